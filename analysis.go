@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/build"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/render"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+)
+
+// analysisFlags are the flags of the commands running an analysis
+type analysisFlags struct {
+	strict      bool
+	ignoreTests bool
+	external    bool
+	vendor      bool
+	replaced    bool
+	generated   bool
+	gitignore   bool
+	configPath  string
+	paths       stringList
+	exclude     stringList
+	include     stringList
+	diff        string
+	modulePath  string
+	tags        string
+	goos        string
+	goarch      string
+	noCache     bool
+	incremental bool
+	typeCheck   bool
+	dip         bool
+	loader      string
+	cacheDir    string
+	logFormat   string
+	timeout     time.Duration
+	rules       string
+	skipRules   string
+	// metrics adds the package design metrics to the report, it is set by the commands showing them
+	metrics bool
+	// suggestInternal warns about packages that fit in an internal directory
+	suggestInternal bool
+	// testGraph checks the imports of test files as a separate graph with their own rules
+	testGraph bool
+	// packages limits the reported violations to the imports of these packages when set, check reads them
+	// with -stdin
+	packages []string
+}
+
+func (f *analysisFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.strict, "strict", false, "do strict checking, do not allow same level imports")
+	fs.BoolVar(&f.ignoreTests, "ignore-tests", false, "ignore imports of test files")
+	fs.BoolVar(&f.external, "external", false, "include third-party modules as pseudo packages on the outermost level")
+	fs.BoolVar(&f.vendor, "with-vendor", false, "include the imported packages of the vendor directory as external packages on the outermost level")
+	fs.BoolVar(&f.replaced, "replaced", false, "analyze the modules replaced by local directories in go.mod as packages of the project")
+	fs.BoolVar(&f.generated, "include-generated", false, "analyze the imports of generated files (\"// Code generated ... DO NOT EDIT.\")")
+	fs.BoolVar(&f.gitignore, "gitignore", false, "leave out the paths ignored by .gitignore files")
+	fs.Var(&f.paths, "path", "directory of the analyzed project (default the working directory), a directory inside a module analyzes the packages below it, can be repeated by check")
+	fs.StringVar(&f.configPath, "config", "", "path to the config file (default "+checker.DefaultConfigFile+" in the project root)")
+	fs.Var(&f.exclude, "exclude", "exclude packages matching an import path glob, can be repeated (ex. */mocks)")
+	fs.Var(&f.include, "include", "only analyze packages matching an import path glob, can be repeated (ex. internal/billing/...)")
+	fs.StringVar(&f.modulePath, "module-path", "", "import path of the project root, for projects without go.mod (inferred from GOPATH by default)")
+	fs.StringVar(&f.tags, "tags", "", "comma-separated build tags, files excluded by build constraints are ignored")
+	fs.StringVar(&f.goos, "goos", "", "target operating system for build constraints (default all files)")
+	fs.StringVar(&f.goarch, "goarch", "", "target architecture for build constraints (default all files)")
+	fs.BoolVar(&f.noCache, "no-cache", false, "do not use the parse cache")
+	fs.StringVar(&f.loader, "loader", checker.LoaderWalk, "how the project files are collected: "+checker.LoaderWalk+" (walk the directory) or "+checker.LoaderPackages+" (go/packages, needs the go command)")
+	fs.BoolVar(&f.incremental, "incremental", false, "reuse the packages mapped by the previous run whose files did not change")
+	fs.StringVar(&f.cacheDir, "cache-dir", "", "directory of the parse cache (default uncle-bob in the user cache directory)")
+	fs.BoolVar(&f.typeCheck, "type-check", false, "type check the project to report exported APIs exposing types of outer levels (needs the go command)")
+	fs.BoolVar(&f.dip, "dip", false, "type check the project to report packages using only concrete types of inner packages, no interface (needs the go command)")
+	fs.BoolVar(&f.suggestInternal, "suggest-internal", false, "warn about packages outside of internal directories only imported by internal packages or from one subtree")
+	fs.BoolVar(&f.testGraph, "test-graph", false, "check the imports of test files as a separate graph: tests may import inner levels, testRules apply instead of rules")
+	fs.StringVar(&f.logFormat, "log-format", "text", "format of the log messages: text (colored) or json (structured, for log aggregation)")
+	fs.DurationVar(&f.timeout, "timeout", 0, "stop the analysis when it takes longer (ex. 30s, 5m), no limit by default")
+	fs.StringVar(&f.rules, "rules", "", "comma-separated codes or names of the only rules to report (ex. UB001,UB006)")
+	fs.StringVar(&f.skipRules, "skip-rules", "", "comma-separated codes or names of rules not to report (ex. UB004)")
+	fs.StringVar(&f.diff, "diff", "", "only report violations introduced by files changed since the merge base with a git ref (ex. origin/main)")
+}
+
+// validate checks the flag values, invalid values are configuration errors.
+// It switches clog to structured JSON messages for -log-format=json.
+func (f *analysisFlags) validate() error {
+	switch f.logFormat {
+	case "text":
+	case "json":
+		clog.SetHandler(func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, nil) })
+	default:
+		return fmt.Errorf("unknown log format %q, use text or json", f.logFormat)
+	}
+
+	if f.loader != checker.LoaderWalk && f.loader != checker.LoaderPackages {
+		return fmt.Errorf("unknown loader %q, use %v or %v", f.loader, checker.LoaderWalk, checker.LoaderPackages)
+	}
+
+	if f.testGraph && f.ignoreTests {
+		return fmt.Errorf("-test-graph checks the imports of test files, it cannot be combined with -ignore-tests")
+	}
+
+	if f.timeout < 0 {
+		return fmt.Errorf("-timeout can not be negative")
+	}
+
+	for _, rule := range append(splitList(f.rules), splitList(f.skipRules)...) {
+		if _, ok := checker.LookupViolationRule(rule); !ok {
+			return fmt.Errorf("unknown rule %q, use the code or the name of a rule (ex. UB001 or same-level)", rule)
+		}
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated flag value, leaving out empty elements
+func splitList(value string) []string {
+	var list []string
+
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+
+	return list
+}
+
+// buildContext returns the build context matching the -tags, -goos and -goarch flags,
+// nil when none is set so that all files are analyzed
+func (f *analysisFlags) buildContext() *build.Context {
+	if f.tags == "" && f.goos == "" && f.goarch == "" {
+		return nil
+	}
+
+	ctx := build.Default
+
+	if f.goos != "" {
+		ctx.GOOS = f.goos
+	}
+
+	if f.goarch != "" {
+		ctx.GOARCH = f.goarch
+	}
+
+	for _, tag := range strings.Split(f.tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			ctx.BuildTags = append(ctx.BuildTags, tag)
+		}
+	}
+
+	return &ctx
+}
+
+// mapOptions returns the options of checker.Map, combining the flags and the config file
+func (f *analysisFlags) mapOptions(cfg checker.Config) checker.MapOptions {
+	opts := cfg.MapOptions()
+
+	opts.IgnoreTests = f.ignoreTests
+	opts.SeparateTests = f.testGraph
+	opts.External = f.external
+	opts.Vendor = f.vendor
+	opts.Replaced = f.replaced
+	opts.IncludeGenerated = f.generated || opts.IncludeGenerated
+	opts.GitIgnore = f.gitignore || opts.GitIgnore
+	opts.Incremental = f.incremental
+	opts.Loader = f.loader
+	opts.BuildContext = f.buildContext()
+	opts.Exclude = append(append([]string{}, cfg.Exclude...), f.exclude...)
+	opts.Include = append(append([]string{}, cfg.Include...), f.include...)
+
+	return opts
+}
+
+// targetDir returns the absolute directory of -path, the working directory by default. Only check analyzes
+// several targets, the other commands fail with more than one -path.
+func (f *analysisFlags) targetDir() string {
+	switch len(f.paths) {
+	case 0:
+		return workingDir()
+	case 1:
+	default:
+		clog.Error("-path can only be given once, check analyzes several targets")
+		exit(exitConfigError)
+	}
+
+	dir, err := filepath.Abs(f.paths[0])
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	// a missing target is a failed analysis, not a misconfigured tool
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		clog.Error(fmt.Sprintf("-path %v is not a directory", f.paths[0]))
+		exit(exitAnalysisError)
+	}
+
+	return dir
+}
+
+// withTimeout returns a context derived from parent that is canceled after -timeout, when set
+func (f *analysisFlags) withTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	if f.timeout == 0 {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithTimeoutCause(parent, f.timeout, fmt.Errorf("the analysis did not finish within -timeout %v", f.timeout))
+}
+
+// analysisError returns the cause of the cancellation of ctx, such as an expired -timeout, instead of err
+func analysisError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	return err
+}
+
+// setupCache points the parse cache to -cache-dir or the default cache directory, unless -no-cache is set
+func (f *analysisFlags) setupCache() {
+	checker.CacheDir = ""
+
+	if f.noCache {
+		return
+	}
+
+	if f.cacheDir != "" {
+		checker.CacheDir = f.cacheDir
+		return
+	}
+
+	if dir, err := checker.DefaultCacheDir(); err == nil {
+		checker.CacheDir = dir
+	}
+}
+
+// assignLevels assigns the packages to the declared layers, or to levels by their imports when there are none
+func assignLevels(ctx context.Context, packageMap map[string]checker.PackageInfo, cfg checker.Config) ([][]string, []string, error) {
+	if len(cfg.Layers) > 0 {
+		packageLevels, layerNames, results := checker.AssignLayers(packageMap, cfg.Layers)
+		render.Results(results)
+
+		return packageLevels, layerNames, nil
+	}
+
+	packageLevels, err := checker.SetUniqueLevels(ctx, packageMap)
+
+	return packageLevels, nil, analysisError(ctx, err)
+}
+
+// mapModule maps the project in workDir for the commands working on the import graph only
+func mapModule(ctx context.Context, workDir string, opts checker.MapOptions) (map[string]checker.PackageInfo, error) {
+	packageMap, _, err := checker.Map(ctx, workDir, opts)
+
+	return packageMap, analysisError(ctx, err)
+}
+
+// analyze maps the project in workDir, assigns the levels and runs the checks, it stops when ctx is done
+func analyze(ctx context.Context, workDir string, cfg checker.Config, f *analysisFlags) (report.Report, error) {
+	r, _, err := analyzePackages(ctx, workDir, cfg, f)
+
+	return r, err
+}
+
+// analyzePackages analyzes the project like analyze, and also returns the mapped packages with their levels
+func analyzePackages(ctx context.Context, workDir string, cfg checker.Config, f *analysisFlags) (report.Report, map[string]checker.PackageInfo, error) {
+	f.setupCache()
+
+	var changedFiles []string
+
+	if f.diff != "" {
+		var err error
+		if changedFiles, err = git.ChangedFiles(workDir, f.diff); err != nil {
+			return report.Report{}, nil, err
+		}
+	}
+
+	mapOptions := f.mapOptions(cfg)
+
+	packageMap, results, err := checker.Map(ctx, workDir, mapOptions)
+	render.Results(results)
+
+	if err != nil {
+		return report.Report{}, nil, analysisError(ctx, err)
+	}
+
+	packageLevels, layerNames, err := assignLevels(ctx, packageMap, cfg)
+	if err != nil {
+		return report.Report{}, nil, err
+	}
+
+	render.Levels(packageLevels, layerNames)
+
+	opts := checker.CheckOptions{
+		Strict:        f.strict,
+		LayerNames:    layerNames,
+		Rules:         cfg.Rules,
+		TestRules:     cfg.TestRules,
+		TestHelpers:   cfg.TestHelpers,
+		Layers:        cfg.Layers,
+		Features:      cfg.Features,
+		Contexts:      cfg.Contexts,
+		Banned:        cfg.Banned,
+		Severity:      cfg.Severity,
+		EnabledRules:  splitList(f.rules),
+		DisabledRules: splitList(f.skipRules),
+		ChangedFiles:  changedFiles,
+		Packages:      f.packages,
+		Unreported:    &checker.Unreported{},
+	}
+
+	violations, err := checker.CheckLevels(ctx, packageMap, packageLevels, opts)
+	if err != nil {
+		return report.Report{}, nil, analysisError(ctx, err)
+	}
+
+	violations = append(violations, checker.CheckRules(packageMap, opts)...)
+	violations = append(violations, checker.CheckCycles(packageMap, opts)...)
+	violations = append(violations, checker.CheckFeatures(packageMap, opts)...)
+	violations = append(violations, checker.CheckContexts(packageMap, opts)...)
+	violations = append(violations, checker.CheckStdlib(packageMap, opts)...)
+	violations = append(violations, checker.CheckThirdParty(packageMap, opts)...)
+	violations = append(violations, checker.CheckLayerDependencies(packageMap, opts)...)
+	violations = append(violations, checker.CheckBanned(packageMap, opts)...)
+	violations = append(violations, checker.CheckTestHelpers(packageMap, opts)...)
+
+	if f.testGraph {
+		violations = append(violations, checker.CheckTests(packageMap, opts)...)
+	}
+
+	if f.typeCheck || f.dip {
+		typed, results, err := checker.LoadTypes(ctx, workDir, mapOptions)
+		if err != nil {
+			return report.Report{}, nil, analysisError(ctx, err)
+		}
+
+		render.Results(results)
+
+		if f.typeCheck {
+			violations = append(violations, checker.CheckTypeLeaks(workDir, packageMap, typed, opts)...)
+		}
+
+		if f.dip {
+			violations = append(violations, checker.CheckDependencyInversion(packageMap, typed, opts)...)
+		}
+	}
+
+	render.Violations(violations)
+
+	drift := checker.CheckDrift(packageMap, opts)
+	render.Drift(drift)
+	render.Results(checker.CheckFan(checker.Fan(packageMap), cfg.MaxFanIn, cfg.MaxFanOut))
+
+	if f.suggestInternal {
+		render.Results(checker.CheckInternalBoundaries(packageMap))
+	}
+
+	r := report.New(packageMap, packageLevels, layerNames, violations)
+	r.Partial = checker.Interrupted()
+	r.Drift = drift
+	r.Unchanged = opts.Unreported.Unchanged
+	r.Untargeted = opts.Unreported.Untargeted
+
+	if f.metrics {
+		r.Metrics = checker.Metrics(workDir, packageMap)
+	}
+
+	return r, packageMap, nil
+}
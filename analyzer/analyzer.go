@@ -0,0 +1,177 @@
+// Package analyzer provides the uncle-bob checks as a go/analysis Analyzer, so they can run under
+// go vet -vettool and other analysis drivers. Violations are reported at the offending import declarations.
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"golang.org/x/tools/go/analysis"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "unclebob",
+	Doc:  "check that packages only import packages of inner levels, following Uncle Bob's clean architecture",
+	URL:  "https://github.com/audi70r/uncle-bob",
+	Run:  run,
+}
+
+var (
+	strict     bool
+	configPath string
+)
+
+func init() {
+	Analyzer.Flags.BoolVar(&strict, "strict", false, "do strict checking, do not allow same level imports")
+	Analyzer.Flags.StringVar(&configPath, "config", "", "path to the config file (default "+checker.DefaultConfigFile+" in the module root)")
+}
+
+// moduleResult holds the violations found in a module, the analysis runs once per module
+type moduleResult struct {
+	violations []checker.Violation
+	err        error
+}
+
+var (
+	// the checker keeps the module path in package state, modules are analyzed one at a time
+	mu      sync.Mutex
+	results = make(map[string]moduleResult)
+)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if len(pass.Files) == 0 {
+		return nil, nil
+	}
+
+	packageDir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+
+	moduleRoot, err := checker.FindModuleRoot(packageDir)
+	if err != nil {
+		// packages outside of a module, like the standard library, are not checked
+		return nil, nil
+	}
+
+	result := analyzeModule(moduleRoot)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	// external test packages are checked as the package they test
+	pkgPath := strings.TrimSuffix(pass.Pkg.Path(), "_test")
+
+	for _, violation := range result.violations {
+		// analysis drivers fail on any diagnostic, violations of lower severities are left out
+		if violation.From != pkgPath || !violation.Fails() {
+			continue
+		}
+
+		for _, file := range pass.Files {
+			for _, spec := range file.Imports {
+				importPath, err := strconv.Unquote(spec.Path.Value)
+
+				if err != nil || (importPath != violation.To && !strings.HasPrefix(importPath, violation.To+"/")) {
+					continue
+				}
+
+				pass.Report(analysis.Diagnostic{
+					Pos:      spec.Pos(),
+					End:      spec.End(),
+					Category: violation.Code,
+					Message:  fmt.Sprintf("%v %v: %v (level %v) imports %v (level %v). %v", violation.Code, violation.Message, violation.From, violation.FromLevel, violation.To, violation.ToLevel, violation.Suggestion),
+				})
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// analyzeModule maps the module at moduleRoot and runs the level and rule checks
+func analyzeModule(moduleRoot string) moduleResult {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if result, ok := results[moduleRoot]; ok {
+		return result
+	}
+
+	var result moduleResult
+
+	result.violations, result.err = checkModule(moduleRoot)
+	results[moduleRoot] = result
+
+	return result
+}
+
+func checkModule(moduleRoot string) ([]checker.Violation, error) {
+	// the checker prints its findings, the analyzer reports them as diagnostics instead
+	clog.SetOutput(io.Discard)
+
+	if err := checker.LocateGoMod(moduleRoot); err != nil {
+		return nil, err
+	}
+
+	path, optional := configPath, configPath == ""
+	if optional {
+		path = filepath.Join(moduleRoot, checker.DefaultConfigFile)
+	}
+
+	cfg, err := checker.LoadConfig(path, optional)
+	if err != nil {
+		return nil, err
+	}
+
+	// analysis passes cannot be canceled, the module is checked to the end
+	ctx := context.Background()
+
+	packageMap, _, err := checker.Map(ctx, moduleRoot, cfg.MapOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	var packageLevels [][]string
+	var layerNames []string
+
+	if len(cfg.Layers) > 0 {
+		packageLevels, layerNames, _ = checker.AssignLayers(packageMap, cfg.Layers)
+	} else {
+		if packageLevels, err = checker.SetUniqueLevels(ctx, packageMap); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := checker.CheckOptions{
+		Strict:      strict,
+		LayerNames:  layerNames,
+		Rules:       cfg.Rules,
+		Layers:      cfg.Layers,
+		Features:    cfg.Features,
+		Contexts:    cfg.Contexts,
+		Banned:      cfg.Banned,
+		TestHelpers: cfg.TestHelpers,
+		Severity:    cfg.Severity,
+	}
+
+	violations, err := checker.CheckLevels(ctx, packageMap, packageLevels, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	violations = append(violations, checker.CheckRules(packageMap, opts)...)
+	violations = append(violations, checker.CheckFeatures(packageMap, opts)...)
+	violations = append(violations, checker.CheckContexts(packageMap, opts)...)
+	violations = append(violations, checker.CheckStdlib(packageMap, opts)...)
+	violations = append(violations, checker.CheckThirdParty(packageMap, opts)...)
+	violations = append(violations, checker.CheckLayerDependencies(packageMap, opts)...)
+	violations = append(violations, checker.CheckBanned(packageMap, opts)...)
+	violations = append(violations, checker.CheckTestHelpers(packageMap, opts)...)
+
+	return append(violations, checker.CheckCycles(packageMap, opts)...), nil
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// writeModule writes the files of a module to a temporary directory
+func writeModule(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// rules returns the rules of the violations
+func rules(violations []checker.Violation) []string {
+	var found []string
+
+	for _, violation := range violations {
+		found = append(found, violation.Rule)
+	}
+
+	return found
+}
+
+func Test_checkModule_layerDependencies(t *testing.T) {
+	layers := `layers:
+  - name: app
+    packages: ["app"]
+    dependsOn: [%v]
+  - name: service
+    packages: ["service"]
+  - name: domain
+    packages: ["domain"]
+`
+
+	tests := []struct {
+		name      string
+		dependsOn string
+		want      int
+	}{
+		{"undeclared", "domain", 1},
+		{"declared", "service, domain", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleRoot := writeModule(t, map[string]string{
+				"go.mod":                  "module example.com/layered\n\ngo 1.22\n",
+				checker.DefaultConfigFile: fmt.Sprintf(layers, tt.dependsOn),
+				"app/app.go":              "package app\n\nimport _ \"example.com/layered/service\"\n",
+				"service/service.go":      "package service\n\nimport _ \"example.com/layered/domain\"\n",
+				"domain/domain.go":        "package domain\n",
+			})
+
+			violations, err := checkModule(moduleRoot)
+			if err != nil {
+				t.Fatalf("checkModule() error = %v", err)
+			}
+
+			undeclared := 0
+
+			for _, violation := range violations {
+				if violation.Rule == checker.RuleUndeclaredDependency {
+					undeclared++
+
+					if violation.From != "example.com/layered/app" || violation.To != "example.com/layered/service" {
+						t.Errorf("violation of %v importing %v, want app importing service", violation.From, violation.To)
+					}
+				}
+			}
+
+			if undeclared != tt.want {
+				t.Errorf("checkModule() = %v, want %v undeclared dependencies", rules(violations), tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkModule_mapOptions(t *testing.T) {
+	files := map[string]string{
+		"go.mod":              "module example.com/mapped\n\ngo 1.22\n",
+		".gitignore":          "scratch/\n",
+		"store/store.go":      "package store\n",
+		"store/store_gen.go":  "// Code generated by stringer. DO NOT EDIT.\n\npackage store\n\nimport _ \"unsafe\"\n",
+		"tools/gen/main.go":   "package main\n\nimport _ \"unsafe\"\n",
+		"scratch/try/main.go": "package main\n\nimport _ \"unsafe\"\n",
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{"defaults", "skipDirs: [tools]\ngitignore: true\n", nil},
+		{"generated files", "includeGenerated: true\nskipDirs: [tools]\ngitignore: true\n", []string{"example.com/mapped/store"}},
+		{"skipped directories", "gitignore: true\n", []string{"example.com/mapped/tools/gen"}},
+		{"git ignored directories", "skipDirs: [tools]\n", []string{"example.com/mapped/scratch/try"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files[checker.DefaultConfigFile] = "banned:\n  - path: unsafe\n" + tt.config
+
+			violations, err := checkModule(writeModule(t, files))
+			if err != nil {
+				t.Fatalf("checkModule() error = %v", err)
+			}
+
+			var banned []string
+
+			for _, violation := range violations {
+				if violation.Rule == checker.RuleBannedImport {
+					banned = append(banned, violation.From)
+				}
+			}
+
+			if !reflect.DeepEqual(banned, tt.want) {
+				t.Errorf("checkModule() banned imports of %v, want %v", banned, tt.want)
+			}
+		})
+	}
+}
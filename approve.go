@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runApprove analyzes the project in the working directory and records its dependencies as the approved snapshot,
+// the following checks fail only on dependencies missing in the snapshot
+func runApprove(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob approve", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob approve [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	snapshotPath := flagSet.String("snapshot", report.DefaultSnapshotFile, "file of the approved snapshot")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if *snapshotPath == "" {
+		clog.Error("-snapshot can not be empty")
+		exit(exitConfigError)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+	*snapshotPath = snapshotFile(*snapshotPath, workDir)
+
+	// the approval is the output, the levels and violations are not printed
+	clog.SetOutput(io.Discard)
+	r := analyzeModule(ctx, workDir, cfg, &af)
+	clog.SetOutput(console)
+
+	// a partial graph would approve the removal of the dependencies that were not mapped
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the snapshot was not written")
+		exit(exitInterrupted)
+	}
+
+	previous, err := report.ReadSnapshot(*snapshotPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	var added, removed []string
+
+	for _, edge := range previous.Unapproved(r) {
+		added = append(added, fmt.Sprintf("%v --> %v", edge.From, edge.To))
+	}
+
+	for _, edge := range previous.Removed(r) {
+		removed = append(removed, fmt.Sprintf("%v --> %v", edge.From, edge.To))
+	}
+
+	if err == nil {
+		printList("Approved dependencies:", added)
+		printList("Removed dependencies:", removed)
+	}
+
+	snapshot := report.NewSnapshot(r)
+
+	if err := report.WriteSnapshot(*snapshotPath, snapshot); err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	clog.Info(fmt.Sprintf("%v dependencies of %v packages approved in %v", len(snapshot.Dependencies), len(r.Packages), *snapshotPath))
+}
+
+// snapshotFile returns the file of -snapshot, the default file is in the project root workDir
+func snapshotFile(path string, workDir string) string {
+	if path == report.DefaultSnapshotFile {
+		return filepath.Join(workDir, filepath.FromSlash(path))
+	}
+
+	return path
+}
+
+// checkApproved fails the check when the report has dependencies missing in the approved snapshot
+func checkApproved(snapshotPath string, snapshot report.Snapshot, r report.Report) {
+	unapproved := snapshot.Unapproved(r)
+
+	for _, edge := range unapproved {
+		clog.Warning(fmt.Sprintf("Dependency not approved: %v --> %v", edge.From, edge.To))
+	}
+
+	if len(unapproved) > 0 {
+		clog.Info("Review the new dependencies and approve them with uncle-bob approve")
+		fmt.Fprintf(console, "%v dependencies not approved in %v, Uncle Bob is Sad :(\n", len(unapproved), snapshotPath)
+		exit(exitViolations)
+	}
+
+	clog.Info("All dependencies approved in " + snapshotPath)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+)
+
+// blameViolations adds the last change of the import lines to the locations of the violations, the locations are
+// relative to dir. Blaming stops with a warning at the first error, outside of a git repository for example.
+func blameViolations(dir string, r *report.Report) {
+	blames := make(map[checker.ImportSite]*checker.Blame)
+
+	for i, violation := range r.Violations {
+		// the locations are shared with the package map and other violations of the import
+		locations := append([]checker.ImportSite{}, violation.Locations...)
+
+		for j, location := range locations {
+			if location.Line == 0 {
+				continue
+			}
+
+			blame, ok := blames[location]
+
+			if !ok {
+				line, err := git.Blame(dir, filepath.FromSlash(location.File), location.Line)
+				if err != nil {
+					clog.Warning("Could not blame the violations: " + err.Error())
+					return
+				}
+
+				blame = &checker.Blame{Commit: line.Commit, Author: line.Author, Email: line.Email, Date: line.Time}
+				blames[location] = blame
+			}
+
+			locations[j].Blame = blame
+		}
+
+		r.Violations[i].Locations = locations
+	}
+}
+
+// printBlame prints the last change of the import lines of the unsuppressed violations
+func printBlame(w io.Writer, r report.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Violation\tLocation\tCommit\tAuthor\tDate")
+
+	for _, violation := range r.Violations {
+		if violation.Suppressed {
+			continue
+		}
+
+		for _, location := range violation.Locations {
+			if location.Blame == nil {
+				continue
+			}
+
+			blame := location.Blame
+			commit := "uncommitted"
+
+			if blame.Commit != "" {
+				commit = blame.Commit[:min(len(blame.Commit), 7)]
+			}
+
+			fmt.Fprintf(tw, "%v [%v]\t%v:%v\t%v\t%v\t%v\n", violation.Code, violation.ID, location.File, location.Line,
+				commit, blame.Author, blame.Date.Local().Format(time.DateOnly))
+		}
+	}
+
+	fmt.Fprintln(tw)
+	tw.Flush()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func Test_printBlame(t *testing.T) {
+	date := time.Date(2024, 3, 4, 12, 0, 0, 0, time.Local)
+
+	r := report.Report{Violations: []checker.Violation{
+		{Code: "UB001", ID: "v1", Locations: []checker.ImportSite{
+			{File: "user/user.go", Line: 4, Blame: &checker.Blame{Commit: "0123456789abcdef", Author: "Ada", Date: date}},
+			{File: "user/repo.go", Line: 5},
+		}},
+		{Code: "UB002", ID: "v2", Locations: []checker.ImportSite{
+			{File: "api/api.go", Line: 3, Blame: &checker.Blame{Author: "Grace", Date: date}},
+		}},
+		{Code: "UB005", ID: "v3", Suppressed: true, Locations: []checker.ImportSite{
+			{File: "db/db.go", Line: 6, Blame: &checker.Blame{Commit: "fedcba9876543210", Author: "Linus", Date: date}},
+		}},
+	}}
+
+	var out strings.Builder
+	printBlame(&out, r)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	want := [][]string{
+		{"Violation", "Location", "Commit", "Author", "Date"},
+		{"UB001", "[v1]", "user/user.go:4", "0123456", "Ada", "2024-03-04"},
+		{"UB002", "[v2]", "api/api.go:3", "uncommitted", "Grace", "2024-03-04"},
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("printBlame() =\n%v\nwant %v lines", out.String(), len(want))
+	}
+
+	for i, line := range lines {
+		if got := strings.Fields(line); strings.Join(got, " ") != strings.Join(want[i], " ") {
+			t.Errorf("line %v = %q, want %q", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,664 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/render"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+	"github.com/audi70r/uncle-bob/visualizer"
+)
+
+// runCheck checks the project in the working directory, the targets given with -path or as arguments, or the
+// module downloaded with -module, this is the default command
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("uncle-bob", flag.ContinueOnError)
+
+	var af analysisFlags
+	af.register(fs)
+
+	fileImports := fs.String("package-imports", "", "show detailed information about package imports, an import path glob shows all matching packages with an import summary")
+	htmlReport := fs.String("html", "", "write a self-contained HTML report to the given file")
+	badge := fs.String("badge", "", "write an SVG status badge to the given file")
+	remoteModule := fs.String("module", "", "download a module from the module proxy of GOPROXY and check it, the latest version unless one is given (ex. github.com/foo/bar@v1.2.3)")
+	stdin := fs.Bool("stdin", false, "only report the violations of the packages read from stdin, one import path, module relative directory or Go file per line")
+	matrix := fs.String("matrix", "", "comma-separated GOOS/GOARCH platforms to analyze one by one (ex. linux/amd64,windows/amd64)")
+	fs.BoolVar(&af.metrics, "metrics", false, "add the package design metrics (instability, abstractness, distance) to the report")
+	recursive := fs.Bool("recursive", false, "analyze every module of the tree, including nested modules with their own go.mod")
+	top := fs.Int("top", report.DefaultTopOffenders, "number of packages ranked as the top offenders, causing and suffering the most violations, 0 ranks all of them")
+	failOnDrift := fs.Bool("fail-on-drift", false, "fail when dependencies declared by dependsOn or allow rules have no matching import")
+	blame := fs.Bool("blame", false, "add the commit, author and date of the last change of the offending import lines to the report (needs git)")
+	webhook := fs.String("notify-webhook", "", "post a notification to this URL when violations are found")
+	notifyFormat := fs.String("notify-format", "json", "payload of the -notify-webhook: json (the report) or slack (a summary for a Slack incoming webhook)")
+	db := fs.String("db", "", "add the packages, levels, edges and violations to this SQLite database as a new run (needs sqlite3)")
+	focus := fs.String("focus", "", "only draw this package and the packages around it in the graph formats, "+strings.Join(graphFormats, ", ")+" (ex. -focus=pkg/foo)")
+	depth := fs.Int("depth", 1, "number of imports away from the -focus package drawn, in both directions")
+	clusterDirs := fs.Bool("cluster-dirs", false, "group the packages of every level of the dot report by top-level directory")
+	treemapColor := fs.String("treemap-color", visualizer.TreemapColorViolations, "coloring of the packages of the treemap format: "+strings.Join(visualizer.TreemapColors, ", "))
+	sourceURL := fs.String("source-url", "", "link the packages and import lines of the HTML report to their source with this URL template, {ref}, {path} and {line} are replaced (ex. https://github.com/org/repo/blob/{ref}/{path}#L{line})")
+	sourceRef := fs.String("source-ref", "", "replaces {ref} in -source-url, the HEAD commit by default")
+	htmlTheme := fs.String("html-theme", visualizer.HTMLThemeLight, "theme of the HTML report: "+strings.Join(visualizer.HTMLThemes, ", "))
+	htmlLogo := fs.String("html-logo", "", "PNG, JPEG, GIF or SVG logo shown in the title of the HTML report, it is embedded in the report")
+	htmlColor := fs.String("html-color", "", "brand color of the titles and links of the HTML report, a hex color (ex. #0b5fff)")
+	imageRenderer := fs.String("image-renderer", visualizer.ImageRendererAuto, "renderer of the svg and png formats: "+strings.Join(visualizer.ImageRenderers, ", ")+", auto uses Graphviz dot when it is installed and the built-in layout otherwise")
+	violationsOnly := fs.Bool("violations-only", false, "only draw the violating imports and their packages in the graph formats, "+strings.Join(graphFormats, ", "))
+	maxViolations := fs.Int("max-violations", 0, "only fail when there are more violations, to ratchet down existing violations")
+	format := fs.String("format", "text", "comma-separated output formats, the report is generated once for all of them: "+strings.Join(outputFormats, ", "))
+	templatePath := fs.String("template", "", "write the report with a Go text/template file executed with the full report (packages, levels, edges, violations, metrics)")
+	history := fs.String("history", report.DefaultHistoryFile, "append the summary of the run to this file for uncle-bob trend, empty to not record it")
+	snapshotPath := fs.String("snapshot", report.DefaultSnapshotFile, "approved snapshot of uncle-bob approve, when the file exists dependencies missing in it fail the check and the violations of the approved ones do not")
+	output := fs.String("o", "", "write the report to this file instead of stdout, with several formats the extension of every format replaces its extension")
+
+	parseFlags(fs, args)
+
+	// the arguments are targets like -path
+	af.paths = append(af.paths, fs.Args()...)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	formats, err := parseFormats(*format)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	// the metrics table is printed when asked for, not for the template
+	showMetrics := af.metrics && contains(formats, "text")
+
+	var tmpl *template.Template
+
+	// the template is an additional format, its report includes the metrics
+	if *templatePath != "" {
+		if tmpl, err = report.ParseTemplate(*templatePath); err != nil {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+
+		formats = append(formats, "template")
+		af.metrics = true
+	}
+
+	written := reportFormats(formats)
+
+	if *output == "" && len(written) > 1 {
+		clog.Error("several output formats can not be written to stdout, name the files with -o")
+		exit(exitConfigError)
+	}
+
+	if *output != "" && len(written) == 0 {
+		clog.Error("the text format is printed on the console, -o needs another output format")
+		exit(exitConfigError)
+	}
+
+	// the report written to stdout is kept apart from the human readable output
+	if *output == "" && len(written) > 0 {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	if *webhook != "" {
+		if err := validateWebhook(*webhook, *notifyFormat); err != nil {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+	}
+
+	if *top < 0 {
+		clog.Error("-top can not be negative")
+		exit(exitConfigError)
+	}
+
+	if !contains(visualizer.TreemapColors, *treemapColor) {
+		clog.Error(fmt.Sprintf("unknown -treemap-color %q, use one of: %v", *treemapColor, strings.Join(visualizer.TreemapColors, ", ")))
+		exit(exitConfigError)
+	}
+
+	if err := (visualizer.ImageOptions{Renderer: *imageRenderer}).Validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if *sourceURL != "" {
+		if err := validateSourceURL(*sourceURL); err != nil {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+	}
+
+	if *depth < 0 {
+		clog.Error("-depth can not be negative")
+		exit(exitConfigError)
+	}
+
+	if *maxViolations < 0 {
+		clog.Error("-max-violations can not be negative")
+		exit(exitConfigError)
+	}
+
+	if *matrix != "" && *recursive {
+		clog.Error("-matrix can not be used with -recursive")
+		exit(exitConfigError)
+	}
+
+	if len(af.paths) > 1 && (*recursive || *matrix != "" || *fileImports != "") {
+		clog.Error("several targets can not be used with -recursive, -matrix or -package-imports")
+		exit(exitConfigError)
+	}
+
+	if *remoteModule != "" {
+		if len(af.paths) > 0 || af.diff != "" || *blame || *fileImports != "" {
+			clog.Error("-module can not be used with -path, arguments, -diff, -blame or -package-imports")
+			exit(exitConfigError)
+		}
+
+		// the history and the snapshot of the working directory are not the ones of the downloaded module
+		explicit := make(map[string]bool)
+		fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+		if !explicit["history"] {
+			*history = ""
+		}
+
+		if !explicit["snapshot"] {
+			*snapshotPath = ""
+		}
+	}
+
+	if *stdin {
+		if af.packages, err = readPackageList(os.Stdin); err != nil {
+			clog.Error("Could not read the packages from stdin: " + err.Error())
+			exit(exitConfigError)
+		}
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	// the downloaded module is checked as the target, its files are removed after the analysis
+	var tmpDir string
+
+	if *remoteModule != "" {
+		var moduleDir, version string
+
+		tmpDir, moduleDir, version = downloadModule(ctx, *remoteModule)
+		af.paths = stringList{moduleDir}
+
+		if *sourceRef == "" {
+			*sourceRef = version
+		}
+	}
+
+	var r report.Report
+
+	// the config of the module, the modules of -recursive and the targets each have their own
+	var cfg checker.Config
+
+	// the root of the analyzed tree, the locations of the report are relative to it
+	var workDir string
+
+	switch {
+	case len(af.paths) > 1:
+		workDir = workingDir()
+		r = analyzeTargets(ctx, workDir, &af)
+	case *recursive:
+		workDir = af.targetDir()
+		r = analyzeModules(ctx, workDir, &af)
+	default:
+		workDir = locateProject(&af)
+
+		cfg = loadConfig(workDir, af.configPath)
+
+		if *fileImports != "" {
+			displayPackageInfo(workDir, *fileImports, af.ignoreTests)
+			return
+		}
+
+		if *matrix != "" {
+			r = analyzeMatrix(ctx, workDir, cfg, &af, strings.Split(*matrix, ","))
+		} else {
+			r = analyzeModule(ctx, workDir, cfg, &af)
+		}
+	}
+
+	if tmpDir != "" {
+		os.RemoveAll(tmpDir)
+	}
+
+	var snapshot *report.Snapshot
+
+	if *snapshotPath != "" {
+		*snapshotPath = snapshotFile(*snapshotPath, workDir)
+
+		approved, err := report.ReadSnapshot(*snapshotPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+
+		if err == nil {
+			snapshot = &approved
+		}
+	}
+
+	if *blame {
+		blameViolations(workDir, &r)
+	}
+
+	summary := report.Summarize(r, *top)
+	r.Summary = &summary
+
+	// the graph formats draw the neighbourhood of the focused package or the violations, the other formats keep
+	// the whole report
+	graph := r
+
+	if *focus != "" {
+		if graph, err = report.Focus(r, *focus, *depth); err != nil {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+	}
+
+	if *violationsOnly {
+		graph = report.ViolationsOnly(graph)
+	}
+
+	opts := reportOptions{
+		template: tmpl,
+		dot:      visualizer.DotOptions{ClusterDirectories: *clusterDirs},
+		treemap:  visualizer.TreemapOptions{ColorBy: *treemapColor},
+		building: visualizer.BuildingOptions{Floors: cfg.Floors},
+		html:     visualizer.HTMLOptions{Theme: *htmlTheme, Color: *htmlColor, SourceURL: *sourceURL, Ref: *sourceRef},
+		image:    visualizer.ImageOptions{Renderer: *imageRenderer, Dot: visualizer.DotOptions{ClusterDirectories: *clusterDirs}},
+	}
+
+	if *htmlLogo != "" {
+		if opts.html.Logo, err = readLogo(*htmlLogo); err != nil {
+			clog.Error(err.Error())
+			exit(exitConfigError)
+		}
+	}
+
+	if err := opts.html.Validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	// the links point at the analyzed commit, HEAD when it is not known
+	if opts.html.SourceURL != "" && opts.html.Ref == "" {
+		if opts.html.Ref, err = git.Head("."); err != nil {
+			opts.html.Ref = "HEAD"
+		}
+	}
+
+	writeReports(ctx, formats, *output, r, graph, opts)
+
+	if showMetrics {
+		printMetrics(console, r)
+	}
+
+	if *blame && contains(formats, "text") {
+		printBlame(console, r)
+	}
+
+	if contains(formats, "text") {
+		printSummary(console, summary)
+	}
+
+	if *htmlReport != "" {
+		writeReportFile(ctx, *htmlReport, "HTML report", graph, func(ctx context.Context, w io.Writer, r report.Report) error {
+			return visualizer.GenerateHTMLReportWithOptions(ctx, w, r, opts.html)
+		})
+	}
+
+	if *badge != "" {
+		writeReportFile(ctx, *badge, "Badge", r, visualizer.GenerateBadge)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results above are partial")
+		exit(exitInterrupted)
+	}
+
+	// partial results are not recorded, they would distort the trend
+	if *history != "" {
+		recordHistory(*history, r, summary)
+	}
+
+	if *db != "" {
+		writeDatabase(ctx, *db, r)
+	}
+
+	// a notification that can not be delivered does not fail the check
+	if *webhook != "" && summary.Violations > 0 {
+		if err := notifyWebhook(ctx, *webhook, *notifyFormat, r); err != nil {
+			clog.Warning("Could not notify the webhook: " + err.Error())
+		} else {
+			clog.Info("Violations notified to the webhook")
+		}
+	}
+
+	if summary.Suppressed > 0 {
+		fmt.Fprintf(console, "%v violations suppressed by unclebob:ignore comments\n", summary.Suppressed)
+	}
+
+	if summary.Unchanged > 0 {
+		fmt.Fprintf(console, "%v violations outside the files changed since %v not reported\n", summary.Unchanged, af.diff)
+	}
+
+	if summary.Untargeted > 0 {
+		fmt.Fprintf(console, "%v violations of packages not read from stdin not reported\n", summary.Untargeted)
+	}
+
+	if *failOnDrift && len(r.Drift) > 0 {
+		fmt.Fprintf(console, "%v declared dependencies without imports, Uncle Bob is Sad :(\n", len(r.Drift))
+		exit(exitViolations)
+	}
+
+	// the snapshot is an additional gate, the violations of approved dependencies do not fail the check
+	if snapshot != nil {
+		checkApproved(*snapshotPath, *snapshot, r)
+	}
+
+	if failing := failingViolations(r, snapshot); failing > *maxViolations {
+		fmt.Fprintln(console, "Issues detected, Uncle Bob is Sad :(")
+		exit(exitViolations)
+	} else if failing > 0 {
+		fmt.Fprintf(console, "%v violations within the budget of %v, Uncle Bob is Patient :|\n", failing, *maxViolations)
+		return
+	}
+
+	fmt.Fprintln(console, "Well done, Uncle Bob is Proud :)")
+}
+
+// failingViolations counts the violations of the report failing the check, the violations of the dependencies
+// approved in snapshot do not fail it
+func failingViolations(r report.Report, snapshot *report.Snapshot) int {
+	failing := 0
+
+	for _, violation := range r.Violations {
+		if violation.Fails() && (snapshot == nil || !snapshot.Approves(violation)) {
+			failing++
+		}
+	}
+
+	return failing
+}
+
+// printSummary prints the summary of the report: the packages per level, the edges, the violations per rule,
+// the cycles and the top offenders
+func printSummary(w io.Writer, s report.Summary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Summary: %v packages, %v imports, %v cycles\n", s.Packages, s.Edges, s.Cycles)
+
+	for _, level := range s.Levels {
+		name := fmt.Sprintf("Level %v", level.Level)
+
+		if level.Layer != "" {
+			name = fmt.Sprintf("%v (%v)", name, level.Layer)
+		}
+
+		fmt.Fprintf(tw, "  %v\t%v packages\n", name, level.Packages)
+	}
+
+	fmt.Fprintf(tw, "Violations: %v, %v suppressed\n", s.Violations, s.Suppressed)
+
+	for _, rule := range s.Rules {
+		fmt.Fprintf(tw, "  %v %v\t%v\n", rule.Code, rule.Rule, rule.Violations)
+	}
+
+	if s.Drift > 0 {
+		fmt.Fprintf(tw, "Drift: %v declared dependencies without imports\n", s.Drift)
+	}
+
+	if len(s.Offenders) > 0 {
+		fmt.Fprintln(tw, "Top offenders, causing violations by their imports:")
+	}
+
+	for _, offender := range s.Offenders {
+		fmt.Fprintf(tw, "  %v\t%v caused\t%v suffered\n", shortPath(offender.Package), offender.Causes, offender.Suffers)
+	}
+
+	if len(s.Suffering) > 0 {
+		fmt.Fprintln(tw, "Top offenders, suffering violations as imported packages:")
+	}
+
+	for _, offender := range s.Suffering {
+		fmt.Fprintf(tw, "  %v\t%v suffered\t%v caused\n", shortPath(offender.Package), offender.Suffers, offender.Causes)
+	}
+
+	fmt.Fprintln(tw)
+	tw.Flush()
+}
+
+// displayPackageInfo shows the imports of a package, exiting on errors
+func displayPackageInfo(workDir string, packageName string, ignoreTests bool) {
+	pkgs, results := checker.PackageImportDetails(workDir, packageName, ignoreTests)
+
+	render.PackageImports(packageName, pkgs)
+	render.Results(results)
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results above are partial")
+		exit(exitInterrupted)
+	}
+
+	for _, result := range results {
+		if result.IsError() {
+			exit(exitAnalysisError)
+		}
+	}
+}
+
+// analyzeModule analyzes the module in workDir, exiting on errors
+func analyzeModule(ctx context.Context, workDir string, cfg checker.Config, af *analysisFlags) report.Report {
+	r, err := analyze(ctx, workDir, cfg, af)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	return r
+}
+
+// analyzeModules analyzes every module in the tree of root with its own config file, unless -config is given,
+// and aggregates their reports. Violation locations are relative to root.
+func analyzeModules(ctx context.Context, root string, af *analysisFlags) report.Report {
+	moduleDirs, err := checker.FindModules(root)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if len(moduleDirs) == 0 {
+		clog.Error("no go.mod file found in " + root)
+		exit(exitAnalysisError)
+	}
+
+	var reports []report.Report
+
+	for _, dir := range moduleDirs {
+		if checker.Interrupted() {
+			break
+		}
+
+		if err := checker.LocateGoMod(dir); err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
+
+		fmt.Fprintf(console, "Module %v\n\n", checker.ModPath)
+
+		r := analyzeModule(ctx, dir, loadConfig(dir, af.configPath), af)
+
+		relDir, err := filepath.Rel(root, dir)
+		if err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
+
+		r.PrefixLocations(filepath.ToSlash(relDir))
+
+		reports = append(reports, r)
+	}
+
+	return report.Merge(reports)
+}
+
+// analyzeTargets analyzes every -path target in its module with the config file of the module, unless -config is
+// given, and aggregates their reports. Violation locations are relative to root.
+func analyzeTargets(ctx context.Context, root string, af *analysisFlags) report.Report {
+	var reports []report.Report
+
+	for _, target := range af.paths {
+		if checker.Interrupted() {
+			break
+		}
+
+		targetFlags := *af
+		targetFlags.paths = stringList{target}
+		targetFlags.include = append(stringList{}, af.include...)
+
+		fmt.Fprintf(console, "Target %v\n\n", target)
+
+		dir := locateProject(&targetFlags)
+		r := analyzeModule(ctx, dir, loadConfig(dir, af.configPath), &targetFlags)
+
+		relDir, err := filepath.Rel(root, dir)
+		if err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
+
+		r.PrefixLocations(filepath.ToSlash(relDir))
+
+		reports = append(reports, r)
+	}
+
+	return report.Merge(reports)
+}
+
+// analyzeMatrix analyzes the module in workDir for every GOOS/GOARCH platform, the violations not found
+// on all of them are listed as platform specific
+func analyzeMatrix(ctx context.Context, workDir string, cfg checker.Config, af *analysisFlags, platforms []string) report.Report {
+	var reports []report.Report
+
+	for i, platform := range platforms {
+		platform = strings.TrimSpace(platform)
+		platforms[i] = platform
+
+		goos, goarch, ok := strings.Cut(platform, "/")
+		if !ok || goos == "" || goarch == "" {
+			clog.Error(fmt.Sprintf("invalid platform %q, use GOOS/GOARCH (ex. linux/amd64)", platform))
+			exit(exitConfigError)
+		}
+
+		if checker.Interrupted() {
+			break
+		}
+
+		platformFlags := *af
+		platformFlags.goos, platformFlags.goarch = goos, goarch
+
+		fmt.Fprintf(console, "Platform %v\n\n", platform)
+
+		reports = append(reports, analyzeModule(ctx, workDir, cfg, &platformFlags))
+	}
+
+	r := report.Union(reports, platforms)
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed && len(violation.Platforms) < len(reports) {
+			clog.Warning(fmt.Sprintf("Only on %v: %v", strings.Join(violation.Platforms, ", "), violation.String()))
+		}
+	}
+
+	return r
+}
+
+// readPackageList reads the packages of -stdin, one per line: import paths, module relative directories or
+// import path globs. Go files stand for their package, empty lines and # comments are skipped.
+func readPackageList(r io.Reader) ([]string, error) {
+	packages := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = path.Clean(filepath.ToSlash(line))
+
+		if strings.HasSuffix(line, ".go") {
+			line = path.Dir(line)
+		}
+
+		if !contains(packages, line) {
+			packages = append(packages, line)
+		}
+	}
+
+	return packages, scanner.Err()
+}
+
+// validateSourceURL checks the URL template of -source-url
+func validateSourceURL(urlTemplate string) error {
+	u, err := url.Parse(strings.NewReplacer("{ref}", "ref", "{path}", "path", "{line}", "1").Replace(urlTemplate))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("-source-url %q is not an http or https URL", urlTemplate)
+	}
+
+	if !strings.Contains(urlTemplate, "{path}") {
+		return fmt.Errorf("-source-url %q has no {path} placeholder", urlTemplate)
+	}
+
+	return nil
+}
+
+// logoTypes are the media types of the logo files by extension
+var logoTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".svg":  "image/svg+xml",
+}
+
+// readLogo reads the -html-logo file as a data URI, so that the HTML report stays self-contained
+func readLogo(path string) (string, error) {
+	mediaType, ok := logoTypes[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("-html-logo %v is not a PNG, JPEG, GIF or SVG file", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func Test_readPackageList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", []string{}},
+		{"import paths", "example.com/app/user\nexample.com/app/api/...\n", []string{"example.com/app/user", "example.com/app/api/..."}},
+		{"comments and empty lines", "# changed packages\n\n  user  \n\t\n#billing\n", []string{"user"}},
+		{"go files", "user/user.go\nuser/repo.go\nmain.go\n", []string{"user", "."}},
+		{"duplicates", "user\nuser/\n./user\nuser\n", []string{"user"}},
+		{"relative paths", "./user\n./internal/billing/../api\n", []string{"user", "internal/api"}},
+		{"no trailing newline", "user\napi", []string{"user", "api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readPackageList(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("readPackageList() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readPackageList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_failingViolations(t *testing.T) {
+	r := report.Report{Violations: []checker.Violation{
+		{Rule: checker.RuleSameLevel, From: "api", To: "user"},
+		{Rule: checker.RuleSameLevel, From: "user", To: "db"},
+		{Rule: checker.RuleSameLevel, From: "db", To: "user", Severity: checker.SeverityWarning},
+		{Rule: checker.RuleSameLevel, From: "user", To: "api", Suppressed: true},
+	}}
+
+	snapshot := report.Snapshot{Dependencies: []report.ApprovedEdge{{From: "api", To: "user"}}}
+
+	tests := []struct {
+		name     string
+		snapshot *report.Snapshot
+		want     int
+	}{
+		{"without snapshot", nil, 2},
+		{"approved dependency", &snapshot, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failingViolations(r, tt.snapshot); got != tt.want {
+				t.Errorf("failingViolations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_snapshotFile(t *testing.T) {
+	root := filepath.Join("project", "root")
+
+	if got, want := snapshotFile(report.DefaultSnapshotFile, root), filepath.Join(root, ".unclebob", "approved.json"); got != want {
+		t.Errorf("snapshotFile() of the default file = %v, want %v", got, want)
+	}
+
+	if got := snapshotFile("approved.json", root); got != "approved.json" {
+		t.Errorf("snapshotFile() of a given file = %v, want approved.json", got)
+	}
+}
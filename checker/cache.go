@@ -0,0 +1,134 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// CacheDir is the directory of the parse cache, the cache is disabled when empty
+var CacheDir string
+
+// cacheVersion is part of the cache keys, it changes when parsedFile or PackageInfo changes
+const cacheVersion = "5"
+
+// DefaultCacheDir returns the uncle-bob directory in the user cache directory (ex. ~/.cache/uncle-bob)
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "uncle-bob"), nil
+}
+
+// cachedFile is the cache entry of a parsed file
+type cachedFile struct {
+	Imports    []string          `json:"imports"`
+	Lines      map[string]int    `json:"lines"`
+	Layer      string            `json:"layer,omitempty"`
+	Suppressed map[string]string `json:"suppressed,omitempty"`
+	Generated  bool              `json:"generated,omitempty"`
+}
+
+// cacheKey returns the cache key of a file content
+func cacheKey(content []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(cacheVersion))
+	hash.Write(content)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func cachePath(key string) string {
+	return filepath.Join(CacheDir, "parse", key[:2], key+".json")
+}
+
+// loadCachedFile returns the cached parse result of a file content, the cache is best effort so errors are misses
+func loadCachedFile(key string) (parsedFile, bool) {
+	if CacheDir == "" {
+		return parsedFile{}, false
+	}
+
+	data, err := os.ReadFile(cachePath(key))
+	if err != nil {
+		return parsedFile{}, false
+	}
+
+	var cached cachedFile
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return parsedFile{}, false
+	}
+
+	parsed := parsedFile{
+		imports:    cached.Imports,
+		lines:      cached.Lines,
+		layer:      cached.Layer,
+		suppressed: cached.Suppressed,
+		generated:  cached.Generated,
+	}
+
+	if parsed.imports == nil {
+		parsed.imports = []string{}
+	}
+
+	if parsed.lines == nil {
+		parsed.lines = make(map[string]int)
+	}
+
+	if parsed.suppressed == nil {
+		parsed.suppressed = make(map[string]string)
+	}
+
+	return parsed, true
+}
+
+// storeCachedFile caches the parse result of a file content, errors are ignored
+func storeCachedFile(key string, parsed parsedFile) {
+	if CacheDir == "" {
+		return
+	}
+
+	data, err := json.Marshal(cachedFile{
+		Imports:    parsed.imports,
+		Lines:      parsed.lines,
+		Layer:      parsed.layer,
+		Suppressed: parsed.suppressed,
+		Generated:  parsed.generated,
+	})
+	if err != nil {
+		return
+	}
+
+	writeCacheFile(cachePath(key), data)
+}
+
+// writeCacheFile writes a cache file through a temporary file, so that concurrent runs never read a partial file.
+// Errors are ignored, the cache is best effort.
+func writeCacheFile(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(data)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseFile_cached(t *testing.T) {
+	defer func(dir string) { CacheDir = dir }(CacheDir)
+
+	content := []byte("// unclebob:layer=domain\npackage user\n\nimport (\n\t\"fmt\"\n\t\"example.com/app/store\" //unclebob:ignore legacy\n)\n")
+
+	path := filepath.Join(t.TempDir(), "user.go")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	CacheDir = ""
+
+	want, err := parseFile(token.NewFileSet(), path)
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+
+	CacheDir = t.TempDir()
+	entry := cachePath(cacheKey(content))
+
+	if _, err := parseFile(token.NewFileSet(), path); err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+
+	if _, err := os.Stat(entry); err != nil {
+		t.Fatalf("parseFile() did not cache the file: %v", err)
+	}
+
+	cached, ok := loadCachedFile(cacheKey(content))
+	if !ok {
+		t.Fatal("loadCachedFile() of the parsed file missed")
+	}
+
+	// the number of lines is counted from the content, it is not cached
+	cached.loc = want.loc
+
+	if !reflect.DeepEqual(cached, want) {
+		t.Errorf("loadCachedFile() = %+v, want %+v", cached, want)
+	}
+
+	if got, err := parseFile(token.NewFileSet(), path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFile() from the cache = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := os.WriteFile(entry, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := loadCachedFile(cacheKey(content)); ok {
+		t.Error("loadCachedFile() of a corrupt entry hit, want a miss")
+	}
+
+	if got, err := parseFile(token.NewFileSet(), path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFile() with a corrupt entry = %+v, %v, want %+v", got, err, want)
+	}
+}
@@ -1,13 +1,15 @@
 package checker
 
 import (
+	"context"
 	"fmt"
 	"github.com/audi70r/uncle-bob/utilities/clog"
-	"go/parser"
+	"go/build"
 	"go/token"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 type PackageInfo struct {
@@ -15,39 +17,185 @@ type PackageInfo struct {
 	Files   []string
 	Imports []string
 	Level   int
+	// Lines is the number of lines of the package files
+	Lines int
+	// External marks a pseudo package standing for a third-party module
+	External bool
+	// Vendored marks an external package found in the vendor directory
+	Vendored bool
+	// Layer is the name of the declared layer the package belongs to, empty when levels are inferred
+	Layer string
+	// AnnotatedLayer is set by a "// unclebob:layer=<name>" comment in one of the package files
+	AnnotatedLayer string
+	// Suppressed maps the imports exempt from checks to the reason given in their "//unclebob:ignore <reason>"
+	// comments. An import is only suppressed if it is suppressed in every file importing it.
+	Suppressed map[string]string
+	// ImportSites maps every import to the import declarations of the package files
+	ImportSites map[string][]ImportSite
+	// StandardImports maps the imported standard library packages to their import declarations,
+	// they are not part of the graph but checked against the restrictions of the layers
+	StandardImports map[string][]ImportSite
+	// ThirdPartyImports maps the imported third-party packages to their import declarations, whether or not
+	// their modules are part of the graph
+	ThirdPartyImports map[string][]ImportSite
+	// TestImports are the project packages imported by the test files, with MapOptions.SeparateTests.
+	// They form a test graph apart from Imports, checked by CheckTests.
+	TestImports []string
+	// TestImportSites maps the test imports to the import declarations of the test files
+	TestImportSites map[string][]ImportSite
 }
 
-var UncleBobIsSad bool
+// ImportSite is the location of an import declaration
+type ImportSite struct {
+	// File is relative to the project root, with forward slashes
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Blame is the last change of the import line, with -blame
+	Blame *Blame `json:"blame,omitempty"`
+}
 
-// check if a package imports another package of a higher of similar level and throw a error result
-func CheckLevels(packageMap map[string]PackageInfo, packageLevels [][]string, strict bool) {
-	var results []clog.CheckResult
+// Blame is the commit that last changed a line, found by git blame
+type Blame struct {
+	// Commit is empty for changes that are not committed yet
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Email  string    `json:"email,omitempty"`
+	Date   time.Time `json:"date"`
+}
+
+func (packageInfo *PackageInfo) addImportSite(pkgImport string, site ImportSite) {
+	packageInfo.ImportSites = appendImportSite(packageInfo.ImportSites, pkgImport, site)
+}
+
+// appendImportSite adds an import declaration of pkgImport to sites, creating the map if needed
+func appendImportSite(sites map[string][]ImportSite, pkgImport string, site ImportSite) map[string][]ImportSite {
+	if sites == nil {
+		sites = make(map[string][]ImportSite)
+	}
+
+	sites[pkgImport] = append(sites[pkgImport], site)
+
+	return sites
+}
+
+// MapOptions control which files and imports are collected by Map
+type MapOptions struct {
+	IgnoreTests bool
+	// External adds the third-party modules required by go.mod as pseudo packages
+	External bool
+	// SeparateTests maps the project imports of test files to TestImports instead of Imports, so that the
+	// levels only depend on the production code
+	SeparateTests bool
+	// Vendor adds the imported packages of the vendor directory as external packages
+	Vendor bool
+	// Replaced maps the packages of the modules replaced by local directories in go.mod as packages of the
+	// project, so that the imports of and by them are checked like internal imports
+	Replaced bool
+	// IncludeGenerated analyzes the imports of generated files, they are ignored by default
+	IncludeGenerated bool
+	// SkipDirs are directory names or module relative paths not walked, in addition to DefaultSkipDirs
+	SkipDirs []string
+	// GitIgnore leaves out the paths ignored by .gitignore files, IgnoreFile files are always honored
+	GitIgnore bool
+	// Loader collects the files of the project, LoaderWalk when empty
+	Loader string
+	// Incremental reuses the directories mapped by the previous run with the same options, if their files
+	// did not change. The map snapshots are kept in CacheDir.
+	Incremental bool
+	// BuildContext excludes the files not matching its build constraints (GOOS, GOARCH and tags), nil includes all files
+	BuildContext *build.Context
+	// Exclude lists import path globs of packages that are left out of the map, including imports of them
+	Exclude []string
+	// Include limits the map to the project packages matching one of the import path globs
+	Include []string
+}
+
+// skipPackage reports whether a project package is left out of the map by the include and exclude patterns
+func (opts MapOptions) skipPackage(importPath string) bool {
+	if len(opts.Include) > 0 && !matchAnyPackagePattern(opts.Include, importPath) {
+		return true
+	}
+
+	return matchAnyPackagePattern(opts.Exclude, importPath)
+}
+
+// isProjectImport reports whether importPath is a package mapped as a package of the project, a package of the
+// module or, with Replaced, of a locally replaced module
+func (opts MapOptions) isProjectImport(importPath string) bool {
+	if isInternalImport(importPath) {
+		return true
+	}
+
+	_, ok := replacementFor(importPath)
+
+	return opts.Replaced && ok
+}
+
+// CheckOptions control the checks done by CheckLevels
+type CheckOptions struct {
+	// Strict only allows importing packages of the next inner level
+	Strict bool
+	// LayerNames holds the declared layer name of every level, nil when levels are inferred
+	LayerNames []string
+	// Rules are consulted to exempt explicitly allowed imports from the level checks
+	Rules []Rule
+	// Layers are the declared layers, their import restrictions are checked by CheckStdlib and CheckThirdParty
+	Layers []Layer
+	// TestRules allow or forbid the imports of the test graph, they replace Rules for the test files
+	TestRules []Rule
+	// TestHelpers are the package name globs of test helpers, DefaultTestHelpers when nil
+	TestHelpers []string
+	// Severity maps rule IDs or codes to their severity, SeverityError by default
+	Severity map[string]string
+	// EnabledRules limits the reported rules to these rule IDs or codes when set, DisabledRules are never reported
+	EnabledRules  []string
+	DisabledRules []string
+	// Banned imports are checked by CheckBanned
+	Banned []BannedImport
+	// Features are checked by CheckFeatures
+	Features Features
+	// Contexts are checked by CheckContexts
+	Contexts []BoundedContext
+	// ChangedFiles are module relative paths, when set only violations with an import in one of them are reported
+	ChangedFiles []string
+	// Packages are import path globs, when set only violations of the imports of matching packages are reported
+	Packages []string
+	// Unreported collects the violations left out by ChangedFiles and Packages, when set
+	Unreported *Unreported
+}
+
+// check if a package imports another package of a higher of similar level and throw a error result.
+// When the levels come from declared layers (opts.LayerNames is set), imports within a layer are allowed
+// and importing a package of an outer layer is reported instead.
+// The check stops with the error of ctx when ctx is done.
+func CheckLevels(ctx context.Context, packageMap map[string]PackageInfo, packageLevels [][]string, opts CheckOptions) ([]Violation, error) {
+	found := newViolations(opts)
+
+	if opts.LayerNames != nil {
+		checkLayers(packageMap, packageLevels, opts, &found)
+	}
+
+	for i := len(packageLevels) - 1; i >= 0 && opts.LayerNames == nil; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	for i := len(packageLevels) - 1; i >= 0; i-- {
 		for _, pkg := range packageLevels[i] {
 			for _, pkgImport := range packageMap[pkg].Imports {
+				if isAllowedByRule(opts.Rules, pkg, pkgImport) {
+					continue
+				}
+
 				for a := i; a >= 0; a-- {
 
-					switch strict {
+					switch opts.Strict {
 					case true:
 						if contains(packageLevels[a], pkgImport) && i-1 != a {
-							errMsg := fmt.Sprintf("%v", "Only one level inward importing is allowed")
-							errMsg = fmt.Sprintf("%v\nLv%v: %v <-- Lv%v: %v \n", errMsg, i, strings.Trim(packageMap[pkg].Path, ModPath), a, strings.Trim(pkgImport, ModPath))
-							if !containsInCheckResults(results, errMsg) {
-								UncleBobIsSad = true
-								results = append(results, clog.NewWarning(errMsg))
-								break
-							}
+							found.add(packageMap, RuleStrictLevel, "Only one level inward importing is allowed", pkg, pkgImport)
 						}
 					default:
 						if contains(packageLevels[a], pkgImport) && i <= a {
-							errMsg := fmt.Sprintf("%v", "Importing a package of the same level is not allowed")
-							errMsg = fmt.Sprintf("%v\nLv%v: %v <-- Lv%v: %v \n", errMsg, i, strings.Trim(packageMap[pkg].Path, ModPath), a, strings.Trim(pkgImport, ModPath))
-							if !containsInCheckResults(results, errMsg) {
-								UncleBobIsSad = true
-								results = append(results, clog.NewWarning(errMsg))
-								break
-							}
+							found.add(packageMap, RuleSameLevel, "Importing a package of the same level is not allowed", pkg, pkgImport)
 						}
 					}
 
@@ -56,91 +204,172 @@ func CheckLevels(packageMap map[string]PackageInfo, packageLevels [][]string, st
 		}
 	}
 
-	for _, v := range results {
-		clog.PrintColorMessage(v)
-	}
+	return found.all(), nil
 }
 
-func LevelsInfo(packageLevels [][]string) {
-	var results []clog.CheckResult
+// checkLayers reports imports of packages of an outer layer. In strict mode only imports
+// of the same layer or of the next inner layer are allowed.
+func checkLayers(packageMap map[string]PackageInfo, packageLevels [][]string, opts CheckOptions, found *violations) {
+	layerNames := opts.LayerNames
 
-	for lvl, packageLevel := range packageLevels {
-		msg := fmt.Sprintf("Level %v packages:\n", lvl)
+	levelOf := make(map[string]int)
 
-		for _, packageImport := range packageLevel {
-			msg = fmt.Sprintf("%v%v \n", msg, packageImport)
+	for level, levelPackages := range packageLevels {
+		for _, pkg := range levelPackages {
+			levelOf[pkg] = level
 		}
-
-		results = append(results, clog.NewInfo(msg))
 	}
 
-	for _, v := range results {
-		clog.PrintColorMessage(v)
+	for i, levelPackages := range packageLevels {
+		for _, pkg := range levelPackages {
+			for _, pkgImport := range packageMap[pkg].Imports {
+				a, ok := levelOf[pkgImport]
+
+				// imports of packages without a layer are not checked
+				if !ok || isAllowedByRule(opts.Rules, pkg, pkgImport) {
+					continue
+				}
+
+				switch {
+				// third-party modules may be imported by the outermost declared layer only
+				case layerNames[a] == ExternalLayer && a == i-1:
+					continue
+				case a < i:
+					found.add(packageMap, RuleOuterLayer, "Importing a package of an outer layer is not allowed", pkg, pkgImport)
+				case opts.Strict && a > i+1:
+					found.add(packageMap, RuleLayerSkip, "Only one layer inward importing is allowed", pkg, pkgImport)
+				}
+			}
+		}
 	}
 }
 
-func DisplayPackageInfo(workdir string, packageName string, ignoreTests bool) []clog.CheckResult {
-	clog.Info("Package: " + packageName)
-	var results []clog.CheckResult
+// FileImports are the imports of a file
+type FileImports struct {
+	File    string   `json:"file"`
+	Imports []string `json:"imports"`
+}
+
+// PackageImports are the imports of the files of a package
+type PackageImports struct {
+	Path  string        `json:"path"`
+	Files []FileImports `json:"files"`
+}
+
+// PackageImportDetails returns the imports of the files of the package packageName and the packages below it,
+// or of every project package matching packageName when it is an import path glob
+func PackageImportDetails(workdir string, packageName string, ignoreTests bool) ([]PackageImports, []clog.CheckResult) {
+	root, pattern := workdir, packageName
+
+	if !IsPackagePattern(packageName) {
+		// get package dir
+		root = strings.Trim(workdir+strings.TrimPrefix(packageName, ModPath), `"`)
+		pattern = ""
+	}
+
+	dirs, dirFiles, results := collectGoFiles(context.Background(), root, walkOptions{ignoreTests: ignoreTests})
 
-	// get package dir
-	packagePath := workdir + strings.TrimPrefix(packageName, ModPath)
-	packagePath = strings.Trim(packagePath, `"`)
+	var pkgs []PackageImports
 
-	filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
-		// log and skip if error is not nil
+	for _, dir := range dirs {
+		if Interrupted() {
+			break
+		}
+
+		relDir, err := filepath.Rel(workdir, dir)
 		if err != nil {
 			results = append(results, clog.NewError(err.Error()))
-			return nil
+			continue
 		}
 
-		// skip directories, non go files and other invalid filenames
-		if info.IsDir() || (len(info.Name()) > 3 && info.Name()[len(info.Name())-3:] != ".go") {
-			return nil
+		packagePath := packagePathForDir(relDir)
+
+		if pattern != "" && !matchPackagePattern(pattern, packagePath) {
+			continue
 		}
 
-		dirString, fileString := filepath.Split(path)
+		files, fileResults := fileImports(dir, dirFiles[dir])
+		results = append(results, fileResults...)
 
-		if strings.Contains(dirString, "/.git/") {
-			return nil
-		}
+		pkgs = append(pkgs, PackageImports{Path: packagePath, Files: files})
+	}
 
-		if ignoreTests && strings.HasSuffix(fileString, "_test.go") {
-			return nil
-		}
+	if pattern != "" && len(pkgs) == 0 {
+		results = append(results, clog.NewError("No package matches "+pattern))
+	}
+
+	return pkgs, results
+}
 
-		msg := fmt.Sprintf("file: %v \n imports: \n", info.Name())
+// IsPackagePattern reports whether a -package-imports name is an import path glob rather than a package
+func IsPackagePattern(packageName string) bool {
+	return strings.ContainsAny(packageName, "*?") || strings.Contains(packageName, "...")
+}
 
-		fileImports, err := getImportsForFile(path)
+// ImportCounts returns the number of packages importing every import of the packages
+func ImportCounts(pkgs []PackageImports) map[string]int {
+	counts := make(map[string]int)
 
-		if err != nil {
-			results = append(results, clog.NewError(err.Error()))
-			return nil
+	for _, pkg := range pkgs {
+		var imports []string
+
+		for _, file := range pkg.Files {
+			for _, fileImport := range file.Imports {
+				imports = AppendStringIfMissing(imports, fileImport)
+			}
 		}
 
-		for _, fileImport := range fileImports {
-			msg = fmt.Sprintf("%v\n<-- %v", msg, fileImport)
+		for _, pkgImport := range imports {
+			counts[pkgImport]++
 		}
+	}
+
+	return counts
+}
+
+// fileImports parses the imports of the files of a directory
+func fileImports(dir string, fileNames []string) ([]FileImports, []clog.CheckResult) {
+	var results []clog.CheckResult
+	var files []FileImports
 
-		msg = fmt.Sprintf("%v \n\n", msg)
+	// files of a directory are parsed with a single FileSet
+	fset := token.NewFileSet()
 
-		results = append(results, clog.NewInfo(msg))
+	for _, fileName := range fileNames {
+		parsed, err := parseFile(fset, filepath.Join(dir, fileName))
 
-		return nil
-	})
+		if err != nil {
+			results = append(results, clog.NewError(err.Error()))
+			continue
+		}
 
-	for _, v := range results {
-		clog.PrintColorMessage(v)
+		files = append(files, FileImports{File: fileName, Imports: parsed.imports})
 	}
 
-	return results
+	return files, results
 }
 
-func SetUniqueLevels(packageMap map[string]PackageInfo) [][]string {
+// SetUniqueLevels assigns every package to the level below the first package importing it, starting with the
+// packages imported by no other package. It stops with the error of ctx when ctx is done, the levels of the
+// packages are only set once all levels are known.
+func SetUniqueLevels(ctx context.Context, packageMap map[string]PackageInfo) ([][]string, error) {
 	var topLevelPackages []string
+	var externalPackages []string
+
+	// loop through all package imports of all packages, in import path order so that the levels are stable
+	for _, path := range sortedPackagePaths(packageMap) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		packageInfo := packageMap[path]
+
+		// external modules are pinned to the outermost level instead of being leveled by imports
+		if packageInfo.External {
+			externalPackages = append(externalPackages, packageInfo.Path)
+			continue
+		}
 
-	// loop through all package imports of all packages
-	for _, packageInfo := range packageMap {
 		packageIsMentionedInImports := false
 
 		// find a package that is not imported by any other packages, usually main
@@ -167,11 +396,20 @@ func SetUniqueLevels(packageMap map[string]PackageInfo) [][]string {
 		if len(packagesByLevel[levelIndex]) == 0 {
 			break
 		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// create another level
 		packagesByLevel = append(packagesByLevel, make([]string, 0, 0))
 		for _, levelPackage := range packagesByLevel[levelIndex] {
 			// find which packages are imported by packages of the current level
 			for _, levelPackageImport := range packageMap[levelPackage].Imports {
+				if packageMap[levelPackageImport].External {
+					continue
+				}
+
 				if !contains(packagesUsed, levelPackageImport) {
 					packagesUsed = append(packagesUsed, levelPackageImport)
 					// send the import to next level
@@ -179,17 +417,41 @@ func SetUniqueLevels(packageMap map[string]PackageInfo) [][]string {
 				}
 			}
 		}
+		sort.Strings(packagesByLevel[levelIndex+1])
 		levelIndex++
 	}
 
-	return packagesByLevel[:len(packagesByLevel)-1]
+	packagesByLevel = packagesByLevel[:len(packagesByLevel)-1]
+
+	if len(externalPackages) > 0 {
+		sort.Strings(externalPackages)
+		packagesByLevel = append([][]string{externalPackages}, packagesByLevel...)
+	}
+
+	for level, levelPackages := range packagesByLevel {
+		for _, path := range levelPackages {
+			if packageInfo, ok := packageMap[path]; ok {
+				packageInfo.Level = level
+				packageMap[path] = packageInfo
+			}
+		}
+	}
+
+	return packagesByLevel, nil
 }
 
-func SetLevels(packageMap map[string]PackageInfo) [][]string {
+// SetLevels groups the packages by the levels of their imports, a package imported from several levels is
+// listed on each of them. It stops with the error of ctx when ctx is done.
+func SetLevels(ctx context.Context, packageMap map[string]PackageInfo) ([][]string, error) {
 	var topLevelPackages []string
 
-	// loop through all package imports of all packages
-	for _, packageInfo := range packageMap {
+	// loop through all package imports of all packages, in import path order so that the levels are stable
+	for _, path := range sortedPackagePaths(packageMap) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		packageInfo := packageMap[path]
 		packageIsMentionedInImports := false
 
 		// find a package that is not imported by any other packages, usually main
@@ -209,6 +471,10 @@ func SetLevels(packageMap map[string]PackageInfo) [][]string {
 	// loop through imports of packages and group them by import level
 	currentPackageLevel := 0
 	for len(packagesByLevel[currentPackageLevel]) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		packagesByLevel = append(packagesByLevel, make([]string, 0, 0))
 		for _, packagePath := range packagesByLevel[currentPackageLevel] {
 
@@ -221,96 +487,319 @@ func SetLevels(packageMap map[string]PackageInfo) [][]string {
 			}
 		}
 
+		sort.Strings(packagesByLevel[currentPackageLevel+1])
+
 		currentPackageLevel++
 	}
 
-	return packagesByLevel
+	return packagesByLevel, nil
+}
+
+// mappedDir is what Map collects from the files of a directory
+type mappedDir struct {
+	Package PackageInfo `json:"package"`
+	// ExternalModules are the third-party modules imported by the package, when MapOptions.External is set
+	ExternalModules []string `json:"externalModules,omitempty"`
+	// VendoredPackages are the vendored packages imported by the package, when MapOptions.Vendor is set
+	VendoredPackages []string `json:"vendoredPackages,omitempty"`
+	// ExternalImports are the imports not belonging to the module, checked for alternate self imports
+	ExternalImports []fileExternalImports `json:"externalImports,omitempty"`
+}
+
+// fileExternalImports lists the imports of a file that do not belong to the module
+type fileExternalImports struct {
+	File    string   `json:"file"`
+	Imports []string `json:"imports"`
 }
 
-func Map(workdir string, ignoreTests bool) (map[string]PackageInfo, []clog.CheckResult) {
+// Map parses the import declarations of the project in workdir and returns its packages by import path.
+// When ctx is done the mapping stops and the packages mapped so far are returned with the error of ctx,
+// after Interrupt they are returned without error.
+func Map(ctx context.Context, workdir string, opts MapOptions) (map[string]PackageInfo, []clog.CheckResult, error) {
 	var results []clog.CheckResult
 
 	PackageMap := make(map[string]PackageInfo)
 
-	filepath.Walk(workdir, func(path string, info os.FileInfo, err error) error {
-		// log and skip if error is not nil
+	var externalImports []fileExternalImports
+
+	// third-party modules imported by the project, mapped as pseudo packages if opts.External is set
+	var externalModules []string
+
+	// imported packages of the vendor directory, mapped as external packages if opts.Vendor is set
+	var vendoredPackages []string
+	vendored := make(map[string]bool)
+
+	// the directories mapped by the previous run, reused if their files did not change
+	var previous, current mapSnapshot
+	if opts.Incremental {
+		previous = loadMapSnapshot(workdir, opts)
+		current.Dirs = make(map[string]snapshotDir)
+	}
+
+	var dirs []string
+	var dirFiles map[string][]string
+	var loadResults []clog.CheckResult
+
+	if opts.Loader == LoaderPackages {
+		dirs, dirFiles, loadResults = loadPackageFiles(ctx, workdir, opts)
+	} else {
+		dirs, dirFiles, loadResults = collectGoFiles(ctx, workdir, walkOptions{ignoreTests: opts.IgnoreTests, skipDirs: opts.SkipDirs, gitignore: opts.GitIgnore})
+	}
+
+	results = append(results, loadResults...)
+
+	if opts.Replaced {
+		replacedDirs, replacedResults := collectReplacedFiles(ctx, workdir, opts, dirFiles)
+		dirs = append(dirs, replacedDirs...)
+		results = append(results, replacedResults...)
+	}
+
+	for _, dir := range dirs {
+		if Interrupted() || ctx.Err() != nil {
+			break
+		}
+
+		relDir, err := filepath.Rel(workdir, dir)
+
 		if err != nil {
 			results = append(results, clog.NewError(err.Error()))
-			return nil
+			continue
 		}
 
-		// skip directories, non go files and other invalid filenames
-		if info.IsDir() || (len(info.Name()) > 3 && info.Name()[len(info.Name())-3:] != ".go") {
-			return nil
+		if opts.skipPackage(packagePathForDir(relDir)) {
+			continue
 		}
 
-		dirString, fileString := filepath.Split(path)
+		var mapped mappedDir
+
+		stamp := dirStamp(dir, dirFiles[dir])
+		snapshot, ok := previous.Dirs[relDir]
+
+		if ok && stamp != "" && snapshot.Stamp == stamp {
+			mapped = snapshot.Mapped
+		} else {
+			var dirResults []clog.CheckResult
+
+			mapped, dirResults = mapDir(workdir, relDir, dirFiles[dir], opts, vendored)
+			results = append(results, dirResults...)
 
-		if strings.Contains(dirString, "/.git/") {
-			return nil
+			// directories with errors are mapped again by the next run, to report the errors again
+			if len(dirResults) > 0 {
+				stamp = ""
+			}
 		}
 
-		if ignoreTests && strings.HasSuffix(fileString, "_test.go") {
-			return nil
+		if opts.Incremental && stamp != "" {
+			current.Dirs[relDir] = snapshotDir{Stamp: stamp, Mapped: mapped}
 		}
 
-		relPath, err := filepath.Rel(workdir, path)
+		externalImports = append(externalImports, mapped.ExternalImports...)
 
-		if err != nil {
-			results = append(results, clog.NewError(err.Error()))
-			return nil
+		for _, modulePath := range mapped.ExternalModules {
+			externalModules = AppendStringIfMissing(externalModules, modulePath)
+		}
+
+		for _, vendoredPackage := range mapped.VendoredPackages {
+			vendoredPackages = AppendStringIfMissing(vendoredPackages, vendoredPackage)
+		}
+
+		if len(mapped.Package.Files) > 0 {
+			PackageMap[mapped.Package.Path] = mapped.Package
+		}
+	}
+
+	if opts.Incremental && !Interrupted() && ctx.Err() == nil {
+		storeMapSnapshot(workdir, opts, current)
+	}
+
+	for _, modulePath := range externalModules {
+		PackageMap[modulePath] = PackageInfo{
+			Path:     modulePath,
+			External: true,
+		}
+	}
+
+	for _, vendoredPackage := range vendoredPackages {
+		PackageMap[vendoredPackage] = PackageInfo{
+			Path:     vendoredPackage,
+			External: true,
+			Vendored: true,
+		}
+	}
+
+	for _, fileImports := range externalImports {
+		for _, fileImport := range fileImports.Imports {
+			if selfPackage, ok := alternateSelfImport(fileImport, PackageMap); ok {
+				warnMsg := fmt.Sprintf("%v imports %v, which looks like the module package %v imported via an alternate path (vanity URL or outdated module path). Such imports are not checked as internal dependencies.\n", fileImports.File, fileImport, selfPackage)
+				results = append(results, clog.NewWarning(warnMsg))
+			}
+		}
+	}
+
+	return PackageMap, results, ctx.Err()
+}
+
+// collectReplacedFiles collects the files of the modules replaced by local directories into dirFiles, it returns
+// the directories not collected yet
+func collectReplacedFiles(ctx context.Context, workdir string, opts MapOptions, dirFiles map[string][]string) ([]string, []clog.CheckResult) {
+	var dirs []string
+	var results []clog.CheckResult
+
+	for _, replace := range ModReplaces {
+		root := filepath.Join(workdir, filepath.FromSlash(replace.Dir))
+
+		if !isModuleRoot(root) {
+			results = append(results, clog.NewWarning(fmt.Sprintf("go.mod replaces %v with %v, which is not a module directory, its packages are not mapped", replace.Path, replace.Dir)))
+			continue
+		}
+
+		replacedDirs, replacedFiles, replacedResults := collectGoFiles(ctx, root, walkOptions{ignoreTests: opts.IgnoreTests, skipDirs: opts.SkipDirs, gitignore: opts.GitIgnore})
+		results = append(results, replacedResults...)
+
+		for _, dir := range replacedDirs {
+			if _, ok := dirFiles[dir]; ok {
+				continue
+			}
+
+			dirFiles[dir] = replacedFiles[dir]
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs, results
+}
+
+// mapDir parses the files of the directory relDir of workdir into a package, vendored caches isVendored results
+func mapDir(workdir string, relDir string, fileNames []string, opts MapOptions, vendored map[string]bool) (mappedDir, []clog.CheckResult) {
+	var results []clog.CheckResult
+	var mapped mappedDir
+
+	dir := filepath.Join(workdir, relDir)
+
+	packageInfo := PackageInfo{
+		Path:  packagePathForDir(relDir),
+		Level: 0,
+	}
+
+	// files of a directory are parsed with a single FileSet
+	fset := token.NewFileSet()
+
+	suppressed := make(map[string]string)
+	unsuppressed := make(map[string]bool)
+
+	for _, fileName := range fileNames {
+		if opts.BuildContext != nil {
+			match, err := opts.BuildContext.MatchFile(dir, fileName)
+
+			if err != nil {
+				results = append(results, clog.NewError(err.Error()))
+				continue
+			}
+
+			if !match {
+				continue
+			}
 		}
 
-		fileImports, err := getImportsForFile(path)
+		parsed, err := parseFile(fset, filepath.Join(dir, fileName))
 
 		if err != nil {
 			results = append(results, clog.NewError(err.Error()))
-			return nil
+			continue
 		}
 
-		packagePath := fmt.Sprintf("%q", ModPath+"/"+filepath.Dir(relPath))
+		packageInfo.Files = append(packageInfo.Files, fileName)
+		packageInfo.Lines += parsed.loc
 
-		if _, ok := PackageMap[packagePath]; ok {
-			packageMapItem := PackageMap[packagePath]
-			packageMapItem.Files = append(packageMapItem.Files, fileString)
-			// add missing imports
-			packageImports := packageMapItem.Imports
+		// generated files keep their package in the map, but their imports can not be fixed by hand
+		if parsed.generated && !opts.IncludeGenerated {
+			continue
+		}
 
-			for _, packageImport := range fileImports {
-				if strings.Index(packageImport, ModPath) > 0 {
-					packageImports = AppendStringIfMissing(packageImports, packageImport)
-				}
+		if parsed.layer != "" {
+			if packageInfo.AnnotatedLayer != "" && packageInfo.AnnotatedLayer != parsed.layer {
+				errMsg := fmt.Sprintf("%v: layer annotation %q conflicts with %q, keeping %q", filepath.Join(relDir, fileName), parsed.layer, packageInfo.AnnotatedLayer, packageInfo.AnnotatedLayer)
+				results = append(results, clog.NewError(errMsg))
+			} else {
+				packageInfo.AnnotatedLayer = parsed.layer
 			}
-
-			packageMapItem.Imports = packageImports
-			PackageMap[packagePath] = packageMapItem
-			return nil
 		}
 
-		packageInfo := PackageInfo{
-			Path:  packagePath,
-			Files: []string{fileString},
-			Level: 0,
+		for _, fileImport := range parsed.imports {
+			if reason, ok := parsed.suppressed[fileImport]; ok {
+				suppressed[fileImport] = reason
+			} else {
+				unsuppressed[fileImport] = true
+			}
 		}
 
-		var packageImports []string
-		for _, packageImport := range fileImports {
-			if strings.Index(packageImport, ModPath) > 0 {
-				packageImports = AppendStringIfMissing(packageImports, packageImport)
+		relFile := filepath.ToSlash(filepath.Join(relDir, fileName))
+
+		var fileExternal []string
+		for _, fileImport := range parsed.imports {
+			site := ImportSite{File: relFile, Line: parsed.lines[fileImport]}
+
+			if opts.isProjectImport(fileImport) {
+				switch {
+				case opts.skipPackage(fileImport):
+				case opts.SeparateTests && strings.HasSuffix(fileName, "_test.go"):
+					// external test packages import the tested package, it is not a dependency
+					if fileImport != packageInfo.Path {
+						packageInfo.TestImports = AppendStringIfMissing(packageInfo.TestImports, fileImport)
+						packageInfo.TestImportSites = appendImportSite(packageInfo.TestImportSites, fileImport, site)
+					}
+				default:
+					packageInfo.Imports = AppendStringIfMissing(packageInfo.Imports, fileImport)
+					packageInfo.addImportSite(fileImport, site)
+				}
+				continue
+			}
+
+			fileExternal = append(fileExternal, fileImport)
+
+			if isStandardImport(fileImport) {
+				packageInfo.StandardImports = appendImportSite(packageInfo.StandardImports, fileImport, site)
+				continue
+			}
+
+			packageInfo.ThirdPartyImports = appendImportSite(packageInfo.ThirdPartyImports, fileImport, site)
+
+			if opts.Vendor && !matchAnyPackagePattern(opts.Exclude, fileImport) && isVendored(workdir, fileImport, vendored) {
+				packageInfo.Imports = AppendStringIfMissing(packageInfo.Imports, fileImport)
+				packageInfo.addImportSite(fileImport, site)
+				mapped.VendoredPackages = AppendStringIfMissing(mapped.VendoredPackages, fileImport)
+				continue
+			}
+
+			if modulePath, ok := requiredModuleFor(fileImport); ok && opts.External && !matchAnyPackagePattern(opts.Exclude, modulePath) {
+				packageInfo.Imports = AppendStringIfMissing(packageInfo.Imports, modulePath)
+				packageInfo.addImportSite(modulePath, site)
+				mapped.ExternalModules = AppendStringIfMissing(mapped.ExternalModules, modulePath)
 			}
 		}
+		mapped.ExternalImports = append(mapped.ExternalImports, fileExternalImports{File: relFile, Imports: fileExternal})
+	}
+
+	for fileImport, reason := range suppressed {
+		if unsuppressed[fileImport] {
+			continue
+		}
 
-		packageInfo.Imports = packageImports
-		PackageMap[packagePath] = packageInfo
+		if packageInfo.Suppressed == nil {
+			packageInfo.Suppressed = make(map[string]string)
+		}
 
-		return nil
-	})
+		packageInfo.Suppressed[fileImport] = reason
 
-	for _, v := range results {
-		clog.PrintColorMessage(v)
+		// suppressed imports of third-party packages apply to their module pseudo package
+		if modulePath, ok := requiredModuleFor(fileImport); ok && !isInternalImport(fileImport) {
+			packageInfo.Suppressed[modulePath] = reason
+		}
 	}
 
-	return PackageMap, results
+	mapped.Package = packageInfo
+
+	return mapped, results
 }
 
 func AppendStringIfMissing(slice []string, i string) []string {
@@ -321,22 +810,3 @@ func AppendStringIfMissing(slice []string, i string) []string {
 	}
 	return append(slice, i)
 }
-
-func getImportsForFile(path string) ([]string, error) {
-	fpath, err := filepath.Abs(path)
-	if err != nil {
-		return nil, err
-	}
-	imports, err := parser.ParseFile(token.NewFileSet(), fpath, nil, parser.ImportsOnly)
-	if err != nil {
-		return nil, err
-	}
-
-	dependencies := make([]string, 0, len(imports.Imports))
-
-	for _, v := range imports.Imports {
-		dependencies = append(dependencies, v.Path.Value)
-	}
-
-	return dependencies, nil
-}
@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeModule writes the files of a module to a temporary directory
+func writeModule(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// fixture is a mapped module with its levels and the check options of its config file
+type fixture struct {
+	dir        string
+	packageMap map[string]PackageInfo
+	levels     [][]string
+	opts       CheckOptions
+}
+
+// mapFixture writes the files of a module, maps it with its config file and assigns the levels like the check does
+func mapFixture(t *testing.T, files map[string]string) fixture {
+	modPath := ModPath
+	t.Cleanup(func() { ModPath = modPath })
+
+	dir := writeModule(t, files)
+
+	cfg, err := LoadConfig(filepath.Join(dir, DefaultConfigFile), true)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if err := LocateModule(dir, ""); err != nil {
+		t.Fatalf("LocateModule() error = %v", err)
+	}
+
+	packageMap, _, err := Map(context.Background(), dir, cfg.MapOptions())
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+
+	f := fixture{
+		dir:        dir,
+		packageMap: packageMap,
+		opts: CheckOptions{
+			Rules:       cfg.Rules,
+			Layers:      cfg.Layers,
+			TestRules:   cfg.TestRules,
+			TestHelpers: cfg.TestHelpers,
+			Features:    cfg.Features,
+			Contexts:    cfg.Contexts,
+			Banned:      cfg.Banned,
+			Severity:    cfg.Severity,
+		},
+	}
+
+	if len(cfg.Layers) > 0 {
+		f.levels, f.opts.LayerNames, _ = AssignLayers(packageMap, cfg.Layers)
+	} else if f.levels, err = SetUniqueLevels(context.Background(), packageMap); err != nil {
+		t.Fatalf("SetUniqueLevels() error = %v", err)
+	}
+
+	return f
+}
+
+// checkLevels runs CheckLevels on the fixture
+func (f fixture) checkLevels(t *testing.T) []Violation {
+	violations, err := CheckLevels(context.Background(), f.packageMap, f.levels, f.opts)
+	if err != nil {
+		t.Fatalf("CheckLevels() error = %v", err)
+	}
+
+	return violations
+}
+
+// imports returns the violations as "rule from -> to" with the import paths relative to the module
+func imports(violations []Violation) []string {
+	var found []string
+
+	for _, violation := range violations {
+		found = append(found, violation.Rule+" "+shortPackagePath(violation.From)+" -> "+shortPackagePath(violation.To))
+	}
+
+	return found
+}
+
+func TestSetUniqueLevels(t *testing.T) {
+	packageMap := map[string]PackageInfo{
+		"m/cmd/b":  {Path: "m/cmd/b", Imports: []string{"m/domain", "m/app"}},
+		"m/cmd/a":  {Path: "m/cmd/a", Imports: []string{"m/app"}},
+		"m/app":    {Path: "m/app", Imports: []string{"m/domain"}},
+		"m/domain": {Path: "m/domain"},
+		"m/lib":    {Path: "m/lib"},
+	}
+
+	want := [][]string{
+		{"m/cmd/a", "m/cmd/b", "m/lib"},
+		{"m/app", "m/domain"},
+	}
+
+	// map iteration order differs between runs, the levels may not
+	for i := 0; i < 10; i++ {
+		got, err := SetUniqueLevels(context.Background(), packageMap)
+		if err != nil {
+			t.Fatalf("SetUniqueLevels() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("SetUniqueLevels() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSetUniqueLevels_canceled(t *testing.T) {
+	packageMap := map[string]PackageInfo{
+		"m/app":    {Path: "m/app", Imports: []string{"m/domain"}},
+		"m/domain": {Path: "m/domain"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SetUniqueLevels(ctx, packageMap); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SetUniqueLevels() error = %v, want %v", err, context.Canceled)
+	}
+
+	if packageMap["m/domain"].Level != 0 {
+		t.Errorf("SetUniqueLevels() set the level of m/domain to %v after cancellation", packageMap["m/domain"].Level)
+	}
+}
+
+func TestMap_incremental(t *testing.T) {
+	defer func(dir string, modPath string) { CacheDir, ModPath = dir, modPath }(CacheDir, ModPath)
+
+	CacheDir = t.TempDir()
+
+	dir := writeModule(t, map[string]string{
+		"go.mod":         "module example.com/incremental\n\ngo 1.22\n",
+		"user/user.go":   "package user\n",
+		"store/store.go": "package store\n",
+	})
+
+	if err := LocateModule(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := MapOptions{Incremental: true}
+
+	first, _, err := Map(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+
+	snapshot := loadMapSnapshot(dir, opts)
+	if _, ok := snapshot.Dirs["user"]; !ok || len(snapshot.Dirs) != 2 {
+		t.Fatalf("Map() stored the directories %v, want store and user", snapshot.Dirs)
+	}
+
+	second, _, err := Map(context.Background(), dir, opts)
+	if err != nil || !reflect.DeepEqual(second, first) {
+		t.Errorf("Map() of an unchanged module = %v, %v, want %v", second, err, first)
+	}
+
+	// the changed directory is mapped again
+	userFile := filepath.Join(dir, "user", "user.go")
+	if err := os.WriteFile(userFile, []byte("package user\n\nimport _ \"example.com/incremental/store\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, _, err := Map(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+
+	if imports := changed["example.com/incremental/user"].Imports; !reflect.DeepEqual(imports, []string{"example.com/incremental/store"}) {
+		t.Errorf("imports of the changed package = %v, want example.com/incremental/store", imports)
+	}
+}
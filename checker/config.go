@@ -0,0 +1,294 @@
+package checker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the config file looked up in the project root
+const DefaultConfigFile = ".unclebob.yaml"
+
+// Config is the uncle-bob configuration file
+type Config struct {
+	// Exclude lists import path globs of packages left out of the analysis
+	Exclude []string `yaml:"exclude"`
+	// Include limits the analysis to the packages matching one of the import path globs
+	Include []string `yaml:"include"`
+	// Preset names the layers of a well known architecture in Presets, instead of declaring Layers
+	Preset string `yaml:"preset"`
+	// Layers declares the architecture layers from the outermost to the innermost
+	Layers []Layer `yaml:"layers"`
+	// Rules explicitly allow or forbid imports, the first matching rule applies
+	Rules []Rule `yaml:"rules"`
+	// TestRules allow or forbid the imports of test files, when they are checked as a separate graph
+	TestRules []Rule `yaml:"testRules"`
+	// TestHelpers are the package name globs of test helpers, which non-test files may not import.
+	// DefaultTestHelpers are used when not set, an empty list disables the check.
+	TestHelpers []string `yaml:"testHelpers"`
+	// Contexts declares the bounded contexts, which may only import the API packages of each other
+	Contexts []BoundedContext `yaml:"contexts"`
+	// Severity maps rule IDs or codes to error, warning or info, only errors fail the check
+	Severity map[string]string `yaml:"severity"`
+	// Banned lists imports forbidden anywhere in the project
+	Banned []BannedImport `yaml:"banned"`
+	// Features declares vertical slices that may not import each other
+	Features Features `yaml:"features"`
+	// IncludeGenerated analyzes the imports of generated files, they are ignored by default
+	IncludeGenerated bool `yaml:"includeGenerated"`
+	// SkipDirs are directory names or paths relative to the project root that are not walked
+	SkipDirs []string `yaml:"skipDirs"`
+	// GitIgnore leaves out the paths ignored by .gitignore files
+	GitIgnore bool `yaml:"gitignore"`
+	// MaxFanIn and MaxFanOut are the numbers of importers and imports of a package above which a warning is
+	// printed, zero disables the warning
+	MaxFanIn  int `yaml:"maxFanIn"`
+	MaxFanOut int `yaml:"maxFanOut"`
+	// Floors maps the layers to the floors of the building format from the ground floor up, instead of a floor
+	// per level
+	Floors []Floor `yaml:"floors"`
+}
+
+// Floor is a floor of the building format holding the packages of its layers
+type Floor struct {
+	// Label names the floor, the names of its layers when empty
+	Label  string   `yaml:"label"`
+	Layers []string `yaml:"layers"`
+}
+
+// Layer maps packages to a named architecture layer
+type Layer struct {
+	Name string `yaml:"name"`
+	// Packages lists import path globs of the packages belonging to the layer
+	Packages []string `yaml:"packages"`
+	// Stdlib restricts the standard library packages the layer may import
+	Stdlib ImportPolicy `yaml:"stdlib"`
+	// ThirdParty restricts the third-party packages the layer may import
+	ThirdParty ImportPolicy `yaml:"thirdParty"`
+	// DependsOn declares the layers the packages of the layer import, next to their own layer. When declared,
+	// imports of other layers are reported, and declared layers without imports are reported as drift.
+	DependsOn []string `yaml:"dependsOn"`
+}
+
+// ImportPolicy restricts imports with import path globs. When Allow is set only the matching imports are
+// allowed, the imports matching Deny are never allowed.
+type ImportPolicy struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// allows reports whether the policy allows importing importPath
+func (policy ImportPolicy) allows(importPath string) bool {
+	if len(policy.Allow) > 0 && !matchAnyPackagePattern(policy.Allow, importPath) {
+		return false
+	}
+
+	return !matchAnyPackagePattern(policy.Deny, importPath)
+}
+
+// LoadConfig reads a YAML config file. If the file does not exist and optional is set, an empty config is returned.
+func LoadConfig(path string, optional bool) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return cfg, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("%v: %w", path, err)
+	}
+
+	if err := cfg.applyPreset(); err != nil {
+		return cfg, fmt.Errorf("%v: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("%v: %w", path, err)
+	}
+
+	if err := cfg.normalizeSeverity(); err != nil {
+		return cfg, fmt.Errorf("%v: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// normalizeSeverity keys the severities by rule ID, rules can be given by ID or by code in any case
+func (cfg *Config) normalizeSeverity() error {
+	if cfg.Severity == nil {
+		return nil
+	}
+
+	severity := make(map[string]string, len(cfg.Severity))
+
+	for key, value := range cfg.Severity {
+		rule, _ := LookupViolationRule(key)
+
+		if _, ok := severity[rule.ID]; ok {
+			return fmt.Errorf("severity of rule %v %v given twice", rule.Code, rule.ID)
+		}
+
+		severity[rule.ID] = value
+	}
+
+	cfg.Severity = severity
+
+	return nil
+}
+
+// applyPreset sets the layers of the preset
+func (cfg *Config) applyPreset() error {
+	if cfg.Preset == "" {
+		return nil
+	}
+
+	layers, ok := Presets[cfg.Preset]
+
+	if !ok {
+		return fmt.Errorf("unknown preset %q, use one of: %v", cfg.Preset, strings.Join(presetNames(), ", "))
+	}
+
+	if len(cfg.Layers) > 0 {
+		return errors.New("preset and layers can not be used together")
+	}
+
+	cfg.Layers = layers
+
+	return nil
+}
+
+// MapOptions returns the options of Map set by the config
+func (cfg Config) MapOptions() MapOptions {
+	return MapOptions{
+		IncludeGenerated: cfg.IncludeGenerated,
+		SkipDirs:         cfg.SkipDirs,
+		GitIgnore:        cfg.GitIgnore,
+		Exclude:          cfg.Exclude,
+		Include:          cfg.Include,
+	}
+}
+
+func (cfg Config) validate() error {
+	layerNames := make(map[string]bool)
+
+	for i, layer := range cfg.Layers {
+		if layer.Name == "" {
+			return fmt.Errorf("layer %v has no name", i)
+		}
+
+		if layer.Name == ExternalLayer {
+			return fmt.Errorf("layer name %q is reserved for third-party modules", ExternalLayer)
+		}
+
+		if layerNames[layer.Name] {
+			return fmt.Errorf("layer %q is declared more than once", layer.Name)
+		}
+
+		layerNames[layer.Name] = true
+	}
+
+	for _, layer := range cfg.Layers {
+		for _, dependency := range layer.DependsOn {
+			if dependency == layer.Name {
+				return fmt.Errorf("layer %q depends on itself, imports within a layer are always allowed", layer.Name)
+			}
+
+			if !layerNames[dependency] {
+				return fmt.Errorf("layer %q depends on the undeclared layer %q", layer.Name, dependency)
+			}
+		}
+	}
+
+	floors := make(map[string]int)
+
+	for i, floor := range cfg.Floors {
+		if len(floor.Layers) == 0 {
+			return fmt.Errorf("floor %v has no layers", i)
+		}
+
+		for _, layer := range floor.Layers {
+			if !layerNames[layer] && layer != ExternalLayer {
+				return fmt.Errorf("floor %v holds the undeclared layer %q", i, layer)
+			}
+
+			if previous, ok := floors[layer]; ok {
+				return fmt.Errorf("layer %q is on floors %v and %v", layer, previous, i)
+			}
+
+			floors[layer] = i
+		}
+	}
+
+	if cfg.MaxFanIn < 0 || cfg.MaxFanOut < 0 {
+		return errors.New("maxFanIn and maxFanOut can not be negative")
+	}
+
+	for rule, severity := range cfg.Severity {
+		if _, ok := LookupViolationRule(rule); !ok {
+			return fmt.Errorf("severity of unknown rule %q", rule)
+		}
+
+		if severity != SeverityError && severity != SeverityWarning && severity != SeverityInfo {
+			return fmt.Errorf("unknown severity %q of rule %q, use %v, %v or %v", severity, rule, SeverityError, SeverityWarning, SeverityInfo)
+		}
+	}
+
+	contextNames := make(map[string]bool)
+
+	for i, context := range cfg.Contexts {
+		if context.Name == "" {
+			return fmt.Errorf("context %v has no name", i)
+		}
+
+		if contextNames[context.Name] {
+			return fmt.Errorf("context %q is declared more than once", context.Name)
+		}
+
+		contextNames[context.Name] = true
+	}
+
+	for i, banned := range cfg.Banned {
+		if banned.Path == "" {
+			return fmt.Errorf("banned import %v has no path", i)
+		}
+	}
+
+	if len(cfg.Features.Shared) > 0 && len(cfg.Features.Roots) == 0 {
+		return errors.New("features declare shared packages but no roots")
+	}
+
+	for i, rule := range cfg.Rules {
+		if rule.From == "" || rule.To == "" {
+			return fmt.Errorf("rule %v needs both from and to patterns", i)
+		}
+	}
+
+	for i, rule := range cfg.TestRules {
+		if rule.From == "" || rule.To == "" {
+			return fmt.Errorf("test rule %v needs both from and to patterns", i)
+		}
+	}
+
+	for _, name := range cfg.TestHelpers {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("invalid test helper pattern %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig_severity(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"rule ID", "severity:\n  same-level: warning\n", map[string]string{RuleSameLevel: SeverityWarning}, false},
+		{"rule code", "severity:\n  UB006: info\n", map[string]string{RuleImportCycle: SeverityInfo}, false},
+		{"lowercase rule code", "severity:\n  ub001: warning\n", map[string]string{RuleSameLevel: SeverityWarning}, false},
+		{"rule given twice", "severity:\n  UB001: warning\n  same-level: info\n", nil, true},
+		{"unknown rule", "severity:\n  UB999: warning\n", nil, true},
+		{"unknown severity", "severity:\n  UB001: fatal\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultConfigFile)
+
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(path, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(cfg.Severity, tt.want) {
+				t.Errorf("LoadConfig() severity = %v, want %v", cfg.Severity, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_severityApplied(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+
+	if err := os.WriteFile(path, []byte("severity:\n  ub001: warning\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path, false)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	found := newViolations(CheckOptions{Severity: cfg.Severity})
+	found.record(map[string]PackageInfo{}, Violation{Rule: RuleSameLevel, From: "example.com/app/user", To: "example.com/app/billing"})
+
+	if violations := found.all(); len(violations) != 1 || violations[0].Severity != SeverityWarning || violations[0].Fails() {
+		t.Errorf("recorded violations = %+v, want a warning", violations)
+	}
+}
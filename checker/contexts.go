@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"fmt"
+)
+
+// BoundedContext is a group of packages of a domain-driven design bounded context, other contexts may only
+// import its API packages
+type BoundedContext struct {
+	Name string `yaml:"name"`
+	// Packages lists import path globs of the packages belonging to the context
+	Packages []string `yaml:"packages"`
+	// API lists import path globs of the packages other contexts may import, like anti-corruption layers
+	// and published interfaces
+	API []string `yaml:"api"`
+}
+
+// contextOf returns the index of the first context with a pattern matching the package import path
+func contextOf(contexts []BoundedContext, importPath string) (int, bool) {
+	for i, context := range contexts {
+		if matchAnyPackagePattern(context.Packages, importPath) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// CheckContexts reports the imports of a package of another bounded context that is not one of its API packages,
+// unless the import is allowed by a rule
+func CheckContexts(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	if len(opts.Contexts) == 0 {
+		return nil
+	}
+
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		from, ok := contextOf(opts.Contexts, pkg)
+
+		if !ok || packageMap[pkg].External {
+			continue
+		}
+
+		for _, pkgImport := range packageMap[pkg].Imports {
+			to, ok := contextOf(opts.Contexts, pkgImport)
+
+			if !ok || to == from || matchAnyPackagePattern(opts.Contexts[to].API, pkgImport) || isAllowedByRule(opts.Rules, pkg, pkgImport) {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Context %v imports %v of context %v, which is not one of its API packages", opts.Contexts[from].Name, shortPackagePath(pkgImport), opts.Contexts[to].Name)
+
+			found.add(packageMap, RuleCrossContext, errMsg, pkg, pkgImport)
+		}
+	}
+
+	return found.all()
+}
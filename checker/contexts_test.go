@@ -0,0 +1,35 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckContexts(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/contexts\n\ngo 1.22\n",
+		DefaultConfigFile: `contexts:
+  - name: billing
+    packages: ["billing/..."]
+    api: [billing/api]
+  - name: shipping
+    packages: ["shipping/..."]
+rules:
+  - from: shipping/legacy
+    to: billing/store
+    allow: true
+`,
+		"billing/api/api.go":        "package api\n\nimport _ \"example.com/contexts/billing/store\"\n",
+		"billing/store/store.go":    "package store\n",
+		"shipping/order/order.go":   "package order\n\nimport (\n\t_ \"example.com/contexts/billing/api\"\n\t_ \"example.com/contexts/billing/store\"\n\t_ \"example.com/contexts/shared\"\n)\n",
+		"shipping/legacy/legacy.go": "package legacy\n\nimport _ \"example.com/contexts/billing/store\"\n",
+		"shared/shared.go":          "package shared\n",
+	})
+
+	// imports within a context, of API packages, of packages without context and allowed by a rule are not reported
+	want := []string{RuleCrossContext + " /shipping/order -> /billing/store"}
+
+	if got := imports(CheckContexts(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckContexts() = %q, want %q", got, want)
+	}
+}
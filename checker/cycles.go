@@ -0,0 +1,177 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StronglyConnected returns the groups of more than one node of graph reaching each other, using Tarjan's
+// strongly connected components algorithm. Nodes are visited in sorted order so that the results are stable.
+func StronglyConnected(graph map[string][]string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range graph[node] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowLink[node] = min(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = min(lowLink[node], index[next])
+			}
+		}
+
+		if lowLink[node] != index[node] {
+			return
+		}
+
+		var component []string
+
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+
+			if last == node {
+				break
+			}
+		}
+
+		if len(component) > 1 {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	nodes := make([]string, 0, len(graph))
+
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+
+	for _, node := range nodes {
+		if _, visited := index[node]; !visited {
+			visit(node)
+		}
+	}
+
+	return components
+}
+
+// shortestCycle returns the shortest cycle through start within the component, starting and ending with start
+func shortestCycle(graph map[string][]string, component []string, start string) []string {
+	inComponent := make(map[string]bool, len(component))
+
+	for _, node := range component {
+		inComponent[node] = true
+	}
+
+	previous := map[string]string{}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range graph[node] {
+			if next == start {
+				cycle := []string{start}
+
+				for back := node; back != start; back = previous[back] {
+					cycle = append(cycle, back)
+				}
+
+				// the path was collected backwards, the first element stays the start
+				for i, j := 1, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+
+				return append(cycle, start)
+			}
+
+			if _, seen := previous[next]; seen || !inComponent[next] || next == start {
+				continue
+			}
+
+			previous[next] = node
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// importGraph returns the imports of every package, without the imports made only by test files,
+// since external test packages may import packages that import the tested package
+func importGraph(packageMap map[string]PackageInfo) map[string][]string {
+	graph := make(map[string][]string, len(packageMap))
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		graph[pkg] = nil
+
+		for _, pkgImport := range packageMap[pkg].Imports {
+			if !isTestOnlyImport(packageMap[pkg], pkgImport) {
+				graph[pkg] = append(graph[pkg], pkgImport)
+			}
+		}
+	}
+
+	return graph
+}
+
+// isTestOnlyImport reports whether all the import declarations of pkgImport are in test files
+func isTestOnlyImport(packageInfo PackageInfo, pkgImport string) bool {
+	sites := packageInfo.ImportSites[pkgImport]
+
+	return len(sites) > 0 && len(nonTestSites(sites)) == 0
+}
+
+// CheckCycles reports a violation for every group of packages importing each other, with the shortest
+// cycle through the first package of the group
+func CheckCycles(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+	graph := importGraph(packageMap)
+
+	for _, component := range StronglyConnected(graph) {
+		cycle := shortestCycle(graph, component, component[0])
+
+		if len(cycle) < 2 {
+			continue
+		}
+
+		shortPaths := make([]string, 0, len(cycle))
+
+		for _, pkg := range cycle {
+			shortPaths = append(shortPaths, shortPackagePath(pkg))
+		}
+
+		errMsg := fmt.Sprintf("Import cycle: %v", strings.Join(shortPaths, " --> "))
+
+		violation := newViolation(packageMap, RuleImportCycle, errMsg, cycle[0], cycle[1])
+		violation.Cycle = cycle
+
+		// the cycle is located at every import along it
+		violation.Locations = nil
+
+		for i := 0; i < len(cycle)-1; i++ {
+			violation.Locations = append(violation.Locations, packageMap[cycle[i]].ImportSites[cycle[i+1]]...)
+		}
+
+		found.record(packageMap, violation)
+	}
+
+	return found.all()
+}
@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_StronglyConnected(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  [][]string
+	}{
+		{
+			name:  "no cycle",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": nil},
+			want:  nil,
+		},
+		{
+			name:  "direct cycle",
+			graph: map[string][]string{"a": {"b"}, "b": {"a"}},
+			want:  [][]string{{"a", "b"}},
+		},
+		{
+			name:  "cycle through other packages",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a", "d"}, "d": nil},
+			want:  [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:  "separate cycles",
+			graph: map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"d"}, "d": {"c"}},
+			want:  [][]string{{"c", "d"}, {"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StronglyConnected(tt.graph); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StronglyConnected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shortestCycle(t *testing.T) {
+	graph := map[string][]string{"a": {"b", "d"}, "b": {"c"}, "c": {"a"}, "d": {"a"}}
+
+	want := []string{"a", "d", "a"}
+
+	if got := shortestCycle(graph, []string{"a", "b", "c", "d"}, "a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("shortestCycle() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckCycles(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod":         "module example.com/cyclic\n\ngo 1.22\n",
+		"user/user.go":   "package user\n\nimport _ \"example.com/cyclic/order\"\n",
+		"order/order.go": "package order\n\nimport _ \"example.com/cyclic/store\"\n",
+		"store/store.go": "package store\n\nimport _ \"example.com/cyclic/user\"\n",
+		"api/api.go":     "package api\n\nimport _ \"example.com/cyclic/db\"\n",
+		"db/db.go":       "package db\n",
+		// the imports of test files do not make cycles
+		"db/db_test.go": "package db\n\nimport _ \"example.com/cyclic/api\"\n",
+	})
+
+	violations := CheckCycles(f.packageMap, f.opts)
+
+	if len(violations) != 1 {
+		t.Fatalf("CheckCycles() = %q, want the cycle of order, store and user", imports(violations))
+	}
+
+	want := []string{"example.com/cyclic/order", "example.com/cyclic/store", "example.com/cyclic/user", "example.com/cyclic/order"}
+
+	if violations[0].Rule != RuleImportCycle || !reflect.DeepEqual(violations[0].Cycle, want) {
+		t.Errorf("CheckCycles() = %v %v, want %v", violations[0].Rule, violations[0].Cycle, want)
+	}
+
+	if len(violations[0].Locations) != 3 {
+		t.Errorf("locations = %v, want the 3 imports of the cycle", violations[0].Locations)
+	}
+}
@@ -0,0 +1,140 @@
+package checker
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// CheckDependencyInversion reports the imports of inner packages of which only concrete types with methods are
+// used, no interface. Such outer packages depend on implementations instead of abstractions, the interface
+// declared by the inner package should be used, or introduced. The packages must be loaded with LoadTypes.
+func CheckDependencyInversion(packageMap map[string]PackageInfo, typed map[string]*packages.Package, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		typedPkg, ok := typed[pkg]
+
+		if !ok || typedPkg.TypesInfo == nil || packageMap[pkg].External {
+			continue
+		}
+
+		used := usedTypes(typedPkg.TypesInfo)
+
+		for _, pkgImport := range sortedImports(packageMap, pkg) {
+			importInfo := packageMap[pkgImport]
+
+			if importInfo.External || importInfo.Level <= packageMap[pkg].Level || isAllowedByRule(opts.Rules, pkg, pkgImport) {
+				continue
+			}
+
+			concrete, usesInterface := classifyUsedTypes(used[pkgImport])
+
+			if usesInterface || len(concrete) == 0 {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Only concrete types of %v are used (%v), introduce an interface", shortPackagePath(pkgImport), strings.Join(concrete, ", "))
+
+			if importTyped, ok := typed[pkgImport]; ok {
+				if interfaces := exportedInterfaces(importTyped.Types); len(interfaces) > 0 {
+					errMsg = fmt.Sprintf("Only concrete types of %v are used (%v), depend on its interfaces (%v)", shortPackagePath(pkgImport), strings.Join(concrete, ", "), strings.Join(interfaces, ", "))
+				}
+			}
+
+			found.add(packageMap, RuleDependencyInversion, errMsg, pkg, pkgImport)
+		}
+	}
+
+	return found.all()
+}
+
+// usedTypes returns the named types used by a package, by package path. The result types of the used
+// functions count, so that a constructor call uses the type it returns.
+func usedTypes(info *types.Info) map[string][]*types.Named {
+	used := make(map[string][]*types.Named)
+	seen := make(map[*types.Named]bool)
+
+	addType := func(t types.Type) {
+		if pointer, ok := types.Unalias(t).(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+
+		named, ok := types.Unalias(t).(*types.Named)
+
+		if !ok || named.Obj().Pkg() == nil || seen[named] {
+			return
+		}
+
+		seen[named] = true
+		used[named.Obj().Pkg().Path()] = append(used[named.Obj().Pkg().Path()], named)
+	}
+
+	for _, object := range info.Uses {
+		switch object := object.(type) {
+		case *types.TypeName:
+			addType(object.Type())
+		case *types.Func:
+			results := object.Type().(*types.Signature).Results()
+
+			for i := 0; i < results.Len(); i++ {
+				addType(results.At(i).Type())
+			}
+		}
+	}
+
+	return used
+}
+
+// classifyUsedTypes returns the names of the struct types with exported methods among the used types,
+// and whether an interface is used
+func classifyUsedTypes(used []*types.Named) ([]string, bool) {
+	var concrete []string
+
+	for _, named := range used {
+		switch named.Underlying().(type) {
+		case *types.Interface:
+			return nil, true
+		case *types.Struct:
+			if hasExportedMethods(named) {
+				concrete = append(concrete, named.Obj().Name())
+			}
+		}
+	}
+
+	sort.Strings(concrete)
+
+	return concrete, false
+}
+
+func hasExportedMethods(named *types.Named) bool {
+	for i := 0; i < named.NumMethods(); i++ {
+		if named.Method(i).Exported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportedInterfaces returns the names of the exported interface types of pkg
+func exportedInterfaces(pkg *types.Package) []string {
+	var interfaces []string
+
+	for _, name := range pkg.Scope().Names() {
+		typeName, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+
+		if !ok || !typeName.Exported() || typeName.IsAlias() {
+			continue
+		}
+
+		if _, ok := typeName.Type().Underlying().(*types.Interface); ok {
+			interfaces = append(interfaces, name)
+		}
+	}
+
+	return interfaces
+}
@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckDependencyInversion(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/inverted\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: app
+    packages: [api, web, cli]
+  - name: domain
+    packages: [user]
+`,
+		"user/user.go": "package user\n\ntype User struct{}\n\ntype Repository interface{ Find() User }\n\ntype Store struct{}\n\nfunc (Store) Find() User { return User{} }\n",
+		// only a concrete type with methods is used
+		"api/api.go": "package api\n\nimport \"example.com/inverted/user\"\n\nvar store user.Store\n\nfunc Find() { store.Find() }\n",
+		// the interface is used
+		"web/web.go": "package web\n\nimport \"example.com/inverted/user\"\n\nvar repository user.Repository = user.Store{}\n",
+		// plain data types need no interface
+		"cli/cli.go": "package cli\n\nimport \"example.com/inverted/user\"\n\nvar current user.User\n",
+	})
+
+	typed, _, err := LoadTypes(context.Background(), f.dir, MapOptions{})
+	if err != nil {
+		t.Fatalf("LoadTypes() error = %v", err)
+	}
+
+	violations := CheckDependencyInversion(f.packageMap, typed, f.opts)
+
+	if want := []string{RuleDependencyInversion + " /api -> /user"}; !reflect.DeepEqual(imports(violations), want) {
+		t.Fatalf("CheckDependencyInversion() = %q, want %q", imports(violations), want)
+	}
+
+	if !strings.Contains(violations[0].Message, "depend on its interfaces (Repository)") {
+		t.Errorf("message = %v, want the interfaces of the imported package", violations[0].Message)
+	}
+}
@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"fmt"
+)
+
+// Kinds of architecture drift
+const (
+	DriftLayer = "layer"
+	DriftRule  = "rule"
+)
+
+// Drift is a dependency declared by the configuration that the code no longer has, the architecture
+// description is out of date
+type Drift struct {
+	Kind string `json:"kind"`
+	// From and To are the layer names of a layer dependency, or the import path globs of an allow rule
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// CheckDrift reports the declared dependencies without a matching import: layers declared in the dependsOn list of
+// a layer that none of its packages imports, and allow rules that match no import
+func CheckDrift(packageMap map[string]PackageInfo, opts CheckOptions) []Drift {
+	var drift []Drift
+
+	layerImports := make(map[[2]string]bool)
+
+	for _, packageInfo := range packageMap {
+		for _, pkgImport := range packageInfo.Imports {
+			layerImports[[2]string{packageInfo.Layer, packageMap[pkgImport].Layer}] = true
+		}
+	}
+
+	for _, layer := range opts.Layers {
+		for _, dependency := range layer.DependsOn {
+			if layerImports[[2]string{layer.Name, dependency}] {
+				continue
+			}
+
+			drift = append(drift, Drift{
+				Kind:    DriftLayer,
+				From:    layer.Name,
+				To:      dependency,
+				Message: fmt.Sprintf("Layer %v declares a dependency on layer %v, none of its packages imports it", layer.Name, dependency),
+			})
+		}
+	}
+
+	for _, rule := range opts.Rules {
+		if rule.Allow && !matchesImport(packageMap, rule) {
+			drift = append(drift, Drift{
+				Kind:    DriftRule,
+				From:    rule.From,
+				To:      rule.To,
+				Message: fmt.Sprintf("Rule allowing imports from: %v to: %v matches no import", rule.From, rule.To),
+			})
+		}
+	}
+
+	return drift
+}
+
+// matchesImport reports whether the rule matches an import of the project
+func matchesImport(packageMap map[string]PackageInfo, rule Rule) bool {
+	for pkg, packageInfo := range packageMap {
+		if !matchPackagePattern(rule.From, pkg) {
+			continue
+		}
+
+		for _, pkgImport := range packageInfo.Imports {
+			if matchPackagePattern(rule.To, pkgImport) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
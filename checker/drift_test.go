@@ -0,0 +1,43 @@
+package checker
+
+import "testing"
+
+func TestCheckDrift(t *testing.T) {
+	ModPath = "github.com/foo/bar"
+
+	packageMap := map[string]PackageInfo{
+		"github.com/foo/bar/infra":  {Path: "github.com/foo/bar/infra", Layer: "infra", Imports: []string{"github.com/foo/bar/domain"}},
+		"github.com/foo/bar/app":    {Path: "github.com/foo/bar/app", Layer: "app"},
+		"github.com/foo/bar/domain": {Path: "github.com/foo/bar/domain", Layer: "domain"},
+	}
+
+	opts := CheckOptions{
+		Layers: []Layer{
+			{Name: "infra", DependsOn: []string{"app", "domain"}},
+			{Name: "app", DependsOn: []string{}},
+			{Name: "domain"},
+		},
+		Rules: []Rule{
+			{From: "infra", To: "domain", Allow: true},
+			{From: "app", To: "domain", Allow: true},
+			{From: "domain", To: "app", Allow: false},
+		},
+	}
+
+	want := []Drift{
+		{Kind: DriftLayer, From: "infra", To: "app"},
+		{Kind: DriftRule, From: "app", To: "domain"},
+	}
+
+	got := CheckDrift(packageMap, opts)
+
+	if len(got) != len(want) {
+		t.Fatalf("CheckDrift() = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i].Kind != want[i].Kind || got[i].From != want[i].From || got[i].To != want[i].To {
+			t.Errorf("CheckDrift()[%v] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
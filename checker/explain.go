@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// violationID returns a short identifier of a violation, stable across runs as long as the rule and the
+// packages of the violation do not change
+func violationID(violation Violation) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v\x00%v\x00%v\x00%v", violation.Rule, violation.From, violation.To, violation.Test)))
+
+	return hex.EncodeToString(sum[:4])
+}
+
+// violationFingerprint returns a stable fingerprint of a violation for baselines and code quality integrations.
+// It only depends on the rule code and the packages relative to the module, the levels, the messages and the
+// order of the violations do not change it, neither does renaming the module.
+func violationFingerprint(violation Violation) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v\x00%v\x00%v\x00%v", violation.Code, shortPackagePath(violation.From), shortPackagePath(violation.To), violation.Test)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// FindViolation returns the violation whose ID starts with id, the prefix has to match a single violation
+func FindViolation(violations []Violation, id string) (Violation, error) {
+	var matches []Violation
+
+	for _, violation := range violations {
+		if id != "" && strings.HasPrefix(violation.ID, id) && !containsViolation(matches, violation) {
+			matches = append(matches, violation)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Violation{}, fmt.Errorf("no violation with id %v", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Violation{}, fmt.Errorf("id %v matches %v violations, give more characters", id, len(matches))
+	}
+}
+
+// ExplainLevel describes why a package is on its level: the layer pattern or annotation assigning it with
+// declared layers, otherwise the chain of imports through which it is first reached from a top level package
+func ExplainLevel(packageMap map[string]PackageInfo, pkg string, layers []Layer) string {
+	packageInfo, ok := packageMap[pkg]
+
+	switch {
+	case !ok:
+		return fmt.Sprintf("%v is a standard library or third-party package, it has no level", shortPackagePath(pkg))
+	case packageInfo.External:
+		return fmt.Sprintf("%v is a third-party module, pinned to the outermost level %v", pkg, packageInfo.Level)
+	case len(layers) > 0:
+		return explainLayer(packageInfo, layers)
+	}
+
+	chain := []string{pkg}
+
+	for current := pkg; ; {
+		importer, ok := outerImporter(packageMap, current)
+		if !ok {
+			break
+		}
+
+		chain = append([]string{importer}, chain...)
+		current = importer
+	}
+
+	if len(chain) > 1 {
+		shortPaths := make([]string, 0, len(chain))
+
+		for _, p := range chain {
+			shortPaths = append(shortPaths, shortPackagePath(p))
+		}
+
+		return fmt.Sprintf("%v is on level %v, it is first reached from the top level package %v through %v",
+			shortPackagePath(pkg), packageInfo.Level, shortPackagePath(chain[0]), strings.Join(shortPaths, " --> "))
+	}
+
+	for _, importer := range sortedPackagePaths(packageMap) {
+		if contains(packageMap[importer].Imports, pkg) {
+			return fmt.Sprintf("%v is on level %v, it is not reached from a top level package, for example because it is part of an import cycle",
+				shortPackagePath(pkg), packageInfo.Level)
+		}
+	}
+
+	return fmt.Sprintf("%v is on level %v, it is a top level package not imported by any other package", shortPackagePath(pkg), packageInfo.Level)
+}
+
+// outerImporter returns the first importer of pkg on the level right outside of it, through which the
+// leveling reached pkg
+func outerImporter(packageMap map[string]PackageInfo, pkg string) (string, bool) {
+	level := packageMap[pkg].Level
+
+	for _, importer := range sortedPackagePaths(packageMap) {
+		importerInfo := packageMap[importer]
+
+		if !importerInfo.External && importerInfo.Level == level-1 && contains(importerInfo.Imports, pkg) {
+			return importer, true
+		}
+	}
+
+	return "", false
+}
+
+// explainLayer describes the annotation or the layer pattern assigning a package to its layer
+func explainLayer(packageInfo PackageInfo, layers []Layer) string {
+	pkg := shortPackagePath(packageInfo.Path)
+
+	if packageInfo.AnnotatedLayer != "" {
+		return fmt.Sprintf("%v is on level %v, it is annotated with the layer %v", pkg, packageInfo.Level, packageInfo.AnnotatedLayer)
+	}
+
+	for _, layer := range layers {
+		for _, pattern := range layer.Packages {
+			if matchPackagePattern(pattern, packageInfo.Path) {
+				return fmt.Sprintf("%v is on level %v, it matches the pattern %v of the layer %v", pkg, packageInfo.Level, pattern, layer.Name)
+			}
+		}
+	}
+
+	return fmt.Sprintf("%v is not assigned to any layer, its imports are not checked", pkg)
+}
+
+// RefactoringOptions returns the refactoring options of the rule of a violation, naming its packages
+func RefactoringOptions(violation Violation) []string {
+	rule, _ := LookupViolationRule(violation.Rule)
+	replacer := strings.NewReplacer("{from}", shortPackagePath(violation.From), "{to}", shortPackagePath(violation.To))
+
+	options := make([]string, 0, len(rule.Options))
+
+	for _, option := range rule.Options {
+		options = append(options, replacer.Replace(option))
+	}
+
+	return options
+}
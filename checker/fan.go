@@ -0,0 +1,64 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// PackageFan counts the importers and the imports of a package
+type PackageFan struct {
+	Path string `json:"path"`
+	// FanIn is the number of project packages importing the package
+	FanIn int `json:"fanIn"`
+	// FanOut is the number of packages imported by the package
+	FanOut int `json:"fanOut"`
+}
+
+// Fan returns the fan-in and fan-out of the project packages of the map, in import path order.
+// Only the imports collected by Map are counted, like for Metrics.
+func Fan(packageMap map[string]PackageInfo) []PackageFan {
+	fanIn := make(map[string]int)
+
+	for _, packageInfo := range packageMap {
+		for _, pkgImport := range packageInfo.Imports {
+			fanIn[pkgImport]++
+		}
+	}
+
+	fans := make([]PackageFan, 0, len(packageMap))
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		if packageMap[pkg].External {
+			continue
+		}
+
+		fans = append(fans, PackageFan{
+			Path:   pkg,
+			FanIn:  fanIn[pkg],
+			FanOut: len(packageMap[pkg].Imports),
+		})
+	}
+
+	return fans
+}
+
+// CheckFan warns about the packages with more importers than maxFanIn or more imports than maxFanOut,
+// a zero threshold is not checked. Excessive coupling is a warning, not a violation.
+func CheckFan(fans []PackageFan, maxFanIn int, maxFanOut int) []clog.CheckResult {
+	var results []clog.CheckResult
+
+	for _, fan := range fans {
+		if maxFanIn > 0 && fan.FanIn > maxFanIn {
+			warnMsg := fmt.Sprintf("%v is imported by %v packages, more than the fan-in threshold of %v", shortPackagePath(fan.Path), fan.FanIn, maxFanIn)
+			results = append(results, clog.NewWarning(warnMsg))
+		}
+
+		if maxFanOut > 0 && fan.FanOut > maxFanOut {
+			warnMsg := fmt.Sprintf("%v imports %v packages, more than the fan-out threshold of %v", shortPackagePath(fan.Path), fan.FanOut, maxFanOut)
+			results = append(results, clog.NewWarning(warnMsg))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFan(t *testing.T) {
+	defer func(modPath string) { ModPath = modPath }(ModPath)
+
+	ModPath = "example.com/app"
+
+	packageMap := map[string]PackageInfo{
+		"example.com/app/api":   {Imports: []string{"example.com/app/user", "example.com/app/store", "github.com/lib/pq"}},
+		"example.com/app/user":  {Imports: []string{"example.com/app/store"}},
+		"example.com/app/store": {},
+		"github.com/lib/pq":     {External: true},
+	}
+
+	fans := Fan(packageMap)
+
+	want := []PackageFan{
+		{Path: "example.com/app/api", FanOut: 3},
+		{Path: "example.com/app/store", FanIn: 2},
+		{Path: "example.com/app/user", FanIn: 1, FanOut: 1},
+	}
+
+	if !reflect.DeepEqual(fans, want) {
+		t.Errorf("Fan() = %v, want %v", fans, want)
+	}
+
+	tests := []struct {
+		name      string
+		maxFanIn  int
+		maxFanOut int
+		want      []string
+	}{
+		{"no thresholds", 0, 0, nil},
+		{"fan-in", 1, 0, []string{"/store is imported by 2 packages"}},
+		{"fan-out", 0, 2, []string{"/api imports 3 packages"}},
+		{"at the thresholds", 2, 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := CheckFan(fans, tt.maxFanIn, tt.maxFanOut)
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("CheckFan() = %v, want %q", results, tt.want)
+			}
+
+			for i, result := range results {
+				if !strings.HasPrefix(result.Message, tt.want[i]) {
+					t.Errorf("CheckFan() = %v, want %q", result.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Features declares the vertical slices of the project, features may not import each other
+type Features struct {
+	// Roots are import path globs, every matching package is the root of a feature including the packages below it
+	Roots []string `yaml:"roots"`
+	// Shared are import path globs of the packages every feature may import
+	Shared []string `yaml:"shared"`
+}
+
+// featureOf returns the root of the feature the package belongs to, the outermost package matching a root pattern
+// among the package and its parents. Packages outside of the features and shared packages have no feature.
+func (features Features) featureOf(importPath string) string {
+	if !isInternalImport(importPath) || matchAnyPackagePattern(features.Shared, importPath) {
+		return ""
+	}
+
+	elems := strings.Split(strings.TrimPrefix(strings.TrimPrefix(importPath, ModPath), "/"), "/")
+
+	for i := 1; i <= len(elems); i++ {
+		root := packagePathForDir(strings.Join(elems[:i], "/"))
+
+		if matchAnyPackagePattern(features.Roots, root) {
+			return root
+		}
+	}
+
+	return ""
+}
+
+// CheckFeatures reports the imports of a package of another feature, unless the imported package is shared
+// or the import is allowed by a rule
+func CheckFeatures(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		feature := opts.Features.featureOf(pkg)
+
+		if feature == "" {
+			continue
+		}
+
+		for _, pkgImport := range packageMap[pkg].Imports {
+			importFeature := opts.Features.featureOf(pkgImport)
+
+			if importFeature == "" || importFeature == feature || isAllowedByRule(opts.Rules, pkg, pkgImport) {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Feature %v imports feature %v", shortPackagePath(feature), shortPackagePath(importFeature))
+
+			found.add(packageMap, RuleCrossFeature, errMsg, pkg, pkgImport)
+		}
+	}
+
+	return found.all()
+}
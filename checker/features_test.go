@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_featureOf(t *testing.T) {
+	ModPath = "github.com/foo/bar"
+
+	features := Features{
+		Roots:  []string{"internal/*"},
+		Shared: []string{"internal/shared/..."},
+	}
+
+	tests := []struct {
+		name       string
+		importPath string
+		want       string
+	}{
+		{
+			name:       "feature root",
+			importPath: "github.com/foo/bar/internal/billing",
+			want:       "github.com/foo/bar/internal/billing",
+		},
+		{
+			name:       "package below a feature root",
+			importPath: "github.com/foo/bar/internal/billing/store",
+			want:       "github.com/foo/bar/internal/billing",
+		},
+		{
+			name:       "shared package",
+			importPath: "github.com/foo/bar/internal/shared/clock",
+			want:       "",
+		},
+		{
+			name:       "package outside of the features",
+			importPath: "github.com/foo/bar/cmd/server",
+			want:       "",
+		},
+		{
+			name:       "third-party package",
+			importPath: "github.com/lib/pq",
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := features.featureOf(tt.importPath); got != tt.want {
+				t.Errorf("featureOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFeatures(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/sliced\n\ngo 1.22\n",
+		DefaultConfigFile: `features:
+  roots: ["internal/*"]
+  shared: ["internal/shared/..."]
+rules:
+  - from: internal/orders/...
+    to: internal/users/api
+    allow: true
+`,
+		"internal/billing/billing.go":     "package billing\n\nimport (\n\t_ \"example.com/sliced/internal/billing/store\"\n\t_ \"example.com/sliced/internal/shared/clock\"\n\t_ \"example.com/sliced/internal/users/store\"\n)\n",
+		"internal/billing/store/store.go": "package store\n",
+		"internal/users/store/store.go":   "package store\n",
+		"internal/users/api/api.go":       "package api\n",
+		"internal/orders/orders.go":       "package orders\n\nimport _ \"example.com/sliced/internal/users/api\"\n",
+		"internal/shared/clock/clock.go":  "package clock\n\nimport _ \"example.com/sliced/internal/users/api\"\n",
+		"cmd/server/main.go":              "package main\n\nimport _ \"example.com/sliced/internal/billing\"\n\nfunc main() {}\n",
+	})
+
+	// imports within a feature, of shared packages, from outside of the features and allowed by a rule are not reported
+	want := []string{RuleCrossFeature + " /internal/billing -> /internal/users/store"}
+
+	if got := imports(CheckFeatures(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckFeatures() = %q, want %q", got, want)
+	}
+}
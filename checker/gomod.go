@@ -1,31 +1,416 @@
 package checker
 
 import (
+	"errors"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/audi70r/uncle-bob/utilities/clog"
 	"golang.org/x/mod/modfile"
-	"os"
+	"golang.org/x/mod/module"
 )
 
 var ModPath string
 
-func LocateGoMod(targetPath string) {
+// ModRequires holds the module paths required by go.mod
+var ModRequires []string
+
+// ModReplaces holds the modules replaced by local directories in go.mod
+var ModReplaces []Replacement
+
+// Replacement is a module replaced by a local directory with a replace directive of go.mod
+type Replacement struct {
+	// Path is the module path of the replaced module
+	Path string
+	// Dir is the directory replacing the module, slash separated and relative to the module root
+	Dir string
+}
+
+// LocateGoMod reads the go.mod file of the target directory and sets ModPath, ModRequires and ModReplaces
+func LocateGoMod(targetPath string) error {
 	var err error
 
-	ModPath, err = getModulePath(targetPath)
+	ModPath, ModRequires, ModReplaces, err = getModulePath(targetPath)
+
+	return err
+}
+
+// LocateModule sets ModPath and ModRequires like LocateGoMod. For projects without go.mod the module path
+// is modulePath, or the import path inferred from the GOPATH layout. A non empty modulePath overrides
+// the module path of go.mod.
+func LocateModule(targetPath string, modulePath string) error {
+	err := LocateGoMod(targetPath)
+
+	if err == nil {
+		if modulePath != "" {
+			ModPath = modulePath
+		}
+
+		return nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	ModRequires = nil
+	ModReplaces = nil
+
+	if modulePath != "" {
+		ModPath = modulePath
+		return nil
+	}
+
+	if ModPath = gopathImportPath(targetPath); ModPath != "" {
+		clog.Warning("No go.mod file found, using the import path " + ModPath + " inferred from GOPATH")
+		return nil
+	}
+
+	return err
+}
+
+// gopathImportPath returns the import path of dir in the GOPATH layout, or an empty string if dir is outside GOPATH
+func gopathImportPath(dir string) string {
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		rel, err := filepath.Rel(filepath.Join(gopath, "src"), dir)
+
+		if err == nil && rel != "." && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return ""
+}
+
+// isVendored reports whether the package importPath is in the vendor directory of the module in workdir,
+// known caches the results
+func isVendored(workdir string, importPath string, known map[string]bool) bool {
+	if isStandardImport(importPath) {
+		return false
+	}
+
+	vendored, ok := known[importPath]
+
+	if !ok {
+		info, err := os.Stat(filepath.Join(workdir, "vendor", filepath.FromSlash(importPath)))
+		vendored = err == nil && info.IsDir()
+		known[importPath] = vendored
+	}
+
+	return vendored
+}
+
+// isModuleRoot reports whether dir contains a go.mod file
+func isModuleRoot(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "go.mod"))
+
+	return err == nil && !info.IsDir()
+}
 
+// FindModuleRoot returns the closest directory containing a go.mod file, starting at dir and walking up the
+// parent directories like the go command
+func FindModuleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		clog.Error(err.Error())
-		clog.Warning("Could not find go.mod file. Please make sure the target directory is correct or that go modules are initiated.")
-		os.Exit(1)
+		return "", err
+	}
+
+	for {
+		if isModuleRoot(dir) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("could not find go.mod in %v or its parent directories: %w", dir, fs.ErrNotExist)
+		}
+
+		dir = parent
 	}
 }
 
-func getModulePath(targetPath string) (string, error) {
-	gomod, modReadErr := os.ReadFile(targetPath + "/go.mod")
+// FindModules returns the directories of the modules in the tree of root, including root if it is a module.
+// Directories are returned in walk order, so that a module comes before the modules nested in it.
+func FindModules(root string) ([]string, error) {
+	var modules []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if isSkippedDir(root, path, nil) {
+			return filepath.SkipDir
+		}
+
+		if isModuleRoot(path) {
+			modules = append(modules, path)
+		}
+
+		return nil
+	})
+
+	return modules, err
+}
+
+func getModulePath(targetPath string) (string, []string, []Replacement, error) {
+	gomodPath := targetPath + "/go.mod"
+	gomod, modReadErr := os.ReadFile(gomodPath)
 
 	if modReadErr != nil {
-		return "", modReadErr
+		return "", nil, nil, modReadErr
+	}
+
+	modFile, modParseErr := modfile.ParseLax(gomodPath, gomod, nil)
+
+	if modParseErr != nil {
+		return "", nil, nil, modParseErr
+	}
+
+	if modFile.Module == nil {
+		return "", nil, nil, fmt.Errorf("%v: no module directive found", gomodPath)
+	}
+
+	requires := make([]string, 0, len(modFile.Require))
+
+	for _, req := range modFile.Require {
+		requires = append(requires, req.Mod.Path)
+	}
+
+	// the lax parser ignores the replace directives, they only apply to the main module
+	var replacements []Replacement
+
+	if mainModFile, err := modfile.Parse(gomodPath, gomod, nil); err == nil {
+		replacements = localReplacements(targetPath, mainModFile.Replace)
+	}
+
+	return modFile.Module.Mod.Path, requires, replacements, nil
+}
+
+// localReplacements returns the modules of the replace directives pointing at local directories, replacements
+// by other module versions are left out
+func localReplacements(targetPath string, replaces []*modfile.Replace) []Replacement {
+	var replacements []Replacement
+
+	for _, replace := range replaces {
+		if replace.New.Version != "" || !modfile.IsDirectoryPath(replace.New.Path) {
+			continue
+		}
+
+		dir := filepath.FromSlash(replace.New.Path)
+
+		if filepath.IsAbs(dir) {
+			root, err := filepath.Abs(targetPath)
+			if err != nil {
+				continue
+			}
+
+			if dir, err = filepath.Rel(root, dir); err != nil {
+				continue
+			}
+		}
+
+		dir = path.Clean(filepath.ToSlash(dir))
+
+		// a module replaced by the project itself is not another module
+		if dir == "." {
+			continue
+		}
+
+		replacements = append(replacements, Replacement{Path: replace.Old.Path, Dir: dir})
+	}
+
+	return replacements
+}
+
+// replacementFor returns the locally replaced module providing importPath, the longest module path wins
+func replacementFor(importPath string) (Replacement, bool) {
+	var replacement Replacement
+
+	for _, replace := range ModReplaces {
+		if (importPath == replace.Path || strings.HasPrefix(importPath, replace.Path+"/")) && len(replace.Path) > len(replacement.Path) {
+			replacement = replace
+		}
+	}
+
+	return replacement, replacement.Path != ""
+}
+
+// isInternalImport reports whether importPath is a package of the module at ModPath.
+// Paths nested under a major version suffix (ModPath/v2/...) belong to another module.
+func isInternalImport(importPath string) bool {
+	modPath := unescapeCase(ModPath)
+	importPath = unescapeCase(importPath)
+
+	if importPath == modPath {
+		return true
+	}
+
+	if !strings.HasPrefix(importPath, modPath+"/") {
+		return false
+	}
+
+	firstElem := strings.SplitN(strings.TrimPrefix(importPath, modPath+"/"), "/", 2)[0]
+
+	return !isMajorVersionSuffix(firstElem)
+}
+
+// isStandardImport reports whether importPath looks like a standard library package (no dot in the first element)
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
+
+// isRequiredImport reports whether importPath belongs to one of the modules required by go.mod
+func isRequiredImport(importPath string) bool {
+	_, ok := requiredModuleFor(importPath)
+
+	return ok
+}
+
+// requiredModuleFor returns the longest module path required by go.mod that provides importPath
+func requiredModuleFor(importPath string) (string, bool) {
+	var modulePath string
+
+	for _, req := range ModRequires {
+		if (importPath == req || strings.HasPrefix(importPath, req+"/")) && len(req) > len(modulePath) {
+			modulePath = req
+		}
+	}
+
+	return modulePath, modulePath != ""
+}
+
+// alternateSelfImport checks if an import that is neither internal, standard nor required imports a module
+// package through another module path with the same module name. This is the case for vanity URLs or module
+// paths that were used before a rename or a move to another owner. The rest of the import after the module
+// name must be the relative path of a module package, the matching module package is returned.
+func alternateSelfImport(importPath string, packageMap map[string]PackageInfo) (string, bool) {
+	if isInternalImport(importPath) || isStandardImport(importPath) || isRequiredImport(importPath) {
+		return "", false
+	}
+
+	name := moduleName(ModPath)
+	elems := strings.Split(importPath, "/")
+
+	// the alternate module path has at least a host element before the module name
+	for i := 1; i < len(elems); i++ {
+		if elems[i] != name {
+			continue
+		}
+
+		relPath := elems[i+1:]
+		if len(relPath) > 0 && isMajorVersionSuffix(relPath[0]) {
+			relPath = relPath[1:]
+		}
+
+		candidate := ModPath
+		if len(relPath) > 0 {
+			candidate += "/" + strings.Join(relPath, "/")
+		}
+
+		if _, ok := packageMap[candidate]; ok {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// moduleName returns the last element of a module path, before its major version suffix
+func moduleName(modPath string) string {
+	elems := strings.Split(modPath, "/")
+
+	if len(elems) > 1 && isMajorVersionSuffix(elems[len(elems)-1]) {
+		return elems[len(elems)-2]
+	}
+
+	return elems[len(elems)-1]
+}
+
+// isMajorVersionSuffix reports whether elem is a semantic import version element such as v2 or v10
+func isMajorVersionSuffix(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' || elem[1] == '0' {
+		return false
+	}
+
+	major, err := strconv.Atoi(elem[1:])
+
+	return err == nil && major >= 2
+}
+
+// unescapeCase decodes module cache case-encoding (github.com/!azure -> github.com/Azure)
+func unescapeCase(p string) string {
+	if !strings.Contains(p, "!") {
+		return p
+	}
+
+	unescaped, err := module.UnescapePath(p)
+	if err != nil {
+		return p
+	}
+
+	return unescaped
+}
+
+// packagePathForDir returns the import path of a package located in relDir, relative to the module root.
+// Directories of locally replaced modules have the import paths of the replaced module, the replacement with
+// the longest directory wins for nested replacements.
+func packagePathForDir(relDir string) string {
+	relDir = path.Clean(filepath.ToSlash(relDir))
+
+	var replacement Replacement
+
+	for _, replace := range ModReplaces {
+		if (relDir == replace.Dir || strings.HasPrefix(relDir, replace.Dir+"/")) && len(replace.Dir) > len(replacement.Dir) {
+			replacement = replace
+		}
+	}
+
+	if replacement.Dir != "" {
+		if relDir == replacement.Dir {
+			return replacement.Path
+		}
+
+		return replacement.Path + "/" + strings.TrimPrefix(relDir, replacement.Dir+"/")
+	}
+
+	if relDir == "." {
+		return ModPath
+	}
+
+	return ModPath + "/" + relDir
+}
+
+// packageDir returns the directory of the project package importPath in workdir
+func packageDir(workdir string, importPath string) string {
+	if importPath == ModPath {
+		return workdir
+	}
+
+	if replace, ok := replacementFor(importPath); ok && !isInternalImport(importPath) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(importPath, replace.Path), "/")
+
+		return filepath.Join(workdir, filepath.FromSlash(replace.Dir), filepath.FromSlash(rel))
+	}
+
+	return filepath.Join(workdir, filepath.FromSlash(strings.TrimPrefix(importPath, ModPath+"/")))
+}
+
+// shortPackagePath trims the module path from a package import path for display
+func shortPackagePath(importPath string) string {
+	if importPath == ModPath {
+		return "/"
 	}
 
-	return modfile.ModulePath(gomod), nil
+	return strings.TrimPrefix(importPath, ModPath)
 }
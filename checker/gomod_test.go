@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func Test_isInternalImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		modPath    string
+		importPath string
+		want       bool
+	}{
+		{
+			name:       "package of the module",
+			modPath:    "github.com/foo/bar",
+			importPath: "github.com/foo/bar/checker",
+			want:       true,
+		},
+		{
+			name:       "module root package",
+			modPath:    "github.com/foo/bar",
+			importPath: "github.com/foo/bar",
+			want:       true,
+		},
+		{
+			name:       "module sharing a path prefix",
+			modPath:    "github.com/foo/bar",
+			importPath: "github.com/foo/barbaz/checker",
+			want:       false,
+		},
+		{
+			name:       "next major version of the module",
+			modPath:    "github.com/foo/bar",
+			importPath: "github.com/foo/bar/v2/checker",
+			want:       false,
+		},
+		{
+			name:       "package of a major version module",
+			modPath:    "github.com/foo/bar/v2",
+			importPath: "github.com/foo/bar/v2/checker",
+			want:       true,
+		},
+		{
+			name:       "previous major version of the module",
+			modPath:    "github.com/foo/bar/v2",
+			importPath: "github.com/foo/bar/checker",
+			want:       false,
+		},
+		{
+			name:       "case-encoded import path",
+			modPath:    "github.com/Foo/bar",
+			importPath: "github.com/!foo/bar/checker",
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ModPath = tt.modPath
+			if got := isInternalImport(tt.importPath); got != tt.want {
+				t.Errorf("isInternalImport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_localReplacements(t *testing.T) {
+	defer func(modPath string, replaces []Replacement) { ModPath, ModReplaces = modPath, replaces }(ModPath, ModReplaces)
+
+	gomod := []byte(`module example.com/app
+
+require (
+	example.com/lib v0.0.0
+	example.com/tools v1.0.0
+	example.com/fork v1.0.0
+	example.com/a v0.0.0
+	example.com/b v0.0.0
+)
+
+replace example.com/lib => ../lib
+
+replace example.com/tools => ./third_party/tools/
+
+replace example.com/fork => example.com/other v1.2.0
+
+replace example.com/a => ./libs
+
+replace example.com/b => ./libs/b
+`)
+
+	modFile, err := modfile.Parse("go.mod", gomod, nil)
+	if err != nil {
+		t.Fatalf("modfile.Parse() error = %v", err)
+	}
+
+	ModPath = "example.com/app"
+	ModReplaces = localReplacements("/src/app", modFile.Replace)
+
+	want := []Replacement{
+		{Path: "example.com/lib", Dir: "../lib"},
+		{Path: "example.com/tools", Dir: "third_party/tools"},
+		{Path: "example.com/a", Dir: "libs"},
+		{Path: "example.com/b", Dir: "libs/b"},
+	}
+
+	if !reflect.DeepEqual(ModReplaces, want) {
+		t.Fatalf("localReplacements() = %v, want %v", ModReplaces, want)
+	}
+
+	for relDir, want := range map[string]string{
+		"../lib":                "example.com/lib",
+		"../lib/util":           "example.com/lib/util",
+		"third_party/tools/cmd": "example.com/tools/cmd",
+		"third_party/toolsets":  "example.com/app/third_party/toolsets",
+		"internal/billing":      "example.com/app/internal/billing",
+		"libs/x":                "example.com/a/x",
+		"libs/b":                "example.com/b",
+		"libs/b/x":              "example.com/b/x",
+		"libs/bx":               "example.com/a/bx",
+		".":                     "example.com/app",
+	} {
+		if got := packagePathForDir(relDir); got != want {
+			t.Errorf("packagePathForDir(%q) = %v, want %v", relDir, got, want)
+		}
+	}
+}
+
+func Test_alternateSelfImport(t *testing.T) {
+	defer func(modPath string, requires []string) { ModPath, ModRequires = modPath, requires }(ModPath, ModRequires)
+
+	packageMap := map[string]PackageInfo{
+		"example.com/gp":             {Path: "example.com/gp"},
+		"example.com/gp/errors":      {Path: "example.com/gp/errors"},
+		"example.com/gp/internal/db": {Path: "example.com/gp/internal/db"},
+	}
+
+	ModPath = "example.com/gp"
+	ModRequires = []string{"github.com/required/gp"}
+
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"github.com/pkg/errors", ""},
+		{"github.com/errors/errors", ""},
+		{"errors", ""},
+		{"example.com/gp/errors", ""},
+		{"github.com/required/gp/errors", ""},
+		{"github.com/old/gp/missing", ""},
+		{"github.com/old/gp/errors", "example.com/gp/errors"},
+		{"github.com/old/gp/v2/internal/db", "example.com/gp/internal/db"},
+		{"go.example.org/gp", "example.com/gp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, ok := alternateSelfImport(tt.importPath, packageMap)
+
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("alternateSelfImport() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
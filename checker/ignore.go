@@ -0,0 +1,165 @@
+package checker
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists paths left out of the file walk, in the .gitignore syntax. It is read in every directory.
+const IgnoreFile = ".unclebobignore"
+
+// ignoreRule is a pattern line of an ignore file
+type ignoreRule struct {
+	// base is the slash separated directory of the ignore file, relative to the walked root
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// ignoreMatcher matches paths against the rules of the ignore files read so far, the last matching rule wins
+type ignoreMatcher struct {
+	fileNames []string
+	rules     []ignoreRule
+}
+
+// newIgnoreMatcher returns a matcher reading IgnoreFile, and .gitignore if gitignore is set
+func newIgnoreMatcher(gitignore bool) *ignoreMatcher {
+	m := &ignoreMatcher{fileNames: []string{IgnoreFile}}
+
+	if gitignore {
+		m.fileNames = []string{".gitignore", IgnoreFile}
+	}
+
+	return m
+}
+
+// load reads the ignore files of the directory rel (slash separated, relative to root)
+func (m *ignoreMatcher) load(root string, rel string) error {
+	for _, fileName := range m.fileNames {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(rel), fileName))
+
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			if rule, ok := parseIgnoreRule(rel, scanner.Text()); ok {
+				m.rules = append(m.rules, rule)
+			}
+		}
+
+		err = scanner.Err()
+		f.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseIgnoreRule parses a line of an ignore file, comments and blank lines are not rules
+func parseIgnoreRule(base string, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+
+	line = strings.TrimPrefix(line, `\`)
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// a pattern without an inner slash matches at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.segments = strings.Split(line, "/")
+
+	if !anchored {
+		rule.segments = append([]string{"**"}, rule.segments...)
+	}
+
+	return rule, true
+}
+
+// ignored reports whether the path rel (slash separated, relative to root) is ignored
+func (m *ignoreMatcher) ignored(rel string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		target := rel
+
+		if rule.base != "." && rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+
+			target = strings.TrimPrefix(rel, rule.base+"/")
+		}
+
+		if matchSegments(rule.segments, strings.Split(target, "/")) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, "**" matches any number of segments
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
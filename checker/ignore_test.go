@@ -0,0 +1,83 @@
+package checker
+
+import "testing"
+
+func Test_ignoreMatcher_ignored(t *testing.T) {
+	m := &ignoreMatcher{}
+
+	for _, line := range []string{"# build output", "bin/", "*.pb.go", "!keep.pb.go", "/tmp", "docs/**/examples"} {
+		if rule, ok := parseIgnoreRule(".", line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+
+	if rule, ok := parseIgnoreRule("web", "generated"); ok {
+		m.rules = append(m.rules, rule)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{
+			name:  "directory pattern",
+			path:  "cmd/bin",
+			isDir: true,
+			want:  true,
+		},
+		{
+			name: "directory pattern does not match files",
+			path: "cmd/bin",
+			want: false,
+		},
+		{
+			name: "glob at any depth",
+			path: "api/v1/user.pb.go",
+			want: true,
+		},
+		{
+			name: "negated pattern",
+			path: "api/keep.pb.go",
+			want: false,
+		},
+		{
+			name:  "anchored pattern",
+			path:  "tmp",
+			isDir: true,
+			want:  true,
+		},
+		{
+			name:  "anchored pattern does not match nested",
+			path:  "internal/tmp",
+			isDir: true,
+			want:  false,
+		},
+		{
+			name:  "double star",
+			path:  "docs/guide/v2/examples",
+			isDir: true,
+			want:  true,
+		},
+		{
+			name:  "nested ignore file",
+			path:  "web/app/generated",
+			isDir: true,
+			want:  true,
+		},
+		{
+			name:  "nested ignore file does not apply outside its directory",
+			path:  "api/generated",
+			isDir: true,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.ignored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("ignored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
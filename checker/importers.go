@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"sort"
+)
+
+// Importer is a package importing another package
+type Importer struct {
+	Path  string `json:"path"`
+	Level int    `json:"level"`
+	Layer string `json:"layer,omitempty"`
+	// Locations are the import declarations of the imported package in the files of the importer
+	Locations []ImportSite `json:"locations,omitempty"`
+}
+
+// Importers returns the packages importing pkg, from the outermost level inwards and then by import path
+func Importers(packageMap map[string]PackageInfo, pkg string) []Importer {
+	var importers []Importer
+
+	for _, importer := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[importer]
+
+		if !contains(packageInfo.Imports, pkg) {
+			continue
+		}
+
+		importers = append(importers, Importer{
+			Path:      importer,
+			Level:     packageInfo.Level,
+			Layer:     packageInfo.Layer,
+			Locations: packageInfo.ImportSites[pkg],
+		})
+	}
+
+	sort.SliceStable(importers, func(i, j int) bool {
+		return importers[i].Level < importers[j].Level
+	})
+
+	return importers
+}
@@ -0,0 +1,32 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImporters(t *testing.T) {
+	site := ImportSite{File: "api/api.go", Line: 3}
+
+	packageMap := map[string]PackageInfo{
+		"example.com/app/api":   {Level: 0, Imports: []string{"example.com/app/store"}, ImportSites: map[string][]ImportSite{"example.com/app/store": {site}}},
+		"example.com/app/admin": {Level: 0, Imports: []string{"example.com/app/store"}},
+		"example.com/app/user":  {Level: 1, Layer: "domain", Imports: []string{"example.com/app/store"}},
+		"example.com/app/cli":   {Level: 0, Imports: []string{"example.com/app/user"}},
+		"example.com/app/store": {Level: 2},
+	}
+
+	want := []Importer{
+		{Path: "example.com/app/admin", Level: 0},
+		{Path: "example.com/app/api", Level: 0, Locations: []ImportSite{site}},
+		{Path: "example.com/app/user", Level: 1, Layer: "domain"},
+	}
+
+	if got := Importers(packageMap, "example.com/app/store"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Importers() = %v, want %v", got, want)
+	}
+
+	if got := Importers(packageMap, "example.com/app/cli"); got != nil {
+		t.Errorf("Importers() of a package imported by no package = %v, want none", got)
+	}
+}
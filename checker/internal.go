@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// CheckInternalBoundaries warns about the project packages outside of internal directories that other modules
+// have no reason to import: packages only imported by packages of internal directories, and packages only
+// imported from one subtree of the module, which fit in an internal directory of that subtree.
+// Packages without importers are not checked, they are commands or the API of the module.
+func CheckInternalBoundaries(packageMap map[string]PackageInfo) []clog.CheckResult {
+	var results []clog.CheckResult
+
+	importers := make(map[string][]string)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		for _, pkgImport := range packageMap[pkg].Imports {
+			importers[pkgImport] = append(importers[pkgImport], pkg)
+		}
+	}
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		if packageMap[pkg].External || pkg == ModPath || isUnderInternal(pkg) || len(importers[pkg]) == 0 {
+			continue
+		}
+
+		onlyInternal := true
+
+		for _, importer := range importers[pkg] {
+			onlyInternal = onlyInternal && isUnderInternal(importer)
+		}
+
+		if onlyInternal {
+			warnMsg := fmt.Sprintf("%v is only imported by packages of internal directories, it can be moved into an internal directory", shortPackagePath(pkg))
+			results = append(results, clog.NewWarning(warnMsg))
+			continue
+		}
+
+		subtree := commonDir(importers[pkg])
+
+		if subtree == "" || subtree == pkg || strings.HasPrefix(subtree, pkg+"/") {
+			continue
+		}
+
+		warnMsg := fmt.Sprintf("%v is only imported from %v, it can be moved into %v/internal", shortPackagePath(pkg), shortPackagePath(subtree), shortPackagePath(subtree))
+		results = append(results, clog.NewWarning(warnMsg))
+	}
+
+	return results
+}
+
+// isUnderInternal reports whether a project package is in an internal directory, only visible to its parent tree
+func isUnderInternal(importPath string) bool {
+	for _, elem := range strings.Split(strings.TrimPrefix(importPath, ModPath), "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commonDir returns the deepest package path containing all the packages, an empty string when it is the module root
+func commonDir(pkgs []string) string {
+	var common []string
+
+	for i, pkg := range pkgs {
+		if pkg == ModPath {
+			return ""
+		}
+
+		elems := strings.Split(strings.TrimPrefix(pkg, ModPath+"/"), "/")
+
+		if i == 0 {
+			common = elems
+			continue
+		}
+
+		n := 0
+
+		for n < len(common) && n < len(elems) && common[n] == elems[n] {
+			n++
+		}
+
+		common = common[:n]
+	}
+
+	if len(common) == 0 {
+		return ""
+	}
+
+	return packagePathForDir(strings.Join(common, "/"))
+}
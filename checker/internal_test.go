@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckInternalBoundaries(t *testing.T) {
+	defer func(modPath string) { ModPath = modPath }(ModPath)
+
+	ModPath = "example.com/app"
+
+	packageMap := map[string]PackageInfo{
+		"example.com/app/cmd/server":      {Imports: []string{"example.com/app/internal/server", "example.com/app/billing/invoice", "example.com/app/shared"}},
+		"example.com/app/api":             {Imports: []string{"example.com/app/billing/invoice", "example.com/app/shared"}},
+		"example.com/app/internal/server": {Imports: []string{"example.com/app/helpers"}},
+		"example.com/app/helpers":         {},
+		"example.com/app/billing/invoice": {Imports: []string{"example.com/app/billing/tax"}},
+		"example.com/app/billing/report":  {Imports: []string{"example.com/app/billing/tax"}},
+		"example.com/app/billing/tax":     {},
+		"example.com/app/shared":          {},
+		"github.com/lib/pq":               {External: true},
+	}
+
+	var got []string
+
+	for _, result := range CheckInternalBoundaries(packageMap) {
+		got = append(got, result.Message)
+	}
+
+	// packages imported from several subtrees and packages without importers are not reported
+	want := []string{
+		"/billing/tax is only imported from /billing, it can be moved into /billing/internal",
+		"/helpers is only imported by packages of internal directories, it can be moved into an internal directory",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckInternalBoundaries() = %q, want %q", got, want)
+	}
+}
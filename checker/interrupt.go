@@ -0,0 +1,20 @@
+package checker
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var interrupted int32
+
+var errInterrupted = errors.New("analysis interrupted")
+
+// Interrupt asks a running analysis to stop, Map and DisplayPackageInfo return what was collected so far
+func Interrupt() {
+	atomic.StoreInt32(&interrupted, 1)
+}
+
+// Interrupted reports whether the analysis was interrupted and its results are partial
+func Interrupted() bool {
+	return atomic.LoadInt32(&interrupted) == 1
+}
@@ -0,0 +1,283 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// ExternalLayer is the layer name of the third-party module pseudo packages
+const ExternalLayer = "external"
+
+// AssignLayers groups packages by declared layers, ordered from the outermost layer (level 0) to the innermost.
+// A package belongs to the layer named by its "unclebob:layer" annotation, otherwise to the first layer with
+// a matching directory pattern. Third-party module pseudo packages are pinned to an additional outermost layer.
+// It returns the packages by level and the layer name of every level. Packages that do not belong to any
+// layer and unknown annotations are reported as warnings.
+func AssignLayers(packageMap map[string]PackageInfo, layers []Layer) ([][]string, []string, []clog.CheckResult) {
+	var results []clog.CheckResult
+	var layerNames []string
+	var externalPackages []string
+	var unassigned []string
+
+	for _, layer := range layers {
+		layerNames = append(layerNames, layer.Name)
+	}
+
+	packagesByLevel := make([][]string, len(layers))
+
+	for _, path := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[path]
+
+		if packageInfo.External {
+			externalPackages = append(externalPackages, path)
+			continue
+		}
+
+		level, ok := layerLevel(layers, path)
+
+		if packageInfo.AnnotatedLayer != "" {
+			level, ok = layerIndex(layers, packageInfo.AnnotatedLayer)
+
+			if !ok {
+				warnMsg := fmt.Sprintf("%v is annotated with the undeclared layer %q\n", path, packageInfo.AnnotatedLayer)
+				results = append(results, clog.NewWarning(warnMsg))
+			}
+		}
+
+		if !ok {
+			unassigned = append(unassigned, path)
+			continue
+		}
+
+		packagesByLevel[level] = append(packagesByLevel[level], path)
+	}
+
+	if len(externalPackages) > 0 {
+		packagesByLevel = append([][]string{externalPackages}, packagesByLevel...)
+		layerNames = append([]string{ExternalLayer}, layerNames...)
+	}
+
+	for level, levelPackages := range packagesByLevel {
+		for _, path := range levelPackages {
+			packageInfo := packageMap[path]
+			packageInfo.Level = level
+			packageInfo.Layer = layerNames[level]
+			packageMap[path] = packageInfo
+		}
+	}
+
+	if len(unassigned) > 0 {
+		msg := "Packages not assigned to any layer, their imports are not checked:\n"
+
+		for _, path := range unassigned {
+			msg = fmt.Sprintf("%v%v \n", msg, path)
+		}
+
+		results = append(results, clog.NewWarning(msg))
+	}
+
+	return packagesByLevel, layerNames, results
+}
+
+// InferLayers returns a layer for every level of packageLevels, from the outermost to the innermost, listing the
+// project packages of the level and depending on the layers they import. The layers are named after their level,
+// they are a starting point for declaring the architecture.
+func InferLayers(packageMap map[string]PackageInfo, packageLevels [][]string) []Layer {
+	var layers []Layer
+
+	layerOf := make(map[string]string)
+
+	for level, levelPackages := range packageLevels {
+		layer := Layer{Name: fmt.Sprintf("level-%v", level), DependsOn: []string{}}
+
+		for _, pkg := range levelPackages {
+			if packageMap[pkg].External {
+				continue
+			}
+
+			layer.Packages = append(layer.Packages, packagePattern(pkg))
+			layerOf[pkg] = layer.Name
+		}
+
+		if len(layer.Packages) > 0 {
+			layers = append(layers, layer)
+		}
+	}
+
+	for i, layer := range layers {
+		imported := make(map[string]bool)
+
+		for pkg, name := range layerOf {
+			if name != layer.Name {
+				continue
+			}
+
+			for _, pkgImport := range packageMap[pkg].Imports {
+				imported[layerOf[pkgImport]] = true
+			}
+		}
+
+		for _, other := range layers {
+			if other.Name != layer.Name && imported[other.Name] {
+				layers[i].DependsOn = append(layers[i].DependsOn, other.Name)
+			}
+		}
+	}
+
+	return layers
+}
+
+// packagePattern returns the import path glob matching only the package, relative to the module
+func packagePattern(importPath string) string {
+	if importPath == ModPath {
+		return "."
+	}
+
+	return strings.TrimPrefix(importPath, ModPath+"/")
+}
+
+// layerIndex returns the index of the layer with the given name
+func layerIndex(layers []Layer, name string) (int, bool) {
+	for i, layer := range layers {
+		if layer.Name == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// layerLevel returns the index of the first layer with a pattern matching the package import path
+func layerLevel(layers []Layer, importPath string) (int, bool) {
+	for i, layer := range layers {
+		if matchAnyPackagePattern(layer.Packages, importPath) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// CheckStdlib reports the standard library imports not allowed by the stdlib policy of the layer of the package
+func CheckStdlib(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	return checkLayerPolicy(packageMap, opts, layerPolicy{
+		rule:        RuleStdlibImport,
+		description: "standard library package",
+		policy:      func(layer Layer) ImportPolicy { return layer.Stdlib },
+		sites:       func(packageInfo PackageInfo) map[string][]ImportSite { return packageInfo.StandardImports },
+	})
+}
+
+// CheckThirdParty reports the third-party imports not allowed by the thirdParty policy of the layer of the package
+func CheckThirdParty(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	return checkLayerPolicy(packageMap, opts, layerPolicy{
+		rule:        RuleThirdPartyImport,
+		description: "third-party package",
+		policy:      func(layer Layer) ImportPolicy { return layer.ThirdParty },
+		sites:       func(packageInfo PackageInfo) map[string][]ImportSite { return packageInfo.ThirdPartyImports },
+	})
+}
+
+// CheckLayerDependencies reports the imports of packages of another layer than the layers declared in the
+// dependsOn list of the layer of the importing package. Layers without dependsOn are not checked.
+func CheckLayerDependencies(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[pkg]
+		index, ok := layerIndex(opts.Layers, packageInfo.Layer)
+
+		if !ok || opts.Layers[index].DependsOn == nil {
+			continue
+		}
+
+		layer := opts.Layers[index]
+
+		for _, pkgImport := range packageInfo.Imports {
+			importLayer := packageMap[pkgImport].Layer
+
+			// third-party modules are restricted by the thirdParty policies
+			if _, ok := layerIndex(opts.Layers, importLayer); !ok || importLayer == layer.Name {
+				continue
+			}
+
+			if contains(layer.DependsOn, importLayer) || isAllowedByRule(opts.Rules, pkg, pkgImport) {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Layer %v does not declare a dependency on layer %v", layer.Name, importLayer)
+
+			found.add(packageMap, RuleUndeclaredDependency, errMsg, pkg, pkgImport)
+		}
+	}
+
+	return found.all()
+}
+
+// layerPolicy selects the imports and the import policy of the layers checked by checkLayerPolicy
+type layerPolicy struct {
+	rule        string
+	description string
+	policy      func(Layer) ImportPolicy
+	sites       func(PackageInfo) map[string][]ImportSite
+}
+
+// checkLayerPolicy reports the imports of the packages of declared layers not allowed by the policy of their layer
+func checkLayerPolicy(packageMap map[string]PackageInfo, opts CheckOptions, lp layerPolicy) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[pkg]
+		index, ok := layerIndex(opts.Layers, packageInfo.Layer)
+
+		if !ok {
+			continue
+		}
+
+		layer := opts.Layers[index]
+		sites := lp.sites(packageInfo)
+
+		for _, pkgImport := range sortedKeys(sites) {
+			if lp.policy(layer).allows(pkgImport) {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Layer %v may not import the %v %v", layer.Name, lp.description, pkgImport)
+
+			violation := newViolation(packageMap, lp.rule, errMsg, pkg, pkgImport)
+			violation.Locations = sites[pkgImport]
+
+			found.record(packageMap, violation)
+		}
+	}
+
+	return found.all()
+}
+
+// sortedKeys returns the imports of a map of import declarations in import path order
+func sortedKeys(sites map[string][]ImportSite) []string {
+	keys := make([]string, 0, len(sites))
+
+	for key := range sites {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedPackagePaths(packageMap map[string]PackageInfo) []string {
+	paths := make([]string, 0, len(packageMap))
+
+	for path := range packageMap {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,190 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferLayers(t *testing.T) {
+	ModPath = "github.com/foo/bar"
+
+	packageMap := map[string]PackageInfo{
+		"github.com/foo/bar":        {Imports: []string{"github.com/foo/bar/app", "github.com/foo/bar/domain", "github.com/lib/pq"}},
+		"github.com/foo/bar/app":    {Imports: []string{"github.com/foo/bar/domain"}},
+		"github.com/foo/bar/domain": {},
+		"github.com/lib/pq":         {External: true},
+	}
+
+	packageLevels := [][]string{
+		{"github.com/foo/bar", "github.com/lib/pq"},
+		{"github.com/foo/bar/app"},
+		{"github.com/foo/bar/domain"},
+	}
+
+	want := []Layer{
+		{Name: "level-0", Packages: []string{"."}, DependsOn: []string{"level-1", "level-2"}},
+		{Name: "level-1", Packages: []string{"app"}, DependsOn: []string{"level-2"}},
+		{Name: "level-2", Packages: []string{"domain"}, DependsOn: []string{}},
+	}
+
+	if got := InferLayers(packageMap, packageLevels); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferLayers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAssignLayers(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/layered\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: adapter
+    packages: ["adapters/..."]
+  - name: domain
+    packages: ["domain/..."]
+`,
+		"adapters/http/http.go": "package http\n\nimport _ \"example.com/layered/domain/user\"\n",
+		"adapters/db/db.go":     "package db\n",
+		"domain/user/user.go":   "package user\n\nimport _ \"example.com/layered/adapters/db\"\n",
+		"domain/order/order.go": "package order\n\nimport _ \"example.com/layered/domain/user\"\n",
+		"tools/tools.go":        "package tools\n\nimport _ \"example.com/layered/adapters/db\"\n",
+	})
+
+	wantLevels := [][]string{
+		{"example.com/layered/adapters/db", "example.com/layered/adapters/http"},
+		{"example.com/layered/domain/order", "example.com/layered/domain/user"},
+	}
+
+	if !reflect.DeepEqual(f.levels, wantLevels) || !reflect.DeepEqual(f.opts.LayerNames, []string{"adapter", "domain"}) {
+		t.Errorf("AssignLayers() = %v %v, want %v [adapter domain]", f.levels, f.opts.LayerNames, wantLevels)
+	}
+
+	// imports within a layer and of inner layers are allowed, packages without a layer are not checked
+	want := []string{RuleOuterLayer + " /domain/user -> /adapters/db"}
+
+	if got := imports(f.checkLevels(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckLevels() = %q, want %q", got, want)
+	}
+}
+
+func TestAssignLayers_strict(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/layered\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: adapter
+    packages: ["adapters/..."]
+  - name: usecase
+    packages: ["usecases/..."]
+  - name: domain
+    packages: ["domain/..."]
+`,
+		"adapters/http/http.go":  "package http\n\nimport _ \"example.com/layered/usecases/signup\"\n",
+		"adapters/cli/cli.go":    "package cli\n\nimport _ \"example.com/layered/domain/user\"\n",
+		"usecases/signup/use.go": "package signup\n\nimport _ \"example.com/layered/domain/user\"\n",
+		"domain/user/user.go":    "package user\n",
+	})
+
+	if got := f.checkLevels(t); len(got) != 0 {
+		t.Errorf("CheckLevels() = %q, want none without strict mode", imports(got))
+	}
+
+	f.opts.Strict = true
+
+	// imports of the next inner layer are allowed, skipping the usecase layer is not
+	want := []string{RuleLayerSkip + " /adapters/cli -> /domain/user"}
+
+	if got := imports(f.checkLevels(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckLevels() = %q, want %q", got, want)
+	}
+}
+
+func TestAssignLayers_annotation(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/annotated\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: infra
+    packages: [store, billing]
+  - name: domain
+    packages: [user]
+`,
+		"store/store.go":     "package store\n",
+		"billing/doc.go":     "// Package billing computes invoices.\n// unclebob:layer=domain\npackage billing\n",
+		"billing/billing.go": "package billing\n\nimport _ \"example.com/annotated/store\"\n",
+		"user/user.go":       "package user\n\nimport _ \"example.com/annotated/billing\"\n",
+	})
+
+	if layer := f.packageMap["example.com/annotated/billing"].Layer; layer != "domain" {
+		t.Errorf("layer of the annotated package = %v, want domain", layer)
+	}
+
+	// the annotation takes precedence over the patterns of the layers
+	want := []string{RuleOuterLayer + " /billing -> /store"}
+
+	if got := imports(f.checkLevels(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckLevels() = %q, want %q", got, want)
+	}
+
+	_, _, results := AssignLayers(f.packageMap, []Layer{{Name: "infra", Packages: []string{"store", "billing", "user"}}})
+
+	if len(results) == 0 || !strings.Contains(results[0].Message, `annotated with the undeclared layer "domain"`) {
+		t.Errorf("AssignLayers() = %v, want a warning about the undeclared layer", results)
+	}
+}
+
+func TestCheckStdlib(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/restricted\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: infra
+    packages: [db]
+  - name: app
+    packages: [api]
+    stdlib:
+      allow: [fmt, "encoding/..."]
+  - name: domain
+    packages: [user]
+    stdlib:
+      deny: ["net/...", os]
+`,
+		"db/db.go":     "package db\n\nimport (\n\t_ \"net/http\"\n\t_ \"os\"\n)\n",
+		"api/api.go":   "package api\n\nimport (\n\t_ \"encoding/json\"\n\t_ \"fmt\"\n\t_ \"os\"\n)\n",
+		"user/user.go": "package user\n\nimport (\n\t_ \"fmt\"\n\t_ \"net/http\"\n\t_ \"os\"\n)\n",
+	})
+
+	want := []string{
+		RuleStdlibImport + " /api -> os",
+		RuleStdlibImport + " /user -> net/http",
+		RuleStdlibImport + " /user -> os",
+	}
+
+	if got := imports(CheckStdlib(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckStdlib() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckThirdParty(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/restricted\n\ngo 1.22\n\nrequire (\n\tgithub.com/lib/pq v1.10.9\n\tgithub.com/google/uuid v1.6.0\n\tgithub.com/google/go-cmp v0.6.0\n)\n",
+		DefaultConfigFile: `layers:
+  - name: infra
+    packages: [db]
+  - name: domain
+    packages: [user, order]
+    thirdParty:
+      allow: ["github.com/google/..."]
+      deny: [github.com/google/uuid]
+`,
+		"db/db.go":       "package db\n\nimport _ \"github.com/lib/pq\"\n",
+		"user/user.go":   "package user\n\nimport (\n\t_ \"github.com/google/uuid\"\n\t_ \"github.com/lib/pq\"\n)\n",
+		"order/order.go": "package order\n\nimport _ \"github.com/google/go-cmp/cmp\"\n",
+	})
+
+	// the imports matching deny are never allowed, the others only when they match allow
+	want := []string{
+		RuleThirdPartyImport + " /user -> github.com/google/uuid",
+		RuleThirdPartyImport + " /user -> github.com/lib/pq",
+	}
+
+	if got := imports(CheckThirdParty(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckThirdParty() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package checker
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// PackageMetrics are Robert C. Martin's package design metrics
+type PackageMetrics struct {
+	Path string `json:"path"`
+	// Afferent couplings (Ca) is the number of project packages importing the package
+	Afferent int `json:"afferent"`
+	// Efferent couplings (Ce) is the number of packages imported by the package
+	Efferent int `json:"efferent"`
+	// Instability is Ce / (Ca + Ce), from 0 (stable) to 1 (instable)
+	Instability float64 `json:"instability"`
+	// Interfaces and Types count the exported interface types and all exported types
+	Interfaces int `json:"interfaces"`
+	Types      int `json:"types"`
+	// Abstractness is Interfaces / Types, from 0 (concrete) to 1 (abstract)
+	Abstractness float64 `json:"abstractness"`
+	// Distance from the main sequence is |A + I - 1|, packages far from it are either rigid or useless
+	Distance float64 `json:"distance"`
+}
+
+// Metrics computes the design metrics of the project packages of the map, in import path order.
+// Only the imports collected by Map count as couplings, so standard library packages are never counted and
+// third-party modules only with MapOptions.External. Abstractness is computed from the non test files.
+func Metrics(workdir string, packageMap map[string]PackageInfo) []PackageMetrics {
+	fans := Fan(packageMap)
+	metrics := make([]PackageMetrics, 0, len(fans))
+
+	for _, fan := range fans {
+		m := PackageMetrics{
+			Path:     fan.Path,
+			Afferent: fan.FanIn,
+			Efferent: fan.FanOut,
+		}
+
+		m.Interfaces, m.Types = countExportedTypes(packageDir(workdir, fan.Path), packageMap[fan.Path].Files)
+
+		if m.Afferent+m.Efferent > 0 {
+			m.Instability = float64(m.Efferent) / float64(m.Afferent+m.Efferent)
+		}
+
+		if m.Types > 0 {
+			m.Abstractness = float64(m.Interfaces) / float64(m.Types)
+		}
+
+		m.Distance = math.Abs(m.Abstractness + m.Instability - 1)
+
+		metrics = append(metrics, m)
+	}
+
+	return metrics
+}
+
+// countExportedTypes returns the number of exported interface types and of all exported types declared in the
+// files of dir. Test files and aliases are not counted, files failing to parse were already reported by Map.
+func countExportedTypes(dir string, fileNames []string) (int, int) {
+	interfaces, types := 0, 0
+	fset := token.NewFileSet()
+
+	for _, fileName := range fileNames {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+
+				if !typeSpec.Name.IsExported() || typeSpec.Assign.IsValid() {
+					continue
+				}
+
+				types++
+
+				if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					interfaces++
+				}
+			}
+		}
+	}
+
+	return interfaces, types
+}
@@ -0,0 +1,26 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod":             "module example.com/measured\n\ngo 1.22\n",
+		"api/api.go":         "package api\n\nimport (\n\t_ \"example.com/measured/store\"\n\t_ \"example.com/measured/user\"\n)\n",
+		"user/user.go":       "package user\n\nimport _ \"example.com/measured/store\"\n\ntype User struct{}\n",
+		"store/store.go":     "package store\n\ntype Repository interface{}\n\ntype Item struct{}\n\ntype row struct{}\n",
+		"store/fake_test.go": "package store\n\ntype Fake struct{}\n",
+	})
+
+	want := []PackageMetrics{
+		{Path: "example.com/measured/api", Efferent: 2, Instability: 1},
+		{Path: "example.com/measured/store", Afferent: 2, Interfaces: 1, Types: 2, Abstractness: 0.5, Distance: 0.5},
+		{Path: "example.com/measured/user", Afferent: 1, Efferent: 1, Instability: 0.5, Types: 1, Distance: 0.5},
+	}
+
+	if got := Metrics(f.dir, f.packageMap); !reflect.DeepEqual(got, want) {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"golang.org/x/tools/go/packages"
+)
+
+// Loaders collecting the files of the project
+const (
+	// LoaderWalk walks the project directory, it needs no go toolchain
+	LoaderWalk = "walk"
+	// LoaderPackages lists the packages with golang.org/x/tools/go/packages, it needs the go command and the
+	// dependencies of the module, in exchange the build constraints and test variants are resolved by go itself
+	LoaderPackages = "packages"
+)
+
+// packagesConfig returns the go/packages config loading the module in workdir for the platform and build tags
+// of opts.BuildContext, the go command is killed when ctx is done
+func packagesConfig(ctx context.Context, workdir string, opts MapOptions, mode packages.LoadMode) *packages.Config {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    mode,
+		Dir:     workdir,
+		Env:     os.Environ(),
+	}
+
+	if opts.BuildContext != nil {
+		cfg.Env = append(cfg.Env, "GOOS="+opts.BuildContext.GOOS, "GOARCH="+opts.BuildContext.GOARCH)
+
+		if len(opts.BuildContext.BuildTags) > 0 {
+			cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.BuildContext.BuildTags, ",")}
+		}
+	}
+
+	return cfg
+}
+
+// loadPackageFiles lists the go files of the packages of the module in workdir with go/packages and groups
+// them by directory like collectGoFiles. Test files are included unless opts.IgnoreTests is set.
+func loadPackageFiles(ctx context.Context, workdir string, opts MapOptions) ([]string, map[string][]string, []clog.CheckResult) {
+	var results []clog.CheckResult
+
+	cfg := packagesConfig(ctx, workdir, opts, packages.NeedName|packages.NeedFiles)
+	cfg.Tests = !opts.IgnoreTests
+
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, nil, append(results, clog.NewError(err.Error()))
+	}
+
+	dirFiles := make(map[string][]string)
+	seen := make(map[string]bool)
+
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			results = append(results, clog.NewWarning(pkgErr.Error()))
+		}
+
+		// test variants list the package files again, generated test mains live outside of the project
+		for _, file := range pkg.GoFiles {
+			rel, err := filepath.Rel(workdir, file)
+
+			if err != nil || !filepath.IsLocal(rel) || seen[file] {
+				continue
+			}
+
+			seen[file] = true
+
+			dir := filepath.Dir(file)
+			dirFiles[dir] = append(dirFiles[dir], filepath.Base(file))
+		}
+	}
+
+	dirs := make([]string, 0, len(dirFiles))
+
+	for dir := range dirFiles {
+		sort.Strings(dirFiles[dir])
+		dirs = append(dirs, dir)
+	}
+
+	sort.Strings(dirs)
+
+	return dirs, dirFiles, results
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestMap_packagesLoader(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+
+	defer func(modPath string) { ModPath = modPath }(ModPath)
+
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/loaded\n\ngo 1.22\n",
+		"main.go":            "package main\n\nimport _ \"example.com/loaded/user\"\n\nfunc main() {}\n",
+		"user/user.go":       "package user\n\nimport _ \"example.com/loaded/store\"\n",
+		"user/user_test.go":  "package user\n\nimport _ \"example.com/loaded/billing\"\n",
+		"user/never.go":      "//go:build never\n\npackage user\n\nimport _ \"example.com/loaded/billing\"\n",
+		"store/store.go":     "package store\n",
+		"billing/billing.go": "package billing\n",
+	})
+
+	if err := LocateModule(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	imports := func(loader string, ignoreTests bool) map[string][]string {
+		packageMap, _, err := Map(context.Background(), dir, MapOptions{Loader: loader, IgnoreTests: ignoreTests})
+		if err != nil {
+			t.Fatalf("Map() with the %v loader error = %v", loader, err)
+		}
+
+		found := make(map[string][]string)
+
+		for path, packageInfo := range packageMap {
+			found[shortPackagePath(path)] = packageInfo.Imports
+		}
+
+		return found
+	}
+
+	want := map[string][]string{
+		"/":        {"example.com/loaded/user"},
+		"/user":    {"example.com/loaded/store", "example.com/loaded/billing"},
+		"/store":   nil,
+		"/billing": nil,
+	}
+
+	if got := imports(LoaderPackages, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() with the packages loader = %v, want %v", got, want)
+	}
+
+	want["/user"] = []string{"example.com/loaded/store"}
+
+	if got := imports(LoaderPackages, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() with the packages loader without tests = %v, want %v", got, want)
+	}
+}
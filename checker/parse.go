@@ -0,0 +1,263 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// DefaultSkipDirs are the names of the directories never walked, they hold no project packages
+var DefaultSkipDirs = []string{".git", ".idea", ".vscode", "node_modules", "testdata", "vendor"}
+
+// isSkippedDir reports whether the directory at path is in DefaultSkipDirs or skipDirs,
+// by name or by slash separated path relative to root
+func isSkippedDir(root string, path string, skipDirs []string) bool {
+	if path == root {
+		return false
+	}
+
+	name := filepath.Base(path)
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	for _, skipDir := range append(DefaultSkipDirs, skipDirs...) {
+		if skipDir == name || strings.Trim(skipDir, "/") == rel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walkOptions control which files collectGoFiles collects
+type walkOptions struct {
+	ignoreTests bool
+	// skipDirs are not walked, in addition to DefaultSkipDirs
+	skipDirs []string
+	// gitignore honors .gitignore files, IgnoreFile files are always honored
+	gitignore bool
+}
+
+// collectGoFiles walks root and groups go file names by directory, the directories of DefaultSkipDirs
+// and opts.skipDirs and the paths of ignore files are not walked.
+// Directories are returned in walk order so that the results are stable.
+func collectGoFiles(ctx context.Context, root string, opts walkOptions) ([]string, map[string][]string, []clog.CheckResult) {
+	var results []clog.CheckResult
+	var dirs []string
+
+	dirFiles := make(map[string][]string)
+	ignore := newIgnoreMatcher(opts.gitignore)
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if Interrupted() {
+			return errInterrupted
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// log and skip if error is not nil
+		if err != nil {
+			results = append(results, clog.NewError(err.Error()))
+			return nil
+		}
+
+		// vendor is mapped with MapOptions.Vendor, the other skipped directories are tooling or test data
+		if info.IsDir() && isSkippedDir(root, path, opts.skipDirs) {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			results = append(results, clog.NewError(err.Error()))
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if rel != "." && ignore.ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.IsDir() {
+			if err := ignore.load(root, rel); err != nil {
+				results = append(results, clog.NewError(err.Error()))
+			}
+		}
+
+		// nested modules have their own module path, they are analyzed separately
+		if info.IsDir() && path != root && isModuleRoot(path) {
+			results = append(results, clog.NewWarning("Skipping nested module "+path+", it is analyzed on its own with -recursive"))
+			return filepath.SkipDir
+		}
+
+		// skip directories and non go files
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			return nil
+		}
+
+		fileString := filepath.Base(path)
+
+		if opts.ignoreTests && strings.HasSuffix(fileString, "_test.go") {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+
+		if _, ok := dirFiles[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+
+		dirFiles[dir] = append(dirFiles[dir], fileString)
+
+		return nil
+	})
+
+	return dirs, dirFiles, results
+}
+
+// parsedFile holds what is collected from the import declarations of a file
+type parsedFile struct {
+	imports []string
+	// lines maps the imports to the line of their declaration
+	lines map[string]int
+	// layer is set by a "unclebob:layer=<name>" comment, usually found in doc.go
+	layer string
+	// suppressed maps the imports exempt from checks by an "unclebob:ignore <reason>" comment to the reason
+	suppressed map[string]string
+	// generated is set for files with a "// Code generated ... DO NOT EDIT." header
+	generated bool
+	// loc is the number of lines of the file
+	loc int
+}
+
+const (
+	layerDirective  = "unclebob:layer="
+	ignoreDirective = "unclebob:ignore"
+)
+
+// parseFile parses the import declarations of a file, fset is shared between the files of a directory.
+// The results are cached by file content in CacheDir.
+func parseFile(fset *token.FileSet, path string) (parsedFile, error) {
+	var parsed parsedFile
+
+	fpath, err := filepath.Abs(path)
+	if err != nil {
+		return parsed, err
+	}
+
+	content, err := os.ReadFile(fpath)
+	if err != nil {
+		return parsed, err
+	}
+
+	key := cacheKey(content)
+
+	if cached, ok := loadCachedFile(key); ok {
+		cached.loc = countLines(content)
+		return cached, nil
+	}
+
+	parsed.loc = countLines(content)
+
+	imports, err := parser.ParseFile(fset, fpath, content, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return parsed, err
+	}
+
+	parsed.generated = ast.IsGenerated(imports)
+	parsed.imports = make([]string, 0, len(imports.Imports))
+	parsed.suppressed = make(map[string]string)
+	parsed.lines = make(map[string]int)
+
+	// comments attached to import specs, the others apply to the whole file
+	importComments := make(map[*ast.CommentGroup]bool)
+
+	for _, v := range imports.Imports {
+		importPath, err := strconv.Unquote(v.Path.Value)
+		if err != nil {
+			return parsed, err
+		}
+
+		parsed.imports = append(parsed.imports, importPath)
+		parsed.lines[importPath] = fset.Position(v.Pos()).Line
+
+		for _, commentGroup := range []*ast.CommentGroup{v.Doc, v.Comment} {
+			if commentGroup == nil {
+				continue
+			}
+
+			importComments[commentGroup] = true
+
+			if reason, ok := ignoreReason(commentGroup); ok {
+				parsed.suppressed[importPath] = reason
+			}
+		}
+	}
+
+	for _, commentGroup := range imports.Comments {
+		for _, comment := range commentGroup.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+
+			if strings.HasPrefix(text, layerDirective) {
+				parsed.layer = strings.TrimSpace(strings.TrimPrefix(text, layerDirective))
+			}
+		}
+
+		if importComments[commentGroup] {
+			continue
+		}
+
+		if reason, ok := ignoreReason(commentGroup); ok {
+			for _, importPath := range parsed.imports {
+				parsed.suppressed[importPath] = reason
+			}
+		}
+	}
+
+	storeCachedFile(key, parsed)
+
+	return parsed, nil
+}
+
+// countLines returns the number of lines of a file content, a last line without newline counts
+func countLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+
+	return lines
+}
+
+// ignoreReason looks for an "unclebob:ignore <reason>" comment in a comment group
+func ignoreReason(commentGroup *ast.CommentGroup) (string, bool) {
+	for _, comment := range commentGroup.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+
+		if text == ignoreDirective || strings.HasPrefix(text, ignoreDirective+" ") {
+			return strings.TrimSpace(strings.TrimPrefix(text, ignoreDirective)), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_isSkippedDir(t *testing.T) {
+	root := filepath.FromSlash("/src/project")
+
+	tests := []struct {
+		name     string
+		path     string
+		skipDirs []string
+		want     bool
+	}{
+		{
+			name: "root is walked",
+			path: "",
+			want: false,
+		},
+		{
+			name: "default skip dir",
+			path: "node_modules",
+			want: true,
+		},
+		{
+			name: "default skip dir nested",
+			path: "internal/parser/testdata",
+			want: true,
+		},
+		{
+			name: "package dir",
+			path: "internal/parser",
+			want: false,
+		},
+		{
+			name:     "custom name",
+			path:     "web/dist",
+			skipDirs: []string{"dist"},
+			want:     true,
+		},
+		{
+			name:     "custom relative path",
+			path:     "third_party/proto",
+			skipDirs: []string{"third_party/proto/"},
+			want:     true,
+		},
+		{
+			name:     "custom relative path does not match another tree",
+			path:     "internal/third_party/proto",
+			skipDirs: []string{"third_party/proto"},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(root, filepath.FromSlash(tt.path))
+
+			if got := isSkippedDir(root, path, tt.skipDirs); got != tt.want {
+				t.Errorf("isSkippedDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// matchPackagePattern reports whether a package import path matches a glob pattern.
+// The pattern is matched against the full import path and against the path relative to the module root,
+// which is "." for the root package.
+// A "*" matches any sequence of characters including "/", "?" matches a single character and a trailing
+// "/..." matches the package itself and all packages below it, as in go tooling.
+func matchPackagePattern(pattern string, importPath string) bool {
+	candidates := []string{importPath}
+
+	switch {
+	case importPath == ModPath:
+		candidates = append(candidates, ".")
+	case strings.HasPrefix(importPath, ModPath+"/"):
+		candidates = append(candidates, strings.TrimPrefix(importPath, ModPath+"/"))
+	}
+
+	patterns := []string{pattern}
+
+	if strings.HasSuffix(pattern, "/...") {
+		patterns = append(patterns, strings.TrimSuffix(pattern, "/..."))
+	}
+
+	for _, p := range patterns {
+		re, err := patternToRegexp(p)
+		if err != nil {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if re.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchAnyPackagePattern reports whether importPath matches at least one of the patterns
+func matchAnyPackagePattern(patterns []string, importPath string) bool {
+	for _, pattern := range patterns {
+		if matchPackagePattern(pattern, importPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compiledPatterns caches the regular expressions of the patterns, they are matched against every import
+var compiledPatterns sync.Map
+
+// patternToRegexp returns the regular expression of a glob pattern, compiled once per pattern
+func patternToRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiledPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	var expr strings.Builder
+
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "..."):
+			expr.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			expr.WriteString(".*")
+		case pattern[i] == '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	compiledPatterns.Store(pattern, re)
+
+	return re, nil
+}
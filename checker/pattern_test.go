@@ -0,0 +1,80 @@
+package checker
+
+import "testing"
+
+func Test_matchPackagePattern(t *testing.T) {
+	ModPath = "github.com/foo/bar"
+
+	tests := []struct {
+		name       string
+		pattern    string
+		importPath string
+		want       bool
+	}{
+		{
+			name:       "suffix glob",
+			pattern:    "*/mocks",
+			importPath: "github.com/foo/bar/internal/adapters/mocks",
+			want:       true,
+		},
+		{
+			name:       "glob in the middle",
+			pattern:    "*/generated/*",
+			importPath: "github.com/foo/bar/api/generated/v1",
+			want:       true,
+		},
+		{
+			name:       "relative path",
+			pattern:    "internal/billing",
+			importPath: "github.com/foo/bar/internal/billing",
+			want:       true,
+		},
+		{
+			name:       "package tree includes its root",
+			pattern:    "internal/billing/...",
+			importPath: "github.com/foo/bar/internal/billing",
+			want:       true,
+		},
+		{
+			name:       "package tree includes subpackages",
+			pattern:    "internal/billing/...",
+			importPath: "github.com/foo/bar/internal/billing/invoices",
+			want:       true,
+		},
+		{
+			name:       "package tree does not include siblings",
+			pattern:    "internal/billing/...",
+			importPath: "github.com/foo/bar/internal/billingv2",
+			want:       false,
+		},
+		{
+			name:       "no match",
+			pattern:    "*/mocks",
+			importPath: "github.com/foo/bar/internal/domain",
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchPackagePattern(tt.pattern, tt.importPath); got != tt.want {
+				t.Errorf("matchPackagePattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_patternToRegexp_cached(t *testing.T) {
+	first, err := patternToRegexp("internal/*/db")
+	if err != nil {
+		t.Fatalf("patternToRegexp() error = %v", err)
+	}
+
+	second, err := patternToRegexp("internal/*/db")
+	if err != nil || second != first {
+		t.Errorf("patternToRegexp() of the same pattern compiled it again")
+	}
+
+	if !first.MatchString("internal/user/db") || first.MatchString("internal/user/api") {
+		t.Errorf("patternToRegexp() = %v, want internal/*/db", first)
+	}
+}
@@ -0,0 +1,51 @@
+package checker
+
+import "sort"
+
+// Presets are the layers of well known architectures, selected with the preset key of the config file.
+// Packages are matched by directory names at any depth, the commands and the module root are outermost.
+var Presets = map[string][]Layer{
+	// clean is Robert C. Martin's Clean Architecture
+	"clean": {
+		{Name: "frameworks", Packages: presetPatterns(".", "cmd", "infrastructure", "infra", "frameworks", "drivers")},
+		{Name: "adapters", Packages: presetPatterns("adapters", "adapter", "controllers", "presenters", "gateways", "handlers")},
+		{Name: "usecases", Packages: presetPatterns("usecases", "usecase", "application", "app")},
+		{Name: "entities", Packages: presetPatterns("entities", "entity", "domain")},
+	},
+	// onion is Jeffrey Palermo's Onion Architecture, the domain services ring is matched before the domain model
+	"onion": {
+		{Name: "infrastructure", Packages: presetPatterns(".", "cmd", "infrastructure", "infra", "adapters", "ui", "api")},
+		{Name: "application-services", Packages: presetPatterns("application", "app", "services/application")},
+		{Name: "domain-services", Packages: presetPatterns("domain/services", "domain/service", "domainservices")},
+		{Name: "domain-model", Packages: presetPatterns("domain", "core", "model")},
+	},
+}
+
+// presetNames returns the names of the presets in alphabetical order
+func presetNames() []string {
+	names := make([]string, 0, len(Presets))
+
+	for name := range Presets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// presetPatterns returns the import path globs matching the directories and the packages below them, at any depth
+func presetPatterns(dirs ...string) []string {
+	var patterns []string
+
+	for _, dir := range dirs {
+		if dir == "." {
+			patterns = append(patterns, dir)
+			continue
+		}
+
+		patterns = append(patterns, dir+"/...", "*/"+dir+"/...")
+	}
+
+	return patterns
+}
@@ -0,0 +1,409 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryFunctions are the functions of the query language, taking an import path glob
+var QueryFunctions = []string{"imports", "importers", "deps", "rdeps", "layer"}
+
+// QueryAttributes are the package attributes compared by the query language
+var QueryAttributes = []string{"level", "fanin", "fanout", "violations"}
+
+// Query is a parsed query selecting packages of the graph. Queries combine import path globs, functions and
+// attribute comparisons with & (and), | (or), ! (not) and parentheses:
+//
+//	importers(internal/domain) & level>1
+//	deps(cmd/...) & !layer(domain)
+//	fanin>=10 | violations>0
+type Query struct {
+	expr queryNode
+}
+
+// queryNode selects packages of the graph
+type queryNode func(g *queryGraph) map[string]bool
+
+// queryGraph is the graph a query is evaluated on
+type queryGraph struct {
+	packageMap map[string]PackageInfo
+	importers  map[string][]string
+	violations map[string]int
+}
+
+// ParseQuery parses a query of the query language
+func ParseQuery(expr string) (Query, error) {
+	p := queryParser{tokens: tokenizeQuery(expr)}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return Query{}, err
+	}
+
+	if !p.done() {
+		return Query{}, fmt.Errorf("unexpected %q in query", p.peek())
+	}
+
+	return Query{expr: node}, nil
+}
+
+// Eval returns the packages of the map selected by the query in import path order, violations are the
+// violations counted by the violations attribute
+func (q Query) Eval(packageMap map[string]PackageInfo, violations []Violation) []string {
+	g := &queryGraph{
+		packageMap: packageMap,
+		importers:  make(map[string][]string),
+		violations: make(map[string]int),
+	}
+
+	for pkg, packageInfo := range packageMap {
+		for _, pkgImport := range packageInfo.Imports {
+			g.importers[pkgImport] = append(g.importers[pkgImport], pkg)
+		}
+	}
+
+	for _, violation := range violations {
+		if !violation.Suppressed {
+			g.violations[violation.From]++
+		}
+	}
+
+	selected := q.expr(g)
+	packages := make([]string, 0, len(selected))
+
+	for pkg := range selected {
+		packages = append(packages, pkg)
+	}
+
+	sort.Strings(packages)
+
+	return packages
+}
+
+// tokenizeQuery splits a query into operators, parentheses and words
+func tokenizeQuery(expr string) []string {
+	var tokens []string
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.HasPrefix(expr[i:], ">=") || strings.HasPrefix(expr[i:], "<=") || strings.HasPrefix(expr[i:], "!="):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case strings.IndexByte("()&|!<>=", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			j := i
+			for j < len(expr) && strings.IndexByte("()&|!<>= \t\n", expr[j]) < 0 {
+				j++
+			}
+
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
+
+// queryParser is a recursive descent parser of the query language
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *queryParser) peek() string {
+	if p.done() {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() string {
+	token := p.peek()
+	p.pos++
+
+	return token
+}
+
+func (p *queryParser) expect(token string) error {
+	if got := p.next(); got != token {
+		if got == "" {
+			return fmt.Errorf("expected %q at the end of the query", token)
+		}
+
+		return fmt.Errorf("expected %q in query, got %q", token, got)
+	}
+
+	return nil
+}
+
+// parseOr parses terms joined by |
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "|" {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = union(left, right)
+	}
+
+	return left, nil
+}
+
+// parseAnd parses factors joined by &
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "&" {
+		p.next()
+
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		left = intersection(left, right)
+	}
+
+	return left, nil
+}
+
+// parseFactor parses a negation, a parenthesized query, a function, a comparison or an import path glob
+func (p *queryParser) parseFactor() (queryNode, error) {
+	token := p.next()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the query")
+	case token == "!":
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		return complement(operand), nil
+	case token == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return node, p.expect(")")
+	case contains([]string{")", "&", "|", "=", "!=", "<", "<=", ">", ">="}, token):
+		return nil, fmt.Errorf("unexpected %q in query", token)
+	case p.peek() == "(":
+		return p.parseFunction(token)
+	case contains(QueryAttributes, token):
+		return p.parseComparison(token)
+	}
+
+	return matching(token), nil
+}
+
+// parseFunction parses the import path glob argument of a function
+func (p *queryParser) parseFunction(name string) (queryNode, error) {
+	if !contains(QueryFunctions, name) {
+		return nil, fmt.Errorf("unknown query function %q, use one of: %v", name, strings.Join(QueryFunctions, ", "))
+	}
+
+	p.next()
+	arg := p.next()
+
+	if arg == "" || arg == ")" {
+		return nil, fmt.Errorf("%v() needs an import path glob", name)
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "imports":
+		return neighbours(arg, func(g *queryGraph, pkg string) []string { return g.packageMap[pkg].Imports }, false), nil
+	case "importers":
+		return neighbours(arg, func(g *queryGraph, pkg string) []string { return g.importers[pkg] }, false), nil
+	case "deps":
+		return neighbours(arg, func(g *queryGraph, pkg string) []string { return g.packageMap[pkg].Imports }, true), nil
+	case "rdeps":
+		return neighbours(arg, func(g *queryGraph, pkg string) []string { return g.importers[pkg] }, true), nil
+	}
+
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+
+		for pkg, packageInfo := range g.packageMap {
+			if packageInfo.Layer == arg {
+				selected[pkg] = true
+			}
+		}
+
+		return selected
+	}, nil
+}
+
+// parseComparison parses the comparison of an attribute with a number
+func (p *queryParser) parseComparison(attribute string) (queryNode, error) {
+	op := p.next()
+
+	if !contains([]string{"=", "!=", "<", "<=", ">", ">="}, op) {
+		return nil, fmt.Errorf("%v needs a comparison, such as %v>1", attribute, attribute)
+	}
+
+	value, err := strconv.Atoi(p.next())
+	if err != nil {
+		return nil, fmt.Errorf("%v%v needs a number", attribute, op)
+	}
+
+	compare := map[string]func(int) bool{
+		"=":  func(n int) bool { return n == value },
+		"!=": func(n int) bool { return n != value },
+		"<":  func(n int) bool { return n < value },
+		"<=": func(n int) bool { return n <= value },
+		">":  func(n int) bool { return n > value },
+		">=": func(n int) bool { return n >= value },
+	}[op]
+
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+
+		for pkg, packageInfo := range g.packageMap {
+			n := 0
+
+			switch attribute {
+			case "level":
+				n = packageInfo.Level
+			case "fanin":
+				n = len(g.importers[pkg])
+			case "fanout":
+				n = len(packageInfo.Imports)
+			case "violations":
+				n = g.violations[pkg]
+			}
+
+			if compare(n) {
+				selected[pkg] = true
+			}
+		}
+
+		return selected
+	}, nil
+}
+
+// matching selects the packages matching an import path glob
+func matching(pattern string) queryNode {
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+
+		for pkg := range g.packageMap {
+			if matchPackagePattern(strings.TrimPrefix(pattern, "/"), pkg) {
+				selected[pkg] = true
+			}
+		}
+
+		return selected
+	}
+}
+
+// neighbours selects the packages next to the packages matching pattern, or reachable from them when transitive
+func neighbours(pattern string, next func(*queryGraph, string) []string, transitive bool) queryNode {
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+		queue := sortedSet(matching(pattern)(g))
+
+		for len(queue) > 0 {
+			pkg := queue[0]
+			queue = queue[1:]
+
+			for _, neighbour := range next(g, pkg) {
+				if selected[neighbour] {
+					continue
+				}
+
+				selected[neighbour] = true
+
+				if transitive {
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+
+		return selected
+	}
+}
+
+func union(a queryNode, b queryNode) queryNode {
+	return func(g *queryGraph) map[string]bool {
+		selected := a(g)
+
+		for pkg := range b(g) {
+			selected[pkg] = true
+		}
+
+		return selected
+	}
+}
+
+func intersection(a queryNode, b queryNode) queryNode {
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+		other := b(g)
+
+		for pkg := range a(g) {
+			if other[pkg] {
+				selected[pkg] = true
+			}
+		}
+
+		return selected
+	}
+}
+
+func complement(a queryNode) queryNode {
+	return func(g *queryGraph) map[string]bool {
+		selected := make(map[string]bool)
+		excluded := a(g)
+
+		for pkg := range g.packageMap {
+			if !excluded[pkg] {
+				selected[pkg] = true
+			}
+		}
+
+		return selected
+	}
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	ModPath = "github.com/foo/bar"
+
+	packageMap := map[string]PackageInfo{
+		"github.com/foo/bar":                 {Level: 0, Imports: []string{"github.com/foo/bar/app"}},
+		"github.com/foo/bar/app":             {Level: 1, Layer: "app", Imports: []string{"github.com/foo/bar/internal/domain"}},
+		"github.com/foo/bar/internal/db":     {Level: 1, Layer: "infra", Imports: []string{"github.com/foo/bar/internal/domain"}},
+		"github.com/foo/bar/internal/domain": {Level: 2, Layer: "domain"},
+	}
+
+	violations := []Violation{{From: "github.com/foo/bar/internal/db"}, {From: "github.com/foo/bar/app", Suppressed: true}}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"importers(internal/domain)", []string{"github.com/foo/bar/app", "github.com/foo/bar/internal/db"}},
+		{"importers(internal/domain) & layer(infra)", []string{"github.com/foo/bar/internal/db"}},
+		{"rdeps(internal/domain) & level<1", []string{"github.com/foo/bar"}},
+		{"deps(.)", []string{"github.com/foo/bar/app", "github.com/foo/bar/internal/domain"}},
+		{"internal/... & !(fanin>=2 | violations>0)", []string{}},
+		{"fanout=0 | imports(app)", []string{"github.com/foo/bar/internal/domain"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			if got := q.Eval(packageMap, violations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery_errors(t *testing.T) {
+	for _, query := range []string{"", "importers(", "level>", "level>x", "upstream(app)", "app &", "(app", "app)", "& app"} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) error = nil", query)
+		}
+	}
+}
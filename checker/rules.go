@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"fmt"
+)
+
+// Rule allows or forbids the imports from packages matching From to packages matching To.
+// Allowed imports are exempt from the level checks, forbidden imports are always reported.
+type Rule struct {
+	// From and To are import path globs
+	From  string `yaml:"from"`
+	To    string `yaml:"to"`
+	Allow bool   `yaml:"allow"`
+}
+
+// BannedImport is an import forbidden anywhere in the project
+type BannedImport struct {
+	// Path is an import path glob
+	Path string `yaml:"path"`
+	// Message tells what to use instead
+	Message string `yaml:"message"`
+}
+
+// matchRule returns the first rule matching the import of pkgImport by pkg
+func matchRule(rules []Rule, pkg string, pkgImport string) (Rule, bool) {
+	for _, rule := range rules {
+		if matchPackagePattern(rule.From, pkg) && matchPackagePattern(rule.To, pkgImport) {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+// isAllowedByRule reports whether the first rule matching the import allows it
+func isAllowedByRule(rules []Rule, pkg string, pkgImport string) bool {
+	rule, ok := matchRule(rules, pkg, pkgImport)
+
+	return ok && rule.Allow
+}
+
+// CheckRules reports the imports forbidden by the first matching rule
+func CheckRules(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		for _, pkgImport := range packageMap[pkg].Imports {
+			rule, ok := matchRule(opts.Rules, pkg, pkgImport)
+
+			if !ok || rule.Allow {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Import forbidden by rule from: %v to: %v", rule.From, rule.To)
+
+			found.add(packageMap, RuleForbiddenImport, errMsg, pkg, pkgImport)
+		}
+	}
+
+	return found.all()
+}
+
+// CheckBanned reports the imports of project, standard library and third-party packages matching a banned import
+func CheckBanned(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	if len(opts.Banned) == 0 {
+		return nil
+	}
+
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[pkg]
+		sites := make(map[string][]ImportSite)
+
+		// module pseudo packages stand for the third-party imports, which are checked by their full path
+		for _, pkgImport := range packageInfo.Imports {
+			if !packageMap[pkgImport].External {
+				sites[pkgImport] = packageInfo.ImportSites[pkgImport]
+			}
+		}
+
+		for _, imports := range []map[string][]ImportSite{packageInfo.StandardImports, packageInfo.ThirdPartyImports} {
+			for pkgImport, importSites := range imports {
+				sites[pkgImport] = importSites
+			}
+		}
+
+		for _, pkgImport := range sortedKeys(sites) {
+			for _, banned := range opts.Banned {
+				if !matchPackagePattern(banned.Path, pkgImport) {
+					continue
+				}
+
+				errMsg := fmt.Sprintf("Import of %v is banned", pkgImport)
+
+				if banned.Message != "" {
+					errMsg = fmt.Sprintf("Import of %v is banned: %v", pkgImport, banned.Message)
+				}
+
+				violation := newViolation(packageMap, RuleBannedImport, errMsg, pkg, pkgImport)
+				violation.Locations = sites[pkgImport]
+
+				found.record(packageMap, violation)
+
+				break
+			}
+		}
+	}
+
+	return found.all()
+}
@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckRules(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/ruled\n\ngo 1.22\n",
+		DefaultConfigFile: `rules:
+  - from: api
+    to: store
+    allow: true
+  - from: "*"
+    to: store
+`,
+		"api/api.go":     "package api\n\nimport (\n\t_ \"example.com/ruled/store\"\n\t_ \"example.com/ruled/user\"\n)\n",
+		"user/user.go":   "package user\n\nimport _ \"example.com/ruled/store\"\n",
+		"store/store.go": "package store\n",
+	})
+
+	// the first matching rule applies, the allow rule of api comes before the rule forbidding the others
+	want := []string{RuleForbiddenImport + " /user -> /store"}
+
+	if got := imports(CheckRules(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckRules() = %q, want %q", got, want)
+	}
+
+	f.opts.Rules = nil
+
+	if got := CheckRules(f.packageMap, f.opts); len(got) != 0 {
+		t.Errorf("CheckRules() without rules = %q, want none", imports(got))
+	}
+}
+
+func TestCheckLevels_allowedByRule(t *testing.T) {
+	files := map[string]string{
+		"go.mod":         "module example.com/ruled\n\ngo 1.22\n",
+		"store/store.go": "package store\n",
+		"user/user.go":   "package user\n\nimport _ \"example.com/ruled/store\"\n",
+	}
+
+	layers := `layers:
+  - name: infra
+    packages: [store]
+  - name: domain
+    packages: [user]
+`
+
+	tests := []struct {
+		name  string
+		rules string
+		want  []string
+	}{
+		{"outer layer", "", []string{RuleOuterLayer + " /user -> /store"}},
+		{"allowed by a rule", "rules:\n  - from: user\n    to: store\n    allow: true\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files[DefaultConfigFile] = layers + tt.rules
+			f := mapFixture(t, files)
+
+			if got := imports(f.checkLevels(t)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckLevels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBanned(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/banned\n\ngo 1.22\n\nrequire github.com/pkg/errors v0.9.1\n",
+		DefaultConfigFile: `banned:
+  - path: github.com/pkg/errors
+    message: use the errors package of the standard library
+  - path: unsafe
+  - path: legacy/...
+`,
+		"legacy/client/client.go": "package client\n",
+		"user/user.go":            "package user\n\nimport (\n\t_ \"fmt\"\n\t_ \"unsafe\"\n\n\t_ \"github.com/pkg/errors\"\n)\n",
+		"api/api.go":              "package api\n\nimport _ \"example.com/banned/legacy/client\"\n",
+		"store/store.go":          "package store\n\nimport _ \"example.com/banned/user\"\n",
+	})
+
+	violations := CheckBanned(f.packageMap, f.opts)
+
+	want := []string{
+		RuleBannedImport + " /api -> /legacy/client",
+		RuleBannedImport + " /user -> github.com/pkg/errors",
+		RuleBannedImport + " /user -> unsafe",
+	}
+
+	if got := imports(violations); !reflect.DeepEqual(got, want) {
+		t.Fatalf("CheckBanned() = %q, want %q", got, want)
+	}
+
+	if want := "Import of github.com/pkg/errors is banned: use the errors package of the standard library"; violations[1].Message != want {
+		t.Errorf("message = %v, want %v", violations[1].Message, want)
+	}
+
+	if len(violations[2].Locations) != 1 || violations[2].Locations[0].File != "user/user.go" {
+		t.Errorf("locations = %v, want the import in user/user.go", violations[2].Locations)
+	}
+}
@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mapSnapshot holds the directories mapped by a run of Map, for incremental runs
+type mapSnapshot struct {
+	Dirs map[string]snapshotDir `json:"dirs"`
+}
+
+// snapshotDir is a mapped directory with the stamp of its files at the time
+type snapshotDir struct {
+	Stamp  string    `json:"stamp"`
+	Mapped mappedDir `json:"mapped"`
+}
+
+// dirStamp summarizes the names, sizes and modification times of the files of a directory,
+// it returns an empty string if a file can not be read
+func dirStamp(dir string, fileNames []string) string {
+	var b strings.Builder
+
+	for _, fileName := range fileNames {
+		info, err := os.Stat(filepath.Join(dir, fileName))
+		if err != nil {
+			return ""
+		}
+
+		fmt.Fprintf(&b, "%v:%v:%v;", fileName, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return b.String()
+}
+
+// snapshotPath returns the snapshot file of the module in workdir mapped with opts, snapshots of other
+// options or go.mod contents do not apply
+func snapshotPath(workdir string, opts MapOptions) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%v\n%v\n%v\n%v\n%v\n", cacheVersion, workdir, ModPath, ModRequires, ModReplaces)
+	fmt.Fprintf(hash, "%v %v %v %v %v %v\n", opts.IgnoreTests, opts.SeparateTests, opts.External, opts.Vendor, opts.IncludeGenerated, opts.Replaced)
+	fmt.Fprintf(hash, "%q %q %q %v %v\n", opts.Exclude, opts.Include, opts.SkipDirs, opts.GitIgnore, opts.Loader)
+
+	if opts.BuildContext != nil {
+		fmt.Fprintf(hash, "%v %v %q %v\n", opts.BuildContext.GOOS, opts.BuildContext.GOARCH, opts.BuildContext.BuildTags, opts.BuildContext.CgoEnabled)
+	}
+
+	return filepath.Join(CacheDir, "maps", hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+// loadMapSnapshot returns the snapshot of the previous run, it is empty if there is none
+func loadMapSnapshot(workdir string, opts MapOptions) mapSnapshot {
+	var snapshot mapSnapshot
+
+	if CacheDir == "" {
+		return snapshot
+	}
+
+	data, err := os.ReadFile(snapshotPath(workdir, opts))
+	if err != nil {
+		return snapshot
+	}
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return mapSnapshot{}
+	}
+
+	return snapshot
+}
+
+// storeMapSnapshot saves the snapshot for the next run, errors are ignored as the snapshot is an optimization
+func storeMapSnapshot(workdir string, opts MapOptions, snapshot mapSnapshot) {
+	if CacheDir == "" {
+		return
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+
+	writeCacheFile(snapshotPath(workdir, opts), data)
+}
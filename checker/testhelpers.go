@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultTestHelpers are the package name globs of test helper packages, used unless configured otherwise
+var DefaultTestHelpers = []string{"*test", "testutil", "fixtures", "mocks"}
+
+// CheckTestHelpers reports the imports of test helper packages by non-test files. Test helpers are project,
+// standard library and third-party packages whose name matches one of opts.TestHelpers, or DefaultTestHelpers
+// when not set. Test helper packages may import each other.
+func CheckTestHelpers(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	names := opts.TestHelpers
+	if names == nil {
+		names = DefaultTestHelpers
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[pkg]
+
+		if isTestHelper(names, pkg) {
+			continue
+		}
+
+		sites := make(map[string][]ImportSite)
+
+		// module pseudo packages stand for the third-party imports, which are checked by their full path
+		for _, pkgImport := range packageInfo.Imports {
+			if !packageMap[pkgImport].External {
+				sites[pkgImport] = packageInfo.ImportSites[pkgImport]
+			}
+		}
+
+		for _, imports := range []map[string][]ImportSite{packageInfo.StandardImports, packageInfo.ThirdPartyImports} {
+			for pkgImport, importSites := range imports {
+				sites[pkgImport] = importSites
+			}
+		}
+
+		for _, pkgImport := range sortedKeys(sites) {
+			if !isTestHelper(names, pkgImport) {
+				continue
+			}
+
+			productionSites := nonTestSites(sites[pkgImport])
+			if len(productionSites) == 0 {
+				continue
+			}
+
+			errMsg := fmt.Sprintf("Production code imports the test helper %v", pkgImport)
+
+			violation := newViolation(packageMap, RuleTestHelperImport, errMsg, pkg, pkgImport)
+			violation.Locations = productionSites
+
+			found.record(packageMap, violation)
+		}
+	}
+
+	return found.all()
+}
+
+// isTestHelper reports whether the name of the package, the last element of its import path, matches one
+// of the test helper name globs
+func isTestHelper(names []string, importPath string) bool {
+	name := path.Base(importPath)
+
+	for _, pattern := range names {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nonTestSites returns the import declarations outside of _test.go files
+func nonTestSites(sites []ImportSite) []ImportSite {
+	var production []ImportSite
+
+	for _, site := range sites {
+		if !strings.HasSuffix(site.File, "_test.go") {
+			production = append(production, site)
+		}
+	}
+
+	return production
+}
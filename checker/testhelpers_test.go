@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_isTestHelper(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       bool
+	}{
+		{"net/http/httptest", true},
+		{"example.com/app/internal/testutil", true},
+		{"example.com/app/internal/user/mocks", true},
+		{"example.com/app/fixtures", true},
+		{"example.com/app/internal/testutil/db", false},
+		{"example.com/app/internal/mock", false},
+		{"testing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			if got := isTestHelper(DefaultTestHelpers, tt.importPath); got != tt.want {
+				t.Errorf("isTestHelper() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTestHelpers(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod":                           "module example.com/tested\n\ngo 1.22\n",
+		"main.go":                          "package main\n\nimport (\n\t_ \"example.com/tested/internal/testutil\"\n\t_ \"example.com/tested/user\"\n)\n\nfunc main() {}\n",
+		"user/user.go":                     "package user\n\nimport _ \"net/http/httptest\"\n",
+		"user/user_test.go":                "package user\n\nimport (\n\t_ \"net/http/httptest\"\n\t_ \"example.com/tested/internal/testutil\"\n)\n",
+		"internal/testutil/testutil.go":    "package testutil\n\nimport _ \"example.com/tested/internal/testutil/mocks\"\n",
+		"internal/testutil/mocks/mocks.go": "package mocks\n",
+	})
+
+	// imports by test files and by the test helpers themselves are not reported
+	want := []string{RuleTestHelperImport + " / -> /internal/testutil", RuleTestHelperImport + " /user -> net/http/httptest"}
+
+	if got := imports(CheckTestHelpers(f.packageMap, f.opts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckTestHelpers() = %q, want %q", got, want)
+	}
+
+	f.opts.TestHelpers = []string{}
+
+	if got := CheckTestHelpers(f.packageMap, f.opts); len(got) != 0 {
+		t.Errorf("CheckTestHelpers() without test helpers = %v, want none", got)
+	}
+}
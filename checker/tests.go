@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"fmt"
+)
+
+// CheckTests checks the test graph, the imports of test files mapped with MapOptions.SeparateTests.
+// The first matching test rule allows or forbids an import, otherwise tests may import packages of the
+// same and inner levels, but not of outer levels. The violations are marked as Test.
+func CheckTests(packageMap map[string]PackageInfo, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		packageInfo := packageMap[pkg]
+
+		for _, pkgImport := range packageInfo.TestImports {
+			var violation Violation
+
+			if rule, ok := matchRule(opts.TestRules, pkg, pkgImport); ok {
+				if rule.Allow {
+					continue
+				}
+
+				errMsg := fmt.Sprintf("Test import forbidden by rule from: %v to: %v", rule.From, rule.To)
+				violation = newViolation(packageMap, RuleForbiddenImport, errMsg, pkg, pkgImport)
+			} else if packageMap[pkgImport].Level < packageInfo.Level {
+				violation = newViolation(packageMap, RuleTestOuterImport, "Test imports a package of an outer level", pkg, pkgImport)
+			} else {
+				continue
+			}
+
+			violation.Locations = packageInfo.TestImportSites[pkgImport]
+			violation.Test = true
+
+			found.record(packageMap, violation)
+		}
+	}
+
+	return found.all()
+}
@@ -0,0 +1,38 @@
+package checker
+
+import "testing"
+
+func TestCheckTests(t *testing.T) {
+	packageMap := map[string]PackageInfo{
+		"m/app":    {Path: "m/app", Level: 1, TestImports: []string{"m/domain", "m/cmd", "m/infra"}},
+		"m/cmd":    {Path: "m/cmd", Level: 0},
+		"m/infra":  {Path: "m/infra", Level: 0},
+		"m/domain": {Path: "m/domain", Level: 2},
+	}
+
+	tests := []struct {
+		name  string
+		rules []Rule
+		want  map[string]string
+	}{
+		{"outer levels", nil, map[string]string{"m/cmd": RuleTestOuterImport, "m/infra": RuleTestOuterImport}},
+		{"allowed by rule", []Rule{{From: "m/app", To: "m/infra", Allow: true}}, map[string]string{"m/cmd": RuleTestOuterImport}},
+		{"forbidden by rule", []Rule{{From: "m/app", To: "m/domain"}}, map[string]string{"m/domain": RuleForbiddenImport, "m/cmd": RuleTestOuterImport, "m/infra": RuleTestOuterImport}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckTests(packageMap, CheckOptions{TestRules: tt.rules})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("CheckTests() = %v violations, want %v", len(got), len(tt.want))
+			}
+
+			for _, violation := range got {
+				if tt.want[violation.To] != violation.Rule || !violation.Test {
+					t.Errorf("CheckTests() reported %v for %v, want %v", violation.Rule, violation.To, tt.want[violation.To])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadTypes type checks the packages of the module in workdir with go/packages and returns them by import path.
+// Like LoaderPackages, it needs the go command and the dependencies of the module. Type errors are returned
+// as warnings, the packages are still returned with the types that could be checked.
+// The dependencies are type checked from source too, the export data of the go command may be newer than the
+// versions go/packages can read. Loading stops with the error of ctx when ctx is done.
+func LoadTypes(ctx context.Context, workdir string, opts MapOptions) (map[string]*packages.Package, []clog.CheckResult, error) {
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps
+
+	pkgs, err := packages.Load(packagesConfig(ctx, workdir, opts, mode), "./...")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []clog.CheckResult
+
+	typed := make(map[string]*packages.Package, len(pkgs))
+
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			results = append(results, clog.NewWarning(pkgErr.Error()))
+		}
+
+		if pkg.Types != nil {
+			typed[pkg.PkgPath] = pkg
+		}
+	}
+
+	return typed, results, nil
+}
+
+// exposure is a type of another package used in the exported API of a package
+type exposure struct {
+	typeName *types.TypeName
+	// object is the exported declaration exposing the type
+	object types.Object
+}
+
+// CheckTypeLeaks reports the packages exposing types of project packages of an outer level in their exported API:
+// function parameters and results, exported struct fields, methods, variables and type definitions.
+// An inner package leaking outer types makes its importers depend on the outer package too.
+func CheckTypeLeaks(workdir string, packageMap map[string]PackageInfo, typed map[string]*packages.Package, opts CheckOptions) []Violation {
+	found := newViolations(opts)
+
+	for _, pkg := range sortedPackagePaths(packageMap) {
+		typedPkg, ok := typed[pkg]
+
+		if !ok || packageMap[pkg].External {
+			continue
+		}
+
+		leaks := make(map[string][]exposure)
+
+		for _, exposed := range exposedTypes(typedPkg.Types) {
+			exposedPkg := exposed.typeName.Pkg().Path()
+			exposedInfo, ok := packageMap[exposedPkg]
+
+			if !ok || exposedInfo.External || exposedInfo.Level >= packageMap[pkg].Level {
+				continue
+			}
+
+			leaks[exposedPkg] = append(leaks[exposedPkg], exposed)
+		}
+
+		outerPkgs := make([]string, 0, len(leaks))
+
+		for outerPkg := range leaks {
+			outerPkgs = append(outerPkgs, outerPkg)
+		}
+
+		sort.Strings(outerPkgs)
+
+		for _, outerPkg := range outerPkgs {
+			var typeNames, objects []string
+			var locations []ImportSite
+
+			for _, exposed := range leaks[outerPkg] {
+				typeName := shortPackagePath(outerPkg) + "." + exposed.typeName.Name()
+				typeNames = AppendStringIfMissing(typeNames, typeName)
+
+				if contains(objects, exposed.object.Name()) {
+					continue
+				}
+
+				objects = append(objects, exposed.object.Name())
+
+				position := typedPkg.Fset.Position(exposed.object.Pos())
+
+				if rel, err := filepath.Rel(workdir, position.Filename); err == nil && position.IsValid() {
+					locations = append(locations, ImportSite{File: filepath.ToSlash(rel), Line: position.Line})
+				}
+			}
+
+			errMsg := fmt.Sprintf("Exported API exposes %v of an outer level through %v", strings.Join(typeNames, ", "), strings.Join(objects, ", "))
+
+			violation := newViolation(packageMap, RuleTypeLeak, errMsg, pkg, outerPkg)
+			violation.Locations = locations
+
+			found.record(packageMap, violation)
+		}
+	}
+
+	return found.all()
+}
+
+// exposedTypes returns the named types of other packages used by the exported declarations of pkg
+func exposedTypes(pkg *types.Package) []exposure {
+	var exposures []exposure
+
+	scope := pkg.Scope()
+
+	for _, name := range scope.Names() {
+		object := scope.Lookup(name)
+
+		if !object.Exported() {
+			continue
+		}
+
+		seen := make(map[types.Type]bool)
+
+		walkExposedType(object.Type(), pkg, seen, func(typeName *types.TypeName) {
+			exposures = append(exposures, exposure{typeName: typeName, object: object})
+		})
+	}
+
+	return exposures
+}
+
+// walkExposedType calls found with the named types of other packages reachable from t through exported
+// fields and methods. The types of other packages are not walked, they are the exposed types.
+func walkExposedType(t types.Type, own *types.Package, seen map[types.Type]bool, found func(*types.TypeName)) {
+	t = types.Unalias(t)
+
+	if seen[t] {
+		return
+	}
+
+	seen[t] = true
+
+	switch t := t.(type) {
+	case *types.Named:
+		if t.Obj().Pkg() != nil && t.Obj().Pkg() != own {
+			found(t.Obj())
+		} else {
+			walkExposedType(t.Underlying(), own, seen, found)
+
+			for i := 0; i < t.NumMethods(); i++ {
+				if t.Method(i).Exported() {
+					walkExposedType(t.Method(i).Type(), own, seen, found)
+				}
+			}
+		}
+
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			walkExposedType(t.TypeArgs().At(i), own, seen, found)
+		}
+	case *types.Pointer:
+		walkExposedType(t.Elem(), own, seen, found)
+	case *types.Slice:
+		walkExposedType(t.Elem(), own, seen, found)
+	case *types.Array:
+		walkExposedType(t.Elem(), own, seen, found)
+	case *types.Chan:
+		walkExposedType(t.Elem(), own, seen, found)
+	case *types.Map:
+		walkExposedType(t.Key(), own, seen, found)
+		walkExposedType(t.Elem(), own, seen, found)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				walkExposedType(tuple.At(i).Type(), own, seen, found)
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if t.Field(i).Exported() {
+				walkExposedType(t.Field(i).Type(), own, seen, found)
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			if t.ExplicitMethod(i).Exported() {
+				walkExposedType(t.ExplicitMethod(i).Type(), own, seen, found)
+			}
+		}
+
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			walkExposedType(t.EmbeddedType(i), own, seen, found)
+		}
+	case *types.TypeParam:
+		walkExposedType(t.Constraint(), own, seen, found)
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			walkExposedType(t.Term(i).Type(), own, seen, found)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestCheckTypeLeaks(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/leaky\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: infra
+    packages: [db, store]
+  - name: domain
+    packages: [user, order]
+`,
+		"db/db.go": "package db\n\ntype Conn struct{}\n",
+		// exported declarations exposing the types of an outer layer leak them
+		"user/user.go": "package user\n\nimport \"example.com/leaky/db\"\n\ntype User struct{}\n\nfunc Find(conn *db.Conn) User { return User{} }\n",
+		// unexported declarations do not
+		"order/order.go": "package order\n\nimport \"example.com/leaky/db\"\n\nfunc find(conn *db.Conn) {}\n",
+		// the types of inner layers may be exposed
+		"store/store.go": "package store\n\nimport \"example.com/leaky/user\"\n\nfunc Save(u user.User) {}\n",
+	})
+
+	typed, _, err := LoadTypes(context.Background(), f.dir, MapOptions{})
+	if err != nil {
+		t.Fatalf("LoadTypes() error = %v", err)
+	}
+
+	violations := CheckTypeLeaks(f.dir, f.packageMap, typed, f.opts)
+
+	if want := []string{RuleTypeLeak + " /user -> /db"}; !reflect.DeepEqual(imports(violations), want) {
+		t.Fatalf("CheckTypeLeaks() = %q, want %q", imports(violations), want)
+	}
+
+	if want := []ImportSite{{File: "user/user.go", Line: 7}}; !reflect.DeepEqual(violations[0].Locations, want) {
+		t.Errorf("locations = %v, want %v", violations[0].Locations, want)
+	}
+}
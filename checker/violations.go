@@ -0,0 +1,532 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation rules
+const (
+	RuleSameLevel            = "same-level"
+	RuleStrictLevel          = "strict-level"
+	RuleOuterLayer           = "outer-layer"
+	RuleLayerSkip            = "layer-skip"
+	RuleForbiddenImport      = "forbidden-import"
+	RuleImportCycle          = "import-cycle"
+	RuleTypeLeak             = "type-leak"
+	RuleDependencyInversion  = "dependency-inversion"
+	RuleCrossFeature         = "cross-feature"
+	RuleStdlibImport         = "stdlib-import"
+	RuleThirdPartyImport     = "third-party-import"
+	RuleBannedImport         = "banned-import"
+	RuleCrossContext         = "cross-context"
+	RuleTestOuterImport      = "test-outer-import"
+	RuleTestHelperImport     = "test-helper-import"
+	RuleUndeclaredDependency = "undeclared-dependency"
+)
+
+// Severities of the violations, only errors fail the check
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// ViolationRule describes a rule checked by uncle-bob
+type ViolationRule struct {
+	ID string
+	// Code is the stable identifier of the rule in all outputs (ex. UB001), it never changes once assigned
+	Code        string
+	Description string
+	Suggestion  string
+	// Options are the refactoring options shown by the explain command, {from} and {to} stand for the packages
+	Options []string
+}
+
+// ViolationRules lists the rules checked by uncle-bob
+var ViolationRules = []ViolationRule{
+	{
+		ID:          RuleSameLevel,
+		Code:        "UB001",
+		Description: "A package imports a package of the same level.",
+		Suggestion:  "Move the shared code into a package of an inner level, or let the importing package declare an interface the imported package satisfies.",
+		Options: []string{
+			"Move the code {from} uses into a new package on an inner level, imported by both {from} and {to}.",
+			"Declare an interface in {from} for what it needs and let {to} implement it, passing the implementation in from an outer package.",
+			"Merge {from} and {to} if they change together and always belong together.",
+		},
+	},
+	{
+		ID:          RuleOuterLayer,
+		Code:        "UB002",
+		Description: "A package imports a package of an outer declared layer.",
+		Suggestion:  "Invert the dependency: declare an interface in the inner layer and implement it in the outer layer.",
+		Options: []string{
+			"Declare an interface in the layer of {from} and implement it in {to}, the outer layer passes the implementation in.",
+			"Move the code {from} needs from {to} into the layer of {from} or an inner layer.",
+			"Pass the data {from} needs as plain values instead of importing {to}.",
+		},
+	},
+	{
+		ID:          RuleStrictLevel,
+		Code:        "UB003",
+		Description: "In strict mode, a package imports a package other than of the next inner level.",
+		Suggestion:  "Only import packages of the next inner level, move the imported code or go through an intermediate package.",
+		Options: []string{
+			"Import the package of the next inner level that wraps {to} instead of reaching {to} directly.",
+			"Add a function to the package of the next inner level that delegates to {to}.",
+			"Move {to} or the code {from} needs one level outward.",
+		},
+	},
+	{
+		ID:          RuleLayerSkip,
+		Code:        "UB004",
+		Description: "In strict mode, a package imports a package skipping a declared layer.",
+		Suggestion:  "Go through the next inner layer instead of reaching into deeper layers directly.",
+		Options: []string{
+			"Go through the next inner layer, add the needed function there and let it call {to}.",
+			"Move the code {from} needs from {to} into the next inner layer.",
+		},
+	},
+	{
+		ID:          RuleForbiddenImport,
+		Code:        "UB005",
+		Description: "A package import is forbidden by a configured rule.",
+		Suggestion:  "Remove the import or move the code, the dependency is forbidden by the configuration.",
+		Options: []string{
+			"Remove the import of {to} from {from}, the configured rule forbids it.",
+			"Move the code {from} needs from {to} into a package {from} is allowed to import.",
+			"If the dependency is intended, change the rule or add an allow rule before it.",
+		},
+	},
+	{
+		ID:          RuleImportCycle,
+		Code:        "UB006",
+		Description: "Packages import each other, directly or through other packages.",
+		Suggestion:  "Break the cycle: move the shared code into a new package both can import, or invert one of the dependencies with an interface.",
+		Options: []string{
+			"Move the code both packages share into a new package imported by all packages of the cycle.",
+			"Invert one of the imports: declare an interface in the importing package and implement it in the imported one.",
+			"Merge the packages of the cycle if they always change together.",
+		},
+	},
+	{
+		ID:          RuleTypeLeak,
+		Code:        "UB007",
+		Description: "The exported API of a package exposes types of a package of an outer level.",
+		Suggestion:  "Expose types of the package or of inner levels instead, map the outer types at the boundary or declare an interface.",
+		Options: []string{
+			"Declare the exposed types in {from} or an inner package and map the types of {to} at the boundary.",
+			"Expose an interface declared in {from} instead of the concrete types of {to}.",
+			"Move the exported functions exposing {to} to the outer package.",
+		},
+	},
+	{
+		ID:          RuleDependencyInversion,
+		Code:        "UB008",
+		Description: "A package only uses concrete types with methods of an inner package, no interface.",
+		Suggestion:  "Depend on an interface of the inner package, or declare one where it is used, and pass the implementation in.",
+		Options: []string{
+			"Use an interface of {to}, or declare one in {from} with the methods it calls.",
+			"Accept the interface as a parameter or struct field and let an outer package pass the concrete type of {to} in.",
+		},
+	},
+	{
+		ID:          RuleCrossFeature,
+		Code:        "UB009",
+		Description: "A package of a feature imports a package of another feature.",
+		Suggestion:  "Move the shared code into a shared package, or let the features communicate through an interface or events.",
+		Options: []string{
+			"Move the code {from} uses from {to} into a shared package.",
+			"Let the features communicate through an interface declared by {from} or through events.",
+			"Merge the features if they are not independent.",
+		},
+	},
+	{
+		ID:          RuleStdlibImport,
+		Code:        "UB010",
+		Description: "A package imports a standard library package its declared layer may not import.",
+		Suggestion:  "Move the code using the package to an outer layer and reach it through an interface.",
+		Options: []string{
+			"Move the code using {to} to the layer allowed to import it and reach it through an interface declared in the layer of {from}.",
+			"If the layer should use {to}, change the stdlib policy of the layer.",
+		},
+	},
+	{
+		ID:          RuleThirdPartyImport,
+		Code:        "UB011",
+		Description: "A package imports a third-party package its declared layer may not import.",
+		Suggestion:  "Move the code using the package to the layer allowed to import it and reach it through an interface.",
+		Options: []string{
+			"Move the code using {to} to the layer allowed to import it and reach it through an interface declared in the layer of {from}.",
+			"Wrap {to} in an adapter package of an outer layer.",
+			"If the layer should use {to}, change the thirdParty policy of the layer.",
+		},
+	},
+	{
+		ID:          RuleBannedImport,
+		Code:        "UB012",
+		Description: "A package imports a package banned in the whole project.",
+		Suggestion:  "Use the replacement given in the message, the package is banned by the configuration.",
+		Options: []string{
+			"Replace {to} with the package given in the banned import message.",
+			"If the import is needed here, suppress it with an unclebob:ignore comment giving the reason.",
+		},
+	},
+	{
+		ID:          RuleCrossContext,
+		Code:        "UB013",
+		Description: "A package of a bounded context imports a package of another context that is not one of its API packages.",
+		Suggestion:  "Go through the API or anti-corruption layer of the other context, or publish the needed package in its api list.",
+		Options: []string{
+			"Import the api packages of the context of {to} instead of {to}.",
+			"Publish {to}, or an interface for it, in the api list of its context.",
+			"Add an anti-corruption layer package to the context of {from} translating the models of the other context.",
+		},
+	},
+	{
+		ID:          RuleTestOuterImport,
+		Code:        "UB014",
+		Description: "The test files of a package import a package of an outer level.",
+		Suggestion:  "Test the package through its own API and inner dependencies, move tests wiring outer packages to an outer level.",
+		Options: []string{
+			"Test {from} through its own API and fakes declared in the test files, instead of wiring {to}.",
+			"Move tests needing {to} to an integration test package on an outer level.",
+			"Allow the import with a test rule if the test is meant to cover both packages.",
+		},
+	},
+	{
+		ID:          RuleTestHelperImport,
+		Code:        "UB015",
+		Description: "A non-test file imports a test helper package, such as a *test, testutil, fixtures or mocks package.",
+		Suggestion:  "Only import test helpers from _test.go files, move the code production needs out of the helper package.",
+		Options: []string{
+			"Only import {to} from _test.go files of {from}.",
+			"Move the code {from} needs from {to} into a regular package.",
+			"Rename the package if it is not a test helper, or change the testHelpers globs.",
+		},
+	},
+	{
+		ID:          RuleUndeclaredDependency,
+		Code:        "UB016",
+		Description: "A package imports a package of a layer its own layer does not declare in dependsOn.",
+		Suggestion:  "Go through a layer the layer depends on, or declare the dependency in the architecture if it is intended.",
+		Options: []string{
+			"Reach the code {from} needs from {to} through a layer the layer of {from} depends on.",
+			"Move the code {from} needs from {to} into a layer the layer of {from} depends on.",
+			"If the dependency is intended, add the layer of {to} to the dependsOn list of the layer of {from}.",
+		},
+	},
+}
+
+// ruleDocsURL is the documentation of the rules, the lower case code of a rule is its anchor
+const ruleDocsURL = "https://github.com/audi70r/uncle-bob#"
+
+// DocURL returns the link to the documentation of the rule
+func (rule ViolationRule) DocURL() string {
+	return ruleDocsURL + strings.ToLower(rule.Code)
+}
+
+// LookupViolationRule returns the description of a rule by its ID or its code
+func LookupViolationRule(id string) (ViolationRule, bool) {
+	for _, rule := range ViolationRules {
+		if rule.ID == id || strings.EqualFold(rule.Code, id) {
+			return rule, true
+		}
+	}
+
+	return ViolationRule{}, false
+}
+
+// Unreported holds the violations left out of the check results by the options of the checks
+type Unreported struct {
+	// Unchanged are the violations without imports in the changed files
+	Unchanged []Violation
+	// Untargeted are the violations of packages that are not checked
+	Untargeted []Violation
+}
+
+// Violation is an import breaking one of the checks
+type Violation struct {
+	// ID identifies the violation for the explain command, it is derived from the rule and the packages
+	ID   string `json:"id"`
+	Rule string `json:"rule"`
+	// Code is the stable code of the rule, such as UB001
+	Code string `json:"code"`
+	// Fingerprint identifies the violation across runs and refactorings, it only depends on the rule and the packages
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	FromLevel   int    `json:"fromLevel"`
+	ToLevel     int    `json:"toLevel"`
+	FromLayer   string `json:"fromLayer,omitempty"`
+	ToLayer     string `json:"toLayer,omitempty"`
+	Suggestion  string `json:"suggestion"`
+	// Locations are the import declarations of To in the files of From, or the declarations of From exposing
+	// the types of To for a type-leak violation
+	Locations []ImportSite `json:"locations"`
+	// Suppressed is set for imports exempt by an "unclebob:ignore" comment, with the comment reason
+	Suppressed     bool   `json:"suppressed,omitempty"`
+	SuppressReason string `json:"suppressReason,omitempty"`
+	// Test is set for violations of the test graph, the imports of test files
+	Test bool `json:"test,omitempty"`
+	// Severity is SeverityError unless configured otherwise for the rule
+	Severity string `json:"severity,omitempty"`
+	// Cycle is the import cycle of an import-cycle violation, starting and ending with From
+	Cycle []string `json:"cycle,omitempty"`
+	// Platforms are the GOOS/GOARCH pairs the violation was found on, set by a build matrix analysis
+	Platforms []string `json:"platforms,omitempty"`
+}
+
+// String formats the violation for console output
+func (v Violation) String() string {
+	from := fmt.Sprintf("Lv%v: %v", v.FromLevel, shortPackagePath(v.From))
+	to := fmt.Sprintf("Lv%v: %v", v.ToLevel, shortPackagePath(v.To))
+
+	if v.FromLayer != "" {
+		from = fmt.Sprintf("Lv%v %v: %v", v.FromLevel, v.FromLayer, shortPackagePath(v.From))
+	}
+
+	if v.ToLayer != "" {
+		to = fmt.Sprintf("Lv%v %v: %v", v.ToLevel, v.ToLayer, shortPackagePath(v.To))
+	}
+
+	msg := fmt.Sprintf("%v\n%v <-- %v \n", v.Message, from, to)
+
+	if v.ID != "" {
+		msg = fmt.Sprintf("%v: %v [%v]\n%v <-- %v \n", v.Code, v.Message, v.ID, from, to)
+	}
+
+	if v.Suppressed {
+		msg = fmt.Sprintf("%vsuppressed: %v \n", msg, v.SuppressReason)
+	}
+
+	return msg
+}
+
+// DocURL returns the link to the documentation of the rule of the violation
+func (v Violation) DocURL() string {
+	rule, _ := LookupViolationRule(v.Rule)
+
+	return rule.DocURL()
+}
+
+// Fails reports whether the violation fails the check, suppressed violations and violations of rules
+// configured with a lower severity do not
+func (v Violation) Fails() bool {
+	return !v.Suppressed && (v.Severity == "" || v.Severity == SeverityError)
+}
+
+// violations collects check results, keeping the imports suppressed by "unclebob:ignore" comments apart
+type violations struct {
+	found      []Violation
+	suppressed []Violation
+	// changedFiles limits the violations to imports in these files when set
+	changedFiles []string
+	// packages limits the violations to the imports of the packages matching these globs when set
+	packages []string
+	// unreported collects the violations left out by changedFiles and packages, when set
+	unreported *Unreported
+	// severity maps rule IDs or codes to their configured severity
+	severity map[string]string
+	// enabled and disabled filter the reported rules by ID or code, all rules are reported when enabled is empty
+	enabled  []string
+	disabled []string
+}
+
+// newViolations returns a collector for the checks run with opts
+func newViolations(opts CheckOptions) violations {
+	unreported := opts.Unreported
+	if unreported == nil {
+		unreported = &Unreported{}
+	}
+
+	return violations{
+		changedFiles: opts.ChangedFiles,
+		packages:     opts.Packages,
+		unreported:   unreported,
+		severity:     opts.Severity,
+		enabled:      opts.EnabledRules,
+		disabled:     opts.DisabledRules,
+	}
+}
+
+// reports reports whether the violations of a rule are reported, according to the enabled and disabled rules
+func (v *violations) reports(rule ViolationRule) bool {
+	if matchesRule(v.disabled, rule) {
+		return false
+	}
+
+	return len(v.enabled) == 0 || matchesRule(v.enabled, rule)
+}
+
+// matchesRule reports whether one of ids is the ID or the code of rule
+func matchesRule(ids []string, rule ViolationRule) bool {
+	for _, id := range ids {
+		if id == rule.ID || strings.EqualFold(id, rule.Code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inChangedFile reports whether one of the imports causing the violation is in a changed file
+func (v *violations) inChangedFile(violation Violation) bool {
+	for _, location := range violation.Locations {
+		if contains(v.changedFiles, location.File) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add records a violation of rule by the import of pkgImport by pkg, unless the import is suppressed
+func (v *violations) add(packageMap map[string]PackageInfo, rule string, message string, pkg string, pkgImport string) {
+	v.record(packageMap, newViolation(packageMap, rule, message, pkg, pkgImport))
+}
+
+// newViolation describes a violation of rule by the import of pkgImport by pkg
+func newViolation(packageMap map[string]PackageInfo, rule string, message string, pkg string, pkgImport string) Violation {
+	violationRule, _ := LookupViolationRule(rule)
+
+	return Violation{
+		Rule:       rule,
+		Message:    message,
+		From:       pkg,
+		To:         pkgImport,
+		FromLevel:  packageMap[pkg].Level,
+		ToLevel:    packageMap[pkgImport].Level,
+		FromLayer:  packageMap[pkg].Layer,
+		ToLayer:    packageMap[pkgImport].Layer,
+		Suggestion: violationRule.Suggestion,
+		Locations:  packageMap[pkg].ImportSites[pkgImport],
+	}
+}
+
+// record records a violation, unless its import is suppressed, outside the changed files or of another package
+func (v *violations) record(packageMap map[string]PackageInfo, violation Violation) {
+	pkg, pkgImport := violation.From, violation.To
+
+	rule, _ := LookupViolationRule(violation.Rule)
+	if !v.reports(rule) {
+		return
+	}
+
+	violation.ID = violationID(violation)
+	violation.Code = rule.Code
+	violation.Fingerprint = violationFingerprint(violation)
+	violation.Severity = SeverityError
+
+	if severity, ok := v.severity[violation.Rule]; ok {
+		violation.Severity = severity
+	} else if severity, ok := v.severity[rule.Code]; ok {
+		violation.Severity = severity
+	}
+
+	if v.changedFiles != nil && !v.inChangedFile(violation) {
+		if !containsViolation(v.unreported.Unchanged, violation) {
+			v.unreported.Unchanged = append(v.unreported.Unchanged, violation)
+		}
+
+		return
+	}
+
+	if v.packages != nil && !matchAnyPackagePattern(v.packages, pkg) {
+		if !containsViolation(v.unreported.Untargeted, violation) {
+			v.unreported.Untargeted = append(v.unreported.Untargeted, violation)
+		}
+
+		return
+	}
+
+	if reason, ok := suppressedRule(packageMap[pkg].Suppressed, pkgImport, rule); ok {
+		if reason == "" {
+			reason = "no reason given"
+		}
+
+		violation.Suppressed = true
+		violation.SuppressReason = reason
+
+		if !containsViolation(v.suppressed, violation) {
+			v.suppressed = append(v.suppressed, violation)
+		}
+
+		return
+	}
+
+	if !containsViolation(v.found, violation) {
+		v.found = append(v.found, violation)
+	}
+}
+
+// suppressedRule returns the reason of the "unclebob:ignore" comment suppressing the violations of rule by the
+// import of pkgImport. A comment starting with rule codes (ex. "unclebob:ignore UB001,UB006 reason") only
+// suppresses these rules, other comments suppress all rules.
+func suppressedRule(suppressed map[string]string, pkgImport string, rule ViolationRule) (string, bool) {
+	comment, ok := suppressed[pkgImport]
+	if !ok {
+		return "", false
+	}
+
+	codes, reason := suppressionCodes(comment)
+
+	if len(codes) > 0 && !matchesRule(codes, rule) {
+		return "", false
+	}
+
+	return reason, true
+}
+
+// suppressionCodes splits the rule codes off the start of the text of an "unclebob:ignore" comment
+func suppressionCodes(comment string) ([]string, string) {
+	var codes []string
+
+	for {
+		field, rest, _ := strings.Cut(comment, " ")
+
+		fieldCodes := strings.Split(strings.TrimSuffix(field, ","), ",")
+
+		for _, code := range fieldCodes {
+			if !isRuleCode(code) {
+				return codes, comment
+			}
+		}
+
+		codes = append(codes, fieldCodes...)
+		comment = strings.TrimSpace(rest)
+	}
+}
+
+// isRuleCode reports whether s has the form of a rule code, UB followed by digits
+func isRuleCode(s string) bool {
+	digits, ok := strings.CutPrefix(strings.ToUpper(s), "UB")
+	if !ok || digits == "" {
+		return false
+	}
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// all returns the violations followed by the suppressed ones
+func (v *violations) all() []Violation {
+	return append(append([]Violation{}, v.found...), v.suppressed...)
+}
+
+func containsViolation(s []Violation, violation Violation) bool {
+	for _, x := range s {
+		if x.Rule == violation.Rule && x.From == violation.From && x.To == violation.To {
+			return true
+		}
+	}
+
+	return false
+}
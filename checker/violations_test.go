@@ -0,0 +1,206 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_suppressedRule(t *testing.T) {
+	sameLevel, _ := LookupViolationRule(RuleSameLevel)
+
+	tests := []struct {
+		comment    string
+		want       bool
+		wantReason string
+	}{
+		{"legacy, tracked in #42", true, "legacy, tracked in #42"},
+		{"UB001 legacy", true, "legacy"},
+		{"UB006,UB001 legacy", true, "legacy"},
+		{"UB006, UB001 legacy", true, "legacy"},
+		{"UB006 legacy", false, ""},
+		{"ub001", true, ""},
+		{"UBER adapter", true, "UBER adapter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			suppressed := map[string]string{"m/db": tt.comment}
+
+			reason, got := suppressedRule(suppressed, "m/db", sameLevel)
+
+			if got != tt.want || reason != tt.wantReason {
+				t.Errorf("suppressedRule() = %q, %v, want %q, %v", reason, got, tt.wantReason, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolationRules_codes(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, rule := range ViolationRules {
+		if !isRuleCode(rule.Code) || seen[rule.Code] {
+			t.Errorf("rule %v has an invalid or duplicate code %q", rule.ID, rule.Code)
+		}
+
+		seen[rule.Code] = true
+	}
+}
+
+func Test_violationFingerprint(t *testing.T) {
+	defer func(modPath string) { ModPath = modPath }(ModPath)
+
+	ModPath = "example.com/app"
+	violation := Violation{Code: "UB001", From: "example.com/app/user", To: "example.com/app/billing", FromLevel: 1, ToLevel: 1}
+	want := violationFingerprint(violation)
+
+	moved := violation
+	moved.FromLevel, moved.ToLevel, moved.Message = 2, 2, "another message"
+
+	if got := violationFingerprint(moved); got != want {
+		t.Errorf("violationFingerprint() changed with the levels and the message: %v, want %v", got, want)
+	}
+
+	ModPath = "example.com/renamed"
+	renamed := violation
+	renamed.From, renamed.To = "example.com/renamed/user", "example.com/renamed/billing"
+
+	if got := violationFingerprint(renamed); got != want {
+		t.Errorf("violationFingerprint() changed with the module path: %v, want %v", got, want)
+	}
+
+	reversed := renamed
+	reversed.From, reversed.To = renamed.To, renamed.From
+
+	if got := violationFingerprint(reversed); got == want {
+		t.Errorf("violationFingerprint() is the same for the reversed import")
+	}
+}
+
+func Test_violations_recordUntargeted(t *testing.T) {
+	defer func(modPath string) { ModPath = modPath }(ModPath)
+
+	ModPath = "example.com/app"
+	packageMap := map[string]PackageInfo{
+		"example.com/app/user":    {Path: "example.com/app/user", Level: 1},
+		"example.com/app/billing": {Path: "example.com/app/billing", Level: 1},
+		"example.com/app/api":     {Path: "example.com/app/api", Level: 2},
+	}
+
+	unreported := &Unreported{}
+	found := newViolations(CheckOptions{Packages: []string{"user", "example.com/app/api/..."}, Unreported: unreported})
+
+	for _, violation := range []Violation{
+		{Rule: RuleSameLevel, From: "example.com/app/user", To: "example.com/app/billing"},
+		{Rule: RuleSameLevel, From: "example.com/app/billing", To: "example.com/app/user"},
+		{Rule: RuleSameLevel, From: "example.com/app/billing", To: "example.com/app/user"},
+		{Rule: RuleSameLevel, From: "example.com/app/api", To: "example.com/app/user"},
+	} {
+		found.record(packageMap, violation)
+	}
+
+	var reported []string
+
+	for _, violation := range found.all() {
+		reported = append(reported, violation.From)
+	}
+
+	if want := []string{"example.com/app/user", "example.com/app/api"}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("reported violations of %v, want %v", reported, want)
+	}
+
+	if len(unreported.Untargeted) != 1 || unreported.Untargeted[0].From != "example.com/app/billing" {
+		t.Errorf("Untargeted = %v, want the violation of example.com/app/billing once", unreported.Untargeted)
+	}
+}
+
+func TestCheckLevels_suppressed(t *testing.T) {
+	f := mapFixture(t, map[string]string{
+		"go.mod": "module example.com/suppressed\n\ngo 1.22\n",
+		DefaultConfigFile: `layers:
+  - name: infra
+    packages: [store, cache, queue]
+  - name: domain
+    packages: [user, billing, order]
+`,
+		"store/store.go": "package store\n",
+		"cache/cache.go": "package cache\n",
+		"queue/queue.go": "package queue\n",
+		// the comment of the import line exempts the import
+		"user/user.go": "package user\n\nimport (\n\t_ \"example.com/suppressed/store\" //unclebob:ignore legacy, tracked in #42\n\t_ \"example.com/suppressed/cache\"\n)\n",
+		// a comment not attached to an import exempts the imports of the file
+		"billing/billing.go": "// unclebob:ignore generated client\npackage billing\n\nimport _ \"example.com/suppressed/store\"\n",
+		// an import is only exempt when every file of the package importing it is
+		"order/order.go": "package order\n\nimport _ \"example.com/suppressed/queue\" //unclebob:ignore\n",
+		"order/sync.go":  "package order\n\nimport _ \"example.com/suppressed/queue\"\n",
+	})
+
+	type result struct {
+		suppressed bool
+		reason     string
+	}
+
+	got := make(map[string]result)
+
+	for _, violation := range f.checkLevels(t) {
+		got[shortPackagePath(violation.From)+" -> "+shortPackagePath(violation.To)] = result{violation.Suppressed, violation.SuppressReason}
+
+		if violation.Fails() == violation.Suppressed {
+			t.Errorf("%v -> %v suppressed = %v, fails = %v", violation.From, violation.To, violation.Suppressed, violation.Fails())
+		}
+	}
+
+	want := map[string]result{
+		"/user -> /store":    {true, "legacy, tracked in #42"},
+		"/user -> /cache":    {false, ""},
+		"/billing -> /store": {true, "generated client"},
+		"/order -> /queue":   {false, ""},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckLevels() = %v, want %v", got, want)
+	}
+}
+
+func TestViolation_severity(t *testing.T) {
+	files := map[string]string{
+		"go.mod":         "module example.com/severe\n\ngo 1.22\n",
+		"user/user.go":   "package user\n\nimport (\n\t_ \"example.com/severe/store\"\n\t_ \"unsafe\"\n)\n",
+		"store/store.go": "package store\n",
+	}
+
+	rules := "rules:\n  - from: user\n    to: store\nbanned:\n  - path: unsafe\n"
+
+	tests := []struct {
+		name     string
+		severity string
+		want     map[string]string
+		fails    int
+	}{
+		{"default", "", map[string]string{RuleForbiddenImport: SeverityError, RuleBannedImport: SeverityError}, 2},
+		{"by code", "severity:\n  UB005: warning\n", map[string]string{RuleForbiddenImport: SeverityWarning, RuleBannedImport: SeverityError}, 1},
+		{"by ID", "severity:\n  forbidden-import: info\n  banned-import: warning\n", map[string]string{RuleForbiddenImport: SeverityInfo, RuleBannedImport: SeverityWarning}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files[DefaultConfigFile] = rules + tt.severity
+			f := mapFixture(t, files)
+
+			got := make(map[string]string)
+			fails := 0
+
+			for _, violation := range append(CheckRules(f.packageMap, f.opts), CheckBanned(f.packageMap, f.opts)...) {
+				got[violation.Rule] = violation.Severity
+
+				if violation.Fails() {
+					fails++
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) || fails != tt.fails {
+				t.Errorf("severities = %v with %v failing, want %v with %v failing", got, fails, tt.want, tt.fails)
+			}
+		})
+	}
+}
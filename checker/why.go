@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"sort"
+	"strings"
+)
+
+// ResolvePackage returns the package of the map matching name, given as an import path, a path relative to the
+// module root or a package of a third-party module pseudo package
+func ResolvePackage(packageMap map[string]PackageInfo, name string) (string, bool) {
+	if _, ok := packageMap[name]; ok {
+		return name, true
+	}
+
+	if pkg := packagePathForDir(strings.TrimPrefix(name, "/")); packageMap[pkg].Path != "" {
+		return pkg, true
+	}
+
+	var module string
+
+	for pkg, packageInfo := range packageMap {
+		if packageInfo.External && strings.HasPrefix(name, pkg+"/") && len(pkg) > len(module) {
+			module = pkg
+		}
+	}
+
+	return module, module != ""
+}
+
+// sortedImports returns the imports of the package in import path order, so that chains are found in a stable order
+func sortedImports(packageMap map[string]PackageInfo, pkg string) []string {
+	imports := append([]string{}, packageMap[pkg].Imports...)
+	sort.Strings(imports)
+
+	return imports
+}
+
+// ShortestImportChain returns the shortest chain of imports from the package from to the package to,
+// starting with from and ending with to, or nil if from does not depend on to
+func ShortestImportChain(packageMap map[string]PackageInfo, from string, to string) []string {
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		if pkg == to {
+			var chain []string
+
+			for ; pkg != from; pkg = previous[pkg] {
+				chain = append([]string{pkg}, chain...)
+			}
+
+			return append([]string{from}, chain...)
+		}
+
+		for _, pkgImport := range sortedImports(packageMap, pkg) {
+			if _, seen := previous[pkgImport]; !seen {
+				previous[pkgImport] = pkg
+				queue = append(queue, pkgImport)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ImportChains returns every chain of imports from the package from to the package to visiting no package twice,
+// shortest first. The number of chains grows quickly with the size of the graph, at most limit chains are
+// searched when limit is positive.
+func ImportChains(packageMap map[string]PackageInfo, from string, to string, limit int) [][]string {
+	var chains [][]string
+
+	onChain := map[string]bool{from: true}
+	chain := []string{from}
+
+	var visit func(pkg string)
+	visit = func(pkg string) {
+		if limit > 0 && len(chains) >= limit {
+			return
+		}
+
+		if pkg == to {
+			chains = append(chains, append([]string{}, chain...))
+			return
+		}
+
+		for _, pkgImport := range sortedImports(packageMap, pkg) {
+			if onChain[pkgImport] {
+				continue
+			}
+
+			onChain[pkgImport] = true
+			chain = append(chain, pkgImport)
+
+			visit(pkgImport)
+
+			chain = chain[:len(chain)-1]
+			onChain[pkgImport] = false
+		}
+	}
+
+	visit(from)
+
+	sort.SliceStable(chains, func(i, j int) bool {
+		return len(chains[i]) < len(chains[j])
+	})
+
+	return chains
+}
@@ -0,0 +1,29 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ImportChains(t *testing.T) {
+	packageMap := map[string]PackageInfo{
+		"a": {Path: "a", Imports: []string{"c", "b"}},
+		"b": {Path: "b", Imports: []string{"d"}},
+		"c": {Path: "c", Imports: []string{"b", "d"}},
+		"d": {Path: "d"},
+	}
+
+	if got, want := ShortestImportChain(packageMap, "a", "d"), []string{"a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ShortestImportChain() = %v, want %v", got, want)
+	}
+
+	if got := ShortestImportChain(packageMap, "d", "a"); got != nil {
+		t.Errorf("ShortestImportChain() = %v, want nil", got)
+	}
+
+	want := [][]string{{"a", "b", "d"}, {"a", "c", "d"}, {"a", "c", "b", "d"}}
+
+	if got := ImportChains(packageMap, "a", "d", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportChains() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+// Command uncle-bob-vet runs the uncle-bob checks as a go vet tool:
+//
+//	go install github.com/audi70r/uncle-bob/cmd/uncle-bob-vet
+//	go vet -vettool=$(which uncle-bob-vet) ./...
+package main
+
+import (
+	"github.com/audi70r/uncle-bob/analyzer"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+)
+
+// runCompare analyzes two git revisions of the project in the working directory and reports the architecture drift
+func runCompare(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob compare", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob compare [flags] <revA> <revB>")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if flagSet.NArg() != 2 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if af.diff != "" {
+		clog.Error("-diff can not be used with compare")
+		exit(exitConfigError)
+	}
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	workDir := locateProject(&af)
+
+	revA, revB := flagSet.Arg(0), flagSet.Arg(1)
+
+	tmpDir, err := os.MkdirTemp("", "uncle-bob-compare")
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	// exit removes the temporary directory when the analysis is interrupted or fails
+	cleanups = append(cleanups, func() { os.RemoveAll(tmpDir) })
+
+	// -timeout applies to the analysis of both revisions
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	reportA, errA := analyzeRevision(ctx, workDir, revA, filepath.Join(tmpDir, "a"), &af)
+	reportB, errB := analyzeRevision(ctx, workDir, revB, filepath.Join(tmpDir, "b"), &af)
+
+	os.RemoveAll(tmpDir)
+
+	for _, err := range []error{errA, errB} {
+		if err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
+	}
+
+	comparison := report.Compare(revA, reportA, revB, reportB)
+
+	if *format == "json" {
+		writeJSON(comparison)
+	} else {
+		printComparison(comparison)
+	}
+
+	if comparison.HasNewViolations() {
+		fmt.Fprintf(console, "New violations in %v, Uncle Bob is Sad :(\n", revB)
+		exit(exitViolations)
+	}
+
+	fmt.Fprintf(console, "No new violations in %v, Uncle Bob is Proud :)\n", revB)
+}
+
+// analyzeRevision exports the revision rev to dest and analyzes it. The config file of the revision is used,
+// unless a config file is given with -config.
+func analyzeRevision(ctx context.Context, workDir string, rev string, dest string, af *analysisFlags) (report.Report, error) {
+	revDir, err := git.Export(workDir, rev, dest)
+	if err != nil {
+		return report.Report{}, err
+	}
+
+	if err := checker.LocateModule(revDir, af.modulePath); err != nil {
+		return report.Report{}, fmt.Errorf("revision %v: %v", rev, err)
+	}
+
+	var cfg checker.Config
+
+	if af.configPath != "" {
+		cfg = loadConfig(workDir, af.configPath)
+	} else if cfg, err = checker.LoadConfig(filepath.Join(revDir, checker.DefaultConfigFile), true); err != nil {
+		return report.Report{}, fmt.Errorf("revision %v: %v", rev, err)
+	}
+
+	clog.SetOutput(io.Discard)
+	defer clog.SetOutput(console)
+
+	return analyze(ctx, revDir, cfg, af)
+}
+
+// printComparison prints the comparison in human readable form
+func printComparison(c report.Comparison) {
+	fmt.Fprintf(console, "Comparing %v to %v\n\n", c.Old, c.New)
+
+	printList("Added packages:", c.AddedPackages)
+	printList("Removed packages:", c.RemovedPackages)
+
+	var added, removed, levelChanges []string
+
+	for _, edge := range c.AddedDependencies {
+		added = append(added, fmt.Sprintf("%v --> %v", edge.From, edge.To))
+	}
+
+	for _, edge := range c.RemovedDependencies {
+		removed = append(removed, fmt.Sprintf("%v --> %v", edge.From, edge.To))
+	}
+
+	for _, change := range c.LevelChanges {
+		levelChanges = append(levelChanges, fmt.Sprintf("%v: Lv%v --> Lv%v", change.Path, change.OldLevel, change.NewLevel))
+	}
+
+	printList("Added dependencies:", added)
+	printList("Removed dependencies:", removed)
+	printList("Level changes:", levelChanges)
+
+	for _, violation := range c.NewViolations {
+		clog.Warning(violation.String())
+	}
+
+	if len(c.FixedViolations) > 0 {
+		clog.Info(fmt.Sprintf("%v fixed violations:\n", len(c.FixedViolations)))
+
+		for _, violation := range c.FixedViolations {
+			clog.Info(violation.String())
+		}
+	}
+}
+
+func printList(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintln(console, title)
+
+	for _, item := range items {
+		fmt.Fprintln(console, "  "+item)
+	}
+
+	fmt.Fprintln(console, "")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// writeDatabase adds the report as a new run to the SQLite database at path with the sqlite3 command,
+// creating the database and its tables when needed
+func writeDatabase(ctx context.Context, path string, r report.Report) {
+	var script, stderr bytes.Buffer
+
+	err := report.WriteSQL(&script, r)
+
+	if err == nil {
+		cmd := exec.CommandContext(ctx, "sqlite3", "-bail", path)
+		cmd.Stdin = &script
+		cmd.Stderr = &stderr
+
+		if err = cmd.Run(); errors.Is(err, exec.ErrNotFound) {
+			err = errors.New("-db needs the sqlite3 command, -format=sql writes the script to load with another client")
+		} else if err != nil {
+			err = fmt.Errorf("sqlite3 %v: %v %v", path, err, strings.TrimSpace(stderr.String()))
+		}
+	}
+
+	if err != nil {
+		clog.Error(analysisError(ctx, err).Error())
+		exit(exitAnalysisError)
+	}
+
+	clog.Info("Database updated: " + path)
+}
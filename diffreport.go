@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/visualizer"
+)
+
+// runDiffReport compares two JSON reports, such as the reports of the base and the head of a pull request, and
+// renders the architectural changes with -html
+func runDiffReport(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob diff-report", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob diff-report [flags] <old.json> <new.json>")
+		flagSet.PrintDefaults()
+	}
+
+	htmlFile := flagSet.String("html", "", "write an HTML report of the changes to this file: added packages and imports in green, removed ones in grey, imports with new violations in red")
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if flagSet.NArg() != 2 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	oldPath, newPath := flagSet.Arg(0), flagSet.Arg(1)
+
+	oldReport, err := report.ReadJSON(oldPath)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	newReport, err := report.ReadJSON(newPath)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	// the package labels are relative to the module of the new report
+	checker.ModPath = newReport.Module
+
+	comparison := report.Compare(oldPath, oldReport, newPath, newReport)
+
+	if *htmlFile != "" {
+		writeReportFile(context.Background(), *htmlFile, "Diff report", newReport, func(ctx context.Context, w io.Writer, r report.Report) error {
+			return visualizer.GenerateDiffReport(ctx, w, oldReport, r, comparison)
+		})
+	}
+
+	if *format == "json" {
+		writeJSON(comparison)
+	} else {
+		printComparison(comparison)
+	}
+
+	if comparison.HasNewViolations() {
+		fmt.Fprintf(console, "New violations in %v, Uncle Bob is Sad :(\n", newPath)
+		exit(exitViolations)
+	}
+
+	fmt.Fprintf(console, "No new violations in %v, Uncle Bob is Proud :)\n", newPath)
+}
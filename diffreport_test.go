@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// writeReport writes the JSON report to a file in dir
+func writeReport(t *testing.T, dir string, name string, r report.Report) string {
+	path := filepath.Join(dir, name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	if err := report.WriteJSON(f, r); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_runDiffReport(t *testing.T) {
+	dir := t.TempDir()
+
+	base := report.Report{
+		Module:   "example.com/diffed",
+		Packages: []report.Package{{Path: "example.com/diffed/user"}, {Path: "example.com/diffed/store", Level: 1}},
+		Edges:    []report.Edge{{From: "example.com/diffed/user", To: "example.com/diffed/store", Weight: 1}},
+	}
+
+	head := base
+	head.Packages = append(head.Packages, report.Package{Path: "example.com/diffed/billing", Level: 1})
+	head.Edges = append(head.Edges, report.Edge{From: "example.com/diffed/billing", To: "example.com/diffed/store", Weight: 1, Violation: true})
+	head.Violations = []checker.Violation{{Rule: checker.RuleSameLevel, Code: "UB001", Fingerprint: "f1", From: "example.com/diffed/billing", To: "example.com/diffed/store", FromLevel: 1, ToLevel: 1}}
+
+	basePath := writeReport(t, dir, "base.json", base)
+	headPath := writeReport(t, dir, "head.json", head)
+	htmlFile := filepath.Join(dir, "diff.html")
+
+	code, stdout, stderr := runMain(t, dir, "diff-report", "-format=json", "-html="+htmlFile, basePath, headPath)
+	if code != exitViolations {
+		t.Fatalf("diff-report with a new violation exit code = %v, want %v\n%v", code, exitViolations, stderr)
+	}
+
+	var comparison report.Comparison
+
+	if err := json.Unmarshal([]byte(stdout), &comparison); err != nil {
+		t.Fatalf("diff-report -format=json printed invalid JSON: %v\n%v", err, stdout)
+	}
+
+	if !reflect.DeepEqual(comparison.AddedPackages, []string{"example.com/diffed/billing"}) || len(comparison.AddedDependencies) != 1 || len(comparison.NewViolations) != 1 {
+		t.Errorf("diff-report = %+v, want billing, its import and its violation added", comparison)
+	}
+
+	if html, err := os.ReadFile(htmlFile); err != nil || !strings.Contains(string(html), "billing") {
+		t.Errorf("diff-report -html did not write the changes: %v", err)
+	}
+
+	// the violation is fixed going back
+	if code, _, stderr := runMain(t, dir, "diff-report", headPath, basePath); code != exitClean {
+		t.Errorf("diff-report without new violations exit code = %v, want %v\n%v", code, exitClean, stderr)
+	}
+
+	if code, _, _ := runMain(t, dir, "diff-report", basePath, filepath.Join(dir, "missing.json")); code != exitConfigError {
+		t.Errorf("diff-report of a missing report exit code = %v, want %v", code, exitConfigError)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runExplain analyzes the project and explains a violation: its import declarations, the levels of both
+// packages and the refactoring options of its rule
+func runExplain(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob explain", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob explain [flags] <violation id>")
+		fmt.Fprintln(flagSet.Output(), "The analysis flags have to match the run that reported the violation.")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if flagSet.NArg() != 1 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	// the explanation is the output, the levels and violations are not printed
+	clog.SetOutput(io.Discard)
+	r, packageMap, err := analyzePackages(ctx, workDir, cfg, &af)
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	violation, err := checker.FindViolation(r.Violations, flagSet.Arg(0))
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	printExplanation(os.Stdout, violation, packageMap, cfg.Layers)
+}
+
+// printExplanation prints the explanation of a violation
+func printExplanation(w io.Writer, violation checker.Violation, packageMap map[string]checker.PackageInfo, layers []checker.Layer) {
+	rule, _ := checker.LookupViolationRule(violation.Rule)
+
+	fmt.Fprintf(w, "Violation %v: %v %v\n", violation.ID, violation.Code, violation.Rule)
+	fmt.Fprintf(w, "%v\n%v\n%v\n\n", violation.Message, rule.Description, rule.DocURL())
+
+	fmt.Fprintln(w, "Imports:")
+
+	for _, location := range violation.Locations {
+		fmt.Fprintf(w, "  %v:%v\n", location.File, location.Line)
+	}
+
+	if violation.Suppressed {
+		fmt.Fprintf(w, "  suppressed: %v\n", violation.SuppressReason)
+	}
+
+	fmt.Fprintln(w, "\nLevels:")
+	fmt.Fprintf(w, "  %v\n", checker.ExplainLevel(packageMap, violation.From, layers))
+	fmt.Fprintf(w, "  %v\n", checker.ExplainLevel(packageMap, violation.To, layers))
+
+	fmt.Fprintln(w, "\nOptions:")
+
+	for i, option := range checker.RefactoringOptions(violation) {
+		fmt.Fprintf(w, "  %v. %v\n", i+1, option)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/render"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+var fanSortKeys = []string{"path", "fan-in", "fan-out"}
+
+// runFan prints the number of importers and imports of the packages of the project in the working directory,
+// warning about the packages above the fan-in and fan-out thresholds
+func runFan(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob fan", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob fan [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	sortKey := flagSet.String("sort", "fan-in", "sort the packages by: "+strings.Join(fanSortKeys, ", "))
+	maxFanIn := flagSet.Int("max-fan-in", 0, "warn about packages with more importers (default maxFanIn of the config file)")
+	maxFanOut := flagSet.Int("max-fan-out", 0, "warn about packages with more imports (default maxFanOut of the config file)")
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if !contains(fanSortKeys, *sortKey) {
+		clog.Error(fmt.Sprintf("unknown sort key %q, use one of: %v", *sortKey, strings.Join(fanSortKeys, ", ")))
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	if *maxFanIn > 0 {
+		cfg.MaxFanIn = *maxFanIn
+	}
+
+	if *maxFanOut > 0 {
+		cfg.MaxFanOut = *maxFanOut
+	}
+
+	af.setupCache()
+
+	// the fan table is the output, the mapping messages are not printed
+	clog.SetOutput(io.Discard)
+	packageMap, err := mapModule(ctx, workDir, af.mapOptions(cfg))
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	fans := checker.Fan(packageMap)
+	sortFans(fans, *sortKey)
+
+	if *format == "json" {
+		writeJSON(fans)
+	} else {
+		printFans(os.Stdout, fans)
+	}
+
+	render.Results(checker.CheckFan(fans, cfg.MaxFanIn, cfg.MaxFanOut))
+}
+
+// sortFans sorts the packages by descending fan-in or fan-out, then by path
+func sortFans(fans []checker.PackageFan, key string) {
+	sort.SliceStable(fans, func(i, j int) bool {
+		switch key {
+		case "fan-in":
+			if fans[i].FanIn != fans[j].FanIn {
+				return fans[i].FanIn > fans[j].FanIn
+			}
+		case "fan-out":
+			if fans[i].FanOut != fans[j].FanOut {
+				return fans[i].FanOut > fans[j].FanOut
+			}
+		}
+
+		return fans[i].Path < fans[j].Path
+	})
+}
+
+// printFans prints the fan-in and fan-out of the packages as a table
+func printFans(w io.Writer, fans []checker.PackageFan) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Package\tFan-in\tFan-out")
+
+	for _, fan := range fans {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", shortPath(fan.Path), fan.FanIn, fan.FanOut)
+	}
+
+	tw.Flush()
+}
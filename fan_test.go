@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func Test_runFan(t *testing.T) {
+	project := writeProject(t)
+
+	code, stdout, stderr := runMain(t, t.TempDir(), "fan", "-format=json", "-sort=fan-out", "-path="+project)
+	if code != exitClean {
+		t.Fatalf("fan exit code = %v\n%v", code, stderr)
+	}
+
+	var fans []checker.PackageFan
+
+	if err := json.Unmarshal([]byte(stdout), &fans); err != nil {
+		t.Fatalf("fan -format=json printed invalid JSON: %v\n%v", err, stdout)
+	}
+
+	want := []checker.PackageFan{
+		{Path: "example.com/served", FanOut: 1},
+		{Path: "example.com/served/user", FanIn: 1, FanOut: 1},
+		{Path: "example.com/served/store", FanIn: 1},
+	}
+
+	if !reflect.DeepEqual(fans, want) {
+		t.Errorf("fan -sort=fan-out = %v, want %v", fans, want)
+	}
+
+	code, stdout, stderr = runMain(t, t.TempDir(), "fan", "-path="+project)
+	if code != exitClean || !strings.Contains(stdout, "Package") || !strings.Contains(stdout, "/user") {
+		t.Errorf("fan = %v, want the table of the packages\n%v%v", code, stdout, stderr)
+	}
+}
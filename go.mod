@@ -1,7 +1,11 @@
 module github.com/audi70r/uncle-bob
 
-go 1.17
+go 1.22.0
 
-require golang.org/x/mod v0.5.0
+require (
+	golang.org/x/mod v0.23.0
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898 // indirect
+require golang.org/x/sync v0.11.0 // indirect
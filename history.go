@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+	"github.com/audi70r/uncle-bob/visualizer"
+)
+
+// defaultHistoryCommits is the number of commits analyzed by history unless -limit says otherwise
+const defaultHistoryCommits = 30
+
+// runHistory analyzes the commits of the project in the working directory since a revision, to show how the
+// architecture evolved
+func runHistory(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob history", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob history -since <rev> [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	since := flagSet.String("since", "", "commit or tag to start from, the commits following it up to HEAD are analyzed")
+	htmlFile := flagSet.String("html", "", "write an HTML animation of the package graph over the commits to the given file")
+	limit := flagSet.Int("limit", defaultHistoryCommits, "analyze at most this many commits spread over the history, always the first and the last one, 0 analyzes all of them")
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if *since == "" || flagSet.NArg() > 0 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *limit < 0 {
+		clog.Error("-limit can not be negative")
+		exit(exitConfigError)
+	}
+
+	if af.diff != "" {
+		clog.Error("-diff can not be used with history")
+		exit(exitConfigError)
+	}
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	workDir := locateProject(&af)
+
+	commits, err := git.Commits(workDir, *since)
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	commits = sampleCommits(commits, *limit)
+
+	tmpDir, err := os.MkdirTemp("", "uncle-bob-history")
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	// exit removes the temporary directory when the analysis is interrupted or fails
+	cleanups = append(cleanups, func() { os.RemoveAll(tmpDir) })
+
+	// -timeout applies to the analysis of all commits
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	var frames []visualizer.EvolutionFrame
+	var entries []report.HistoryEntry
+
+	for i, commit := range commits {
+		if checker.Interrupted() || ctx.Err() != nil {
+			break
+		}
+
+		clog.Info(fmt.Sprintf("Analyzing %v (%v/%v) %v", commit.Short, i+1, len(commits), commit.Subject))
+
+		dest := filepath.Join(tmpDir, strconv.Itoa(i))
+
+		r, err := analyzeRevision(ctx, workDir, commit.Hash, dest, &af)
+		os.RemoveAll(dest)
+
+		// commits before the project had a go.mod or with a broken config are left out of the history
+		if err != nil {
+			clog.Warning(fmt.Sprintf("Skipping %v: %v", commit.Short, err))
+			continue
+		}
+
+		summary := report.Summarize(r, report.DefaultTopOffenders)
+
+		frames = append(frames, visualizer.EvolutionFrame{Commit: commit.Short, Time: commit.Time, Subject: commit.Subject, Report: r})
+		entries = append(entries, report.NewHistoryEntry(r, summary, commit.Short, commit.Time))
+	}
+
+	os.RemoveAll(tmpDir)
+
+	if err := ctx.Err(); err != nil {
+		clog.Error(analysisError(ctx, err).Error())
+		exit(exitAnalysisError)
+	}
+
+	if *htmlFile != "" && len(frames) > 0 {
+		writeReportFile(ctx, *htmlFile, "History animation", report.Report{}, func(ctx context.Context, w io.Writer, _ report.Report) error {
+			return visualizer.GenerateEvolution(ctx, w, frames)
+		})
+	}
+
+	if *format == "json" {
+		if entries == nil {
+			entries = []report.HistoryEntry{}
+		}
+
+		writeJSON(entries)
+	} else {
+		printTrend(os.Stdout, entries)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the history above is partial")
+		exit(exitInterrupted)
+	}
+
+	if len(frames) == 0 {
+		clog.Error("none of the commits could be analyzed")
+		exit(exitAnalysisError)
+	}
+}
+
+// sampleCommits returns at most limit commits spread evenly over the commits, keeping the first and the last one.
+// A limit of 0 keeps all of them.
+func sampleCommits(commits []git.Commit, limit int) []git.Commit {
+	if limit == 0 || len(commits) <= limit {
+		return commits
+	}
+
+	if limit == 1 {
+		return commits[len(commits)-1:]
+	}
+
+	sampled := make([]git.Commit, 0, limit)
+
+	for i := 0; i < limit; i++ {
+		sampled = append(sampled, commits[i*(len(commits)-1)/(limit-1)])
+	}
+
+	return sampled
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/git"
+)
+
+// commitProject commits the files of the project in dir, creating the git repository first
+func commitProject(t *testing.T, dir string, message string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "uncle-bob")
+	t.Setenv("GIT_AUTHOR_EMAIL", "uncle-bob@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "uncle-bob")
+	t.Setenv("GIT_COMMITTER_EMAIL", "uncle-bob@example.com")
+
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "-A"}, {"commit", "-q", "-m", message}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v %s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+func Test_sampleCommits(t *testing.T) {
+	var commits []git.Commit
+
+	for _, short := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		commits = append(commits, git.Commit{Short: short})
+	}
+
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{0, "abcdefg"},
+		{7, "abcdefg"},
+		{10, "abcdefg"},
+		{1, "g"},
+		{2, "ag"},
+		{3, "adg"},
+		{4, "aceg"},
+	}
+
+	for _, tt := range tests {
+		var got strings.Builder
+
+		for _, commit := range sampleCommits(commits, tt.limit) {
+			got.WriteString(commit.Short)
+		}
+
+		if got.String() != tt.want {
+			t.Errorf("sampleCommits(%v) = %v, want %v", tt.limit, got.String(), tt.want)
+		}
+	}
+}
+
+func Test_runHistory(t *testing.T) {
+	project := writeProject(t)
+	commitProject(t, project, "add the served module")
+
+	// the second commit adds a violation
+	writeConfig(t, project, "banned:\n  - path: example.com/served/store\n")
+	commitProject(t, project, "ban the store")
+
+	htmlFile := filepath.Join(t.TempDir(), "evolution.html")
+
+	code, stdout, stderr := runMain(t, project, "history", "-since=HEAD~1", "-format=json", "-html="+htmlFile)
+	if code != exitClean {
+		t.Fatalf("history exit code = %v\n%v", code, stderr)
+	}
+
+	var entries []report.HistoryEntry
+
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("history -format=json printed invalid JSON: %v\n%v", err, stdout)
+	}
+
+	var violations []int
+
+	for _, entry := range entries {
+		violations = append(violations, entry.Summary.Violations)
+
+		if entry.Module != "example.com/served" || entry.Commit == "" || entry.Summary.Packages != 3 {
+			t.Errorf("history entry = %+v, want a commit of the 3 packages of example.com/served", entry)
+		}
+	}
+
+	if want := []int{0, 1}; !reflect.DeepEqual(violations, want) {
+		t.Errorf("history violations = %v, want %v", violations, want)
+	}
+
+	if html, err := os.ReadFile(htmlFile); err != nil || !strings.Contains(string(html), "ban the store") {
+		t.Errorf("history -html did not write the animation of the commits: %v", err)
+	}
+
+	if code, _, _ := runMain(t, project, "history", "-since=missing"); code != exitConfigError {
+		t.Errorf("history of an unknown revision exit code = %v, want %v", code, exitConfigError)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runImporters lists the packages importing a package with their levels, the inverse of -package-imports
+func runImporters(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob importers", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob importers [flags] <package>")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	files := flagSet.Bool("files", false, "list the files and lines importing the package")
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if flagSet.NArg() != 1 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	af.setupCache()
+
+	// the importers are the output, the mapping messages are not printed
+	clog.SetOutput(io.Discard)
+	packageMap, err := mapModule(ctx, workDir, af.mapOptions(cfg))
+	if err == nil {
+		_, _, err = assignLevels(ctx, packageMap, cfg)
+	}
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	pkg, ok := checker.ResolvePackage(packageMap, flagSet.Arg(0))
+	if !ok {
+		clog.Error(fmt.Sprintf("package %v is not part of the import graph", flagSet.Arg(0)))
+		exit(exitConfigError)
+	}
+
+	importers := checker.Importers(packageMap, pkg)
+
+	if !*files {
+		for i := range importers {
+			importers[i].Locations = nil
+		}
+	}
+
+	if *format == "json" {
+		writeJSON(importers)
+
+		return
+	}
+
+	if len(importers) == 0 {
+		fmt.Fprintf(console, "%v is not imported by any package\n", shortPath(pkg))
+		return
+	}
+
+	printImporters(os.Stdout, packageMap[pkg], importers)
+}
+
+// printImporters prints the importers of a package as a table, followed by their import declarations when set
+func printImporters(w io.Writer, imported checker.PackageInfo, importers []checker.Importer) {
+	fmt.Fprintf(w, "Importers of %v (level %v):\n\n", shortPath(imported.Path), imported.Level)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Package\tLevel\tLayer")
+
+	for _, importer := range importers {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", shortPath(importer.Path), importer.Level, importer.Layer)
+
+		for _, location := range importer.Locations {
+			fmt.Fprintf(tw, "  %v:%v\t\t\n", location.File, location.Line)
+		}
+	}
+
+	tw.Flush()
+}
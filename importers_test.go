@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func Test_runImporters(t *testing.T) {
+	project := writeProject(t)
+
+	code, stdout, stderr := runMain(t, t.TempDir(), "importers", "-format=json", "-path="+project, "store")
+	if code != exitClean {
+		t.Fatalf("importers exit code = %v\n%v", code, stderr)
+	}
+
+	var importers []checker.Importer
+
+	if err := json.Unmarshal([]byte(stdout), &importers); err != nil {
+		t.Fatalf("importers -format=json printed invalid JSON: %v\n%v", err, stdout)
+	}
+
+	if want := []checker.Importer{{Path: "example.com/served/user", Level: 1}}; !reflect.DeepEqual(importers, want) {
+		t.Errorf("importers store = %v, want %v", importers, want)
+	}
+
+	code, stdout, stderr = runMain(t, t.TempDir(), "importers", "-files", "-path="+project, "example.com/served/store")
+	if code != exitClean || !strings.Contains(stdout, "/user") || !strings.Contains(stdout, "user/user.go:3") {
+		t.Errorf("importers -files = %v, want the importers with their import declarations\n%v%v", code, stdout, stderr)
+	}
+
+	code, stdout, stderr = runMain(t, t.TempDir(), "importers", "-path="+project)
+	if code != exitConfigError {
+		t.Errorf("importers without a package exit code = %v, want %v\n%v%v", code, exitConfigError, stdout, stderr)
+	}
+
+	code, stdout, stderr = runMain(t, t.TempDir(), "importers", "-path="+project, "missing")
+	if code != exitConfigError || !strings.Contains(stderr+stdout, "not part of the import graph") {
+		t.Errorf("importers of a missing package = %v, want an error\n%v%v", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runInitArch analyzes the project in the working directory and writes a config file declaring the inferred levels
+// as layers, for the users to curate
+func runInitArch(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob init-arch", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob init-arch [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	output := flagSet.String("o", "", "file to write (default "+checker.DefaultConfigFile+" in the project root), - writes to stdout")
+	force := flagSet.Bool("force", false, "overwrite an existing file")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if *output == "-" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	if *output == "" {
+		*output = filepath.Join(workDir, checker.DefaultConfigFile)
+	}
+
+	if _, err := os.Stat(*output); *output != "-" && !*force && !errors.Is(err, os.ErrNotExist) {
+		clog.Error(*output + " already exists, use -force to overwrite it or -o to write another file")
+		exit(exitConfigError)
+	}
+
+	af.setupCache()
+
+	// the levels are inferred from the imports, not from the declared layers
+	clog.SetOutput(io.Discard)
+	var packageLevels [][]string
+
+	packageMap, err := mapModule(ctx, workDir, af.mapOptions(cfg))
+	if err == nil {
+		packageLevels, err = checker.SetUniqueLevels(ctx, packageMap)
+		err = analysisError(ctx, err)
+	}
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	// the layers of a partial graph would miss packages and dependencies
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the config file was not written")
+		exit(exitInterrupted)
+	}
+
+	layers := checker.InferLayers(packageMap, packageLevels)
+
+	unleveled := 0
+
+	for _, packageInfo := range packageMap {
+		if !packageInfo.External {
+			unleveled++
+		}
+	}
+
+	for _, layer := range layers {
+		unleveled -= len(layer.Packages)
+	}
+
+	if unleveled > 0 {
+		clog.Warning(fmt.Sprintf("%v packages have no level, they import each other in a cycle or import such packages. Assign them to layers by hand.", unleveled))
+	}
+
+	if *output == "-" {
+		writeArchitecture(os.Stdout, layers)
+		return
+	}
+
+	f, err := os.Create(*output)
+	if err == nil {
+		writeArchitecture(f, layers)
+		err = f.Close()
+	}
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	clog.Info(fmt.Sprintf("%v layers written to %v, rename and curate them", len(layers), *output))
+}
+
+// writeArchitecture writes the layers as a config file, commented for curation
+func writeArchitecture(w io.Writer, layers []checker.Layer) {
+	fmt.Fprintf(w, "# Architecture of %v, generated by uncle-bob init-arch from the inferred levels.\n", checker.ModPath)
+	fmt.Fprintln(w, "# Rename the layers, merge or split them and widen the package patterns (ex. internal/billing/...),")
+	fmt.Fprintln(w, "# packages matching no layer are reported and not checked. dependsOn lists the layers imported today.")
+	fmt.Fprintln(w, "layers:")
+
+	for _, layer := range layers {
+		fmt.Fprintf(w, "  - name: %v\n", layer.Name)
+		fmt.Fprintln(w, "    packages:")
+
+		for _, pattern := range layer.Packages {
+			fmt.Fprintf(w, "      - %q\n", pattern)
+		}
+
+		fmt.Fprintf(w, "    dependsOn: [%v]\n", strings.Join(layer.DependsOn, ", "))
+	}
+
+	fmt.Fprintln(w, "# Rules allow or forbid imports between packages, the first matching rule applies.")
+	fmt.Fprintln(w, "# rules:")
+	fmt.Fprintln(w, "#   - from: internal/adapters/*")
+	fmt.Fprintln(w, "#     to: internal/domain/*")
+	fmt.Fprintln(w, "#     allow: false")
+}
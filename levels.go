@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runLevels prints the level assignment of the packages of the project, for other tooling to consume
+func runLevels(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob levels", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob levels [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	af.setupCache()
+
+	// the levels are the output, the mapping messages are not printed
+	clog.SetOutput(io.Discard)
+	var packageLevels [][]string
+	var layerNames []string
+
+	packageMap, err := mapModule(ctx, workDir, af.mapOptions(cfg))
+	if err == nil {
+		packageLevels, layerNames, err = assignLevels(ctx, packageMap, cfg)
+	}
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	r := report.New(packageMap, packageLevels, layerNames, nil)
+
+	if *format == "json" {
+		levels := struct {
+			Module   string           `json:"module"`
+			Levels   []report.Level   `json:"levels"`
+			Packages []report.Package `json:"packages"`
+		}{r.Module, r.Levels, r.Packages}
+
+		writeJSON(levels)
+
+		return
+	}
+
+	printLevels(os.Stdout, r.Levels)
+}
+
+// printLevels prints the packages of every level as a table, from the outermost level inwards
+func printLevels(w io.Writer, levels []report.Level) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Level\tLayer\tPackage")
+
+	for _, level := range levels {
+		for _, pkg := range level.Packages {
+			fmt.Fprintf(tw, "%v\t%v\t%v\n", level.Level, level.Layer, shortPath(pkg))
+		}
+	}
+
+	tw.Flush()
+}
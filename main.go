@@ -1,15 +1,109 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
 
 	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/visualizer"
 )
 
+// Exit codes, so that pipelines can tell a broken architecture from a misconfigured tool
+const (
+	exitClean         = 0
+	exitViolations    = 1
+	exitAnalysisError = 2
+	exitConfigError   = 3
+	exitInterrupted   = 130
+)
+
+// cleanups are run by exit, they remove the temporary files of the running command
+var cleanups []func()
+
+// exit runs the cleanups, the last registered first, and exits with code
+func exit(code int) {
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+
+	os.Exit(code)
+}
+
+var outputFormats = []string{"text", "json", "sarif", "junit", "csv", "github", "openmetrics", "cypher", "sql", "dot", "d2", "structurizr", "svg", "png", "html", "treemap", "sunburst", "building"}
+
+// graphFormats are the formats drawing the package graph, narrowed by -focus
+var graphFormats = []string{"dot", "d2", "structurizr", "svg", "png", "html", "building"}
+
+// formatExtensions are the file extensions of the formats written with -o
+var formatExtensions = map[string]string{
+	"json":        ".json",
+	"sarif":       ".sarif",
+	"junit":       ".xml",
+	"csv":         ".csv",
+	"github":      ".txt",
+	"openmetrics": ".prom",
+	"cypher":      ".cypher",
+	"sql":         ".sql",
+	"dot":         ".dot",
+	"d2":          ".d2",
+	"structurizr": ".dsl",
+	"svg":         ".svg",
+	"png":         ".png",
+	"html":        ".html",
+	"treemap":     ".html",
+	"sunburst":    ".html",
+	"building":    ".json",
+	"template":    ".txt",
+}
+
+func contains(s []string, searchterm string) bool {
+	for _, x := range s {
+		if x == searchterm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateTextJSON exits when format is not one of the text and json formats of the commands printing a result
+func validateTextJSON(format string) {
+	if format != "text" && format != "json" {
+		clog.Error(fmt.Sprintf("unknown output format %q, use one of: text, json", format))
+		exit(exitConfigError)
+	}
+}
+
+// writeJSON writes v to stdout as indented JSON, the result of the commands run with -format=json
+func writeJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+}
+
 func PrintAA() {
+	// structured logs are read by machines
+	if clog.Structured() {
+		return
+	}
+
 	aa := []string{
 		` /\ /\ _ __   ___| | ___    / __\ ___ | |__  `,
 		`/ / \ \ '_ \ / __| |/ _ \  /__\/// _ \| '_ \ `,
@@ -18,45 +112,311 @@ func PrintAA() {
 		`v1.0                         dmitri@nuage.ee `,
 	}
 	for _, s := range aa {
-		fmt.Println(s)
+		fmt.Fprintln(console, s)
+	}
+	fmt.Fprintln(console, "")
+}
+
+// console receives the human readable output, it is moved to stderr when a machine-readable format is written to stdout
+var console io.Writer = os.Stdout
+
+// stringList is a flag value that can be set multiple times
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// loadConfig reads the config file, the default config file is optional
+func loadConfig(workDir string, configPath string) checker.Config {
+	optional := configPath == ""
+	if optional {
+		configPath = filepath.Join(workDir, checker.DefaultConfigFile)
+	}
+
+	cfg, err := checker.LoadConfig(configPath, optional)
+
+	if err != nil {
+		clog.Error(err.Error())
+		clog.Warning("Could not read the config file.")
+		exit(exitConfigError)
+	}
+
+	return cfg
+}
+
+// reportOptions are the options of the report formats
+type reportOptions struct {
+	// template is the -template of the template format
+	template *template.Template
+	dot      visualizer.DotOptions
+	treemap  visualizer.TreemapOptions
+	html     visualizer.HTMLOptions
+	building visualizer.BuildingOptions
+	image    visualizer.ImageOptions
+}
+
+// generateReport writes the report to w in a machine-readable format, the text format is printed during the checks
+func generateReport(ctx context.Context, w io.Writer, format string, r report.Report, opts reportOptions) error {
+	switch format {
+	case "json":
+		return report.WriteJSON(w, r)
+	case "sarif":
+		return report.WriteSARIF(w, r)
+	case "junit":
+		return report.WriteJUnit(w, r)
+	case "csv":
+		return report.WriteCSV(w, r)
+	case "github":
+		return report.WriteGitHubAnnotations(w, r)
+	case "openmetrics":
+		return report.WriteOpenMetrics(w, r)
+	case "cypher":
+		return report.WriteCypher(w, r)
+	case "sql":
+		return report.WriteSQL(w, r)
+	case "dot":
+		return visualizer.GenerateDotGraphWithOptions(ctx, w, r, opts.dot)
+	case "d2":
+		return visualizer.GenerateD2Graph(ctx, w, r)
+	case "structurizr":
+		return visualizer.GenerateStructurizrDSL(ctx, w, r)
+	case "svg":
+		return visualizer.GenerateSVGWithOptions(ctx, w, r, opts.image)
+	case "png":
+		return visualizer.GeneratePNGWithOptions(ctx, w, r, opts.image)
+	case "html":
+		return visualizer.GenerateHTMLReportWithOptions(ctx, w, r, opts.html)
+	case "treemap":
+		return visualizer.GenerateTreemapWithOptions(ctx, w, r, opts.treemap)
+	case "sunburst":
+		return visualizer.GenerateSunburst(ctx, w, r)
+	case "building":
+		return visualizer.GenerateBuildingDataWithOptions(ctx, w, r, opts.building)
+	case "template":
+		return report.WriteTemplate(w, r, opts.template)
+	}
+
+	return nil
+}
+
+// writeReports writes the report in every machine-readable format of formats, to stdout or to the -o file output
+func writeReports(ctx context.Context, formats []string, output string, r report.Report, graph report.Report, opts reportOptions) {
+	for _, format := range reportFormats(formats) {
+		r := r
+		if contains(graphFormats, format) {
+			r = graph
+		}
+
+		generate := func(ctx context.Context, w io.Writer, r report.Report) error {
+			return generateReport(ctx, w, format, r, opts)
+		}
+
+		if output != "" {
+			writeReportFile(ctx, outputFile(output, format, formats), format+" report", r, generate)
+			continue
+		}
+
+		if err := generate(ctx, os.Stdout, r); err != nil {
+			clog.Error(analysisError(ctx, err).Error())
+			exit(exitAnalysisError)
+		}
+	}
+}
+
+// parseFormats splits the comma-separated -format value, every format is written once
+func parseFormats(value string) ([]string, error) {
+	var formats []string
+
+	for _, format := range strings.Split(value, ",") {
+		format = strings.TrimSpace(format)
+
+		if !contains(outputFormats, format) {
+			return nil, fmt.Errorf("unknown output format %q, use one of: %v", format, strings.Join(outputFormats, ", "))
+		}
+
+		if !contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+
+	return formats, nil
+}
+
+// reportFormats returns the formats written after the analysis, all but text
+func reportFormats(formats []string) []string {
+	var written []string
+
+	for _, format := range formats {
+		if format != "text" {
+			written = append(written, format)
+		}
+	}
+
+	return written
+}
+
+// outputFile returns the file of a format written with -o. With several formats output names all of them,
+// its extension is replaced by the extension of the format (ex. -o report writes report.json and report.html).
+func outputFile(output string, format string, formats []string) string {
+	if len(reportFormats(formats)) == 1 {
+		return output
+	}
+
+	return strings.TrimSuffix(output, filepath.Ext(output)) + formatExtensions[format]
+}
+
+// writeReportFile writes the report to a file with the given generator, name describes the file in the log
+func writeReportFile(ctx context.Context, path string, name string, r report.Report, generate func(context.Context, io.Writer, report.Report) error) {
+	f, err := os.Create(path)
+
+	if err == nil {
+		err = generate(ctx, f, r)
+
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
 	}
-	fmt.Println("")
+
+	if err != nil {
+		clog.Error(analysisError(ctx, err).Error())
+		exit(exitAnalysisError)
+	}
+
+	clog.Info(name + " written to " + path)
+}
+
+// handleInterrupts stops the analysis on SIGINT/SIGTERM so that partial results can still be printed,
+// a second signal exits immediately
+func handleInterrupts() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		checker.Interrupt()
+		clog.Warning("Interrupt received, stopping the analysis. Interrupt again to exit immediately.")
+
+		<-signals
+		exit(exitInterrupted)
+	}()
 }
 
 func main() {
-	PrintAA()
+	args := os.Args[1:]
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "check":
+			runCheck(args[1:])
+			return
+		case "serve":
+			runServe(args[1:])
+			return
+		case "compare":
+			runCompare(args[1:])
+			return
+		case "diff-report":
+			runDiffReport(args[1:])
+			return
+		case "metrics":
+			runMetrics(args[1:])
+			return
+		case "fan":
+			runFan(args[1:])
+			return
+		case "why":
+			runWhy(args[1:])
+			return
+		case "importers":
+			runImporters(args[1:])
+			return
+		case "levels":
+			runLevels(args[1:])
+			return
+		case "explain":
+			runExplain(args[1:])
+			return
+		case "tui":
+			runTUI(args[1:])
+			return
+		case "trend":
+			runTrend(args[1:])
+			return
+		case "history":
+			runHistory(args[1:])
+			return
+		case "approve":
+			runApprove(args[1:])
+			return
+		case "init-arch":
+			runInitArch(args[1:])
+			return
+		case "query":
+			runQuery(args[1:])
+			return
+		}
+	}
 
-	fileImports := flag.String("package-imports", "", "show detailed information about package imports")
-	strictFlag := flag.Bool("strict", false, "do strict checking, do not allow same level imports")
-	ignoreTests := flag.Bool("ignore-tests", false, "ignore imports of test files")
+	runCheck(args)
+}
 
-	flag.Parse()
+// parseFlags parses the arguments of a command, exiting with exitConfigError on invalid flags
+func parseFlags(fs *flag.FlagSet, args []string) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			exit(exitClean)
+		}
 
+		exit(exitConfigError)
+	}
+}
+
+// workingDir returns the working directory, the root of the analyzed tree
+func workingDir() string {
 	workDir, wrkDirErr := os.Getwd()
 	if wrkDirErr != nil {
-		log.Println(wrkDirErr)
+		clog.Error(wrkDirErr.Error())
+		exit(exitAnalysisError)
 	}
 
-	checker.LocateGoMod(workDir)
+	return workDir
+}
 
-	if *fileImports != "" {
-		_ = checker.DisplayPackageInfo(workDir, *fileImports, *ignoreTests)
+// locateProject returns the root of the analyzed module after reading its go.mod, -module-path overrides the
+// module path. The module is found in -path, the working directory by default, or its closest parent directory
+// with a go.mod like the go command does. A -path below the module root scopes the analysis to its packages.
+func locateProject(af *analysisFlags) string {
+	target := af.targetDir()
+	workDir := target
 
-		return
+	if root, err := checker.FindModuleRoot(target); err == nil {
+		workDir = root
 	}
 
-	packageMap, _ := checker.Map(workDir, *ignoreTests)
-
-	packageLevels := checker.SetUniqueLevels(packageMap)
+	if err := checker.LocateModule(workDir, af.modulePath); err != nil {
+		clog.Error(err.Error())
+		clog.Warning("Could not find go.mod file. Please make sure the target directory is correct or that go modules are initiated, or set -module-path.")
+		exit(exitAnalysisError)
+	}
 
-	checker.LevelsInfo(packageLevels)
+	if workDir != target {
+		rel, err := filepath.Rel(workDir, target)
+		if err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
 
-	checker.CheckLevels(packageMap, packageLevels, *strictFlag)
+		pattern := checker.ModPath + "/" + filepath.ToSlash(rel) + "/..."
+		af.include = append(af.include, pattern)
 
-	if checker.UncleBobIsSad {
-		fmt.Println("Issues detected, Uncle Bob is Sad :(")
-		os.Exit(1)
+		clog.Info(fmt.Sprintf("Found go.mod in %v, analyzing the packages of %v", workDir, pattern))
 	}
 
-	fmt.Println("Well done, Uncle Bob is Proud :)")
+	return workDir
 }
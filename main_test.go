@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// mainArgsEnv makes the test binary run main with the newline-separated arguments of the variable instead of the
+// tests, so that the tests can check the exit codes of the commands
+const mainArgsEnv = "UNCLE_BOB_TEST_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(mainArgsEnv); ok {
+		os.Args = append([]string{"uncle-bob"}, strings.Split(args, "\n")...)
+		main()
+		exit(exitClean)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runMain runs uncle-bob with args in dir in another process and returns its exit code, stdout and stderr
+func runMain(t *testing.T, dir string, args ...string) (int, string, string) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr strings.Builder
+
+	cmd := exec.Command(executable)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), mainArgsEnv+"="+strings.Join(args, "\n"))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), stdout.String(), stderr.String()
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return exitClean, stdout.String(), stderr.String()
+}
+
+// writeConfig writes the config file of the project in dir
+func writeConfig(t *testing.T, dir string, config string) {
+	if err := os.WriteFile(filepath.Join(dir, checker.DefaultConfigFile), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitCodes(t *testing.T) {
+	project := writeProject(t)
+	file := filepath.Join(project, "main.go")
+
+	violating := writeProject(t)
+	writeConfig(t, violating, "banned:\n  - path: example.com/served/store\n")
+
+	warned := writeProject(t)
+	writeConfig(t, warned, "banned:\n  - path: example.com/served/store\nseverity:\n  banned-import: warning\n")
+
+	misconfigured := writeProject(t)
+	writeConfig(t, misconfigured, "layers: [\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"clean", []string{"check", "-history=", "-snapshot=", "-path=" + project}, exitClean},
+		{"missing -path", []string{"check", "-history=", "-snapshot=", "-path=" + filepath.Join(project, "missing")}, exitAnalysisError},
+		{"-path to a file", []string{"check", "-history=", "-snapshot=", "-path=" + file}, exitAnalysisError},
+		{"unknown format", []string{"check", "-history=", "-snapshot=", "-format=unknown", "-path=" + project}, exitConfigError},
+		{"violations", []string{"check", "-history=", "-snapshot=", "-path=" + violating}, exitViolations},
+		{"violations up to -max-violations", []string{"check", "-history=", "-snapshot=", "-max-violations=1", "-path=" + violating}, exitClean},
+		{"violations above -max-violations", []string{"check", "-history=", "-snapshot=", "-max-violations=0", "-path=" + violating}, exitViolations},
+		{"warnings", []string{"check", "-history=", "-snapshot=", "-path=" + warned}, exitClean},
+		{"invalid config", []string{"check", "-history=", "-snapshot=", "-path=" + misconfigured}, exitConfigError},
+		{"no go.mod", []string{"check", "-history=", "-snapshot=", "-path=" + t.TempDir()}, exitAnalysisError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, stdout, stderr := runMain(t, t.TempDir(), tt.args...); code != tt.want {
+				t.Errorf("exit code = %v, want %v\n%v%v", code, tt.want, stdout, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runMetrics prints the design metrics of the packages of the project in the working directory
+func runMetrics(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob metrics", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob metrics [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+	af.metrics = true
+
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	// the metrics are the output, the levels and violations are not printed
+	clog.SetOutput(io.Discard)
+	r := analyzeModule(ctx, workDir, cfg, &af)
+	clog.SetOutput(console)
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	if *format == "json" {
+		metrics := struct {
+			Module  string                   `json:"module"`
+			Metrics []checker.PackageMetrics `json:"metrics"`
+		}{r.Module, r.Metrics}
+
+		writeJSON(metrics)
+
+		return
+	}
+
+	printMetrics(os.Stdout, r)
+}
+
+// printMetrics prints the metrics of the report as a table
+func printMetrics(w io.Writer, r report.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Package\tCa\tCe\tI\tA\tD")
+
+	for _, m := range r.Metrics {
+		path := strings.TrimPrefix(m.Path, r.Module)
+
+		if path == "" {
+			path = "/"
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%.2f\t%.2f\t%.2f\n", path, m.Afferent, m.Efferent, m.Instability, m.Abstractness, m.Distance)
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func Test_runMetrics(t *testing.T) {
+	code, stdout, stderr := runMain(t, t.TempDir(), "metrics", "-format=json", "-path="+writeProject(t))
+	if code != exitClean {
+		t.Fatalf("metrics exit code = %v\n%v", code, stderr)
+	}
+
+	var metrics struct {
+		Module  string                   `json:"module"`
+		Metrics []checker.PackageMetrics `json:"metrics"`
+	}
+
+	if err := json.Unmarshal([]byte(stdout), &metrics); err != nil {
+		t.Fatalf("metrics -format=json printed invalid JSON: %v\n%v", err, stdout)
+	}
+
+	want := map[string]checker.PackageMetrics{
+		"example.com/served":       {Path: "example.com/served", Efferent: 1, Instability: 1},
+		"example.com/served/user":  {Path: "example.com/served/user", Afferent: 1, Efferent: 1, Instability: 0.5, Distance: 0.5},
+		"example.com/served/store": {Path: "example.com/served/store", Afferent: 1, Distance: 1},
+	}
+
+	if metrics.Module != "example.com/served" || len(metrics.Metrics) != len(want) {
+		t.Fatalf("metrics = %+v, want the 3 packages of example.com/served", metrics)
+	}
+
+	for _, m := range metrics.Metrics {
+		if m != want[m.Path] {
+			t.Errorf("metrics of %v = %+v, want %+v", m.Path, m, want[m.Path])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/goproxy"
+)
+
+// downloadModule downloads the module of -module, a module path with an optional @version, from the module proxy
+// of GOPROXY. It returns the temporary directory to remove after the analysis, exit removes it when the check
+// fails before, the directory of the module in it and the downloaded version.
+func downloadModule(ctx context.Context, spec string) (string, string, string) {
+	modulePath, version, _ := strings.Cut(spec, "@")
+
+	tmpDir, err := os.MkdirTemp("", "uncle-bob-module")
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	moduleDir := filepath.Join(tmpDir, "module")
+
+	if version, err = goproxy.Download(ctx, modulePath, version, moduleDir); err != nil {
+		os.RemoveAll(tmpDir)
+		clog.Error(fmt.Sprintf("Could not download %v: %v", spec, analysisError(ctx, err)))
+		exit(exitAnalysisError)
+	}
+
+	cleanups = append(cleanups, func() { os.RemoveAll(tmpDir) })
+
+	clog.Info(fmt.Sprintf("Downloaded %v@%v", modulePath, version))
+
+	// modules without a go.mod file, such as +incompatible versions, are given the go.mod the go command assumes
+	if _, err := os.Stat(filepath.Join(moduleDir, "go.mod")); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte("module "+modulePath+"\n"), 0o644); err != nil {
+			clog.Error(err.Error())
+			exit(exitAnalysisError)
+		}
+	}
+
+	return tmpDir, moduleDir, version
+}
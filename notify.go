@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// notifyFormats are the payloads posted to the -notify-webhook
+var notifyFormats = []string{"json", "slack"}
+
+// notifyTimeout bounds the webhook request, a slow endpoint does not hold up the check
+const notifyTimeout = 10 * time.Second
+
+// validateWebhook checks the -notify-webhook URL and payload format
+func validateWebhook(webhook string, format string) error {
+	if !contains(notifyFormats, format) {
+		return fmt.Errorf("unknown notification format %q, use one of: json, slack", format)
+	}
+
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid -notify-webhook URL, use an http or https URL")
+	}
+
+	return nil
+}
+
+// notifyWebhook posts the JSON report or a Slack message with its summary to the webhook
+func notifyWebhook(ctx context.Context, webhook string, format string, r report.Report) error {
+	var body bytes.Buffer
+
+	write := report.WriteJSON
+	if format == "slack" {
+		write = report.WriteSlack
+	}
+
+	if err := write(&body, r); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// webhook URLs hold their credentials, the errors leave them out of the logs
+	resp, err := http.DefaultClient.Do(req)
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %v", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func Test_validateWebhook(t *testing.T) {
+	tests := []struct {
+		webhook string
+		format  string
+		wantErr bool
+	}{
+		{"https://hooks.example.com/T000/B000/secret", "slack", false},
+		{"http://localhost:8080/uncle-bob", "json", false},
+		{"https://hooks.example.com/T000", "teams", true},
+		{"ftp://hooks.example.com/T000", "json", true},
+		{"hooks.example.com/T000", "json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.webhook+" "+tt.format, func(t *testing.T) {
+			if err := validateWebhook(tt.webhook, tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("validateWebhook() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_notifyWebhook(t *testing.T) {
+	var contentType, body string
+
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		contentType, body = r.Header.Get("Content-Type"), string(payload)
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	r := report.Report{
+		Module:     "example.com/notified",
+		Violations: []checker.Violation{{Rule: checker.RuleSameLevel, Code: "UB001", From: "example.com/notified/user", To: "example.com/notified/store"}},
+	}
+
+	if err := notifyWebhook(context.Background(), server.URL+"/secret", "json", r); err != nil {
+		t.Fatalf("notifyWebhook() error = %v", err)
+	}
+
+	var posted report.Report
+
+	if err := json.Unmarshal([]byte(body), &posted); err != nil || posted.Module != r.Module || len(posted.Violations) != 1 || contentType != "application/json" {
+		t.Errorf("notifyWebhook() posted %v %q, %v, want the JSON report", contentType, body, err)
+	}
+
+	if err := notifyWebhook(context.Background(), server.URL+"/secret", "slack", r); err != nil {
+		t.Fatalf("notifyWebhook() error = %v", err)
+	}
+
+	var message struct {
+		Text string `json:"text"`
+	}
+
+	if err := json.Unmarshal([]byte(body), &message); err != nil || !strings.Contains(message.Text, "1 violations in `example.com/notified`") {
+		t.Errorf("notifyWebhook() posted %q, %v, want the Slack summary", body, err)
+	}
+
+	status = http.StatusForbidden
+
+	if err := notifyWebhook(context.Background(), server.URL+"/secret", "json", r); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("notifyWebhook() error = %v, want the status of the webhook", err)
+	}
+
+	server.Close()
+
+	// the URL holds the credentials of the webhook, the errors leave it out
+	if err := notifyWebhook(context.Background(), server.URL+"/secret", "json", r); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("notifyWebhook() error = %v, want an error without the URL", err)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runQuery analyzes the project and prints the packages selected by a query of the query language
+func runQuery(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob query", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob query [flags] <query>")
+		fmt.Fprintln(flagSet.Output(), `Example: uncle-bob query "importers(internal/domain) & level>1"`)
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if flagSet.NArg() != 1 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	validateTextJSON(*format)
+
+	query, err := checker.ParseQuery(flagSet.Arg(0))
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if *format != "text" {
+		console = os.Stderr
+		clog.SetOutput(os.Stderr)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	// the selected packages are the output, the levels and violations are not printed
+	clog.SetOutput(io.Discard)
+	r, packageMap, err := analyzePackages(ctx, workDir, cfg, &af)
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	packages := make([]report.Package, 0)
+
+	selected := make(map[string]bool)
+
+	for _, pkg := range query.Eval(packageMap, r.Violations) {
+		selected[pkg] = true
+	}
+
+	for _, pkg := range r.Packages {
+		if selected[pkg.Path] {
+			packages = append(packages, pkg)
+		}
+	}
+
+	if *format == "json" {
+		result := struct {
+			Module   string           `json:"module"`
+			Query    string           `json:"query"`
+			Packages []report.Package `json:"packages"`
+		}{r.Module, flagSet.Arg(0), packages}
+
+		writeJSON(result)
+
+		return
+	}
+
+	printPackages(os.Stdout, packages)
+}
+
+// printPackages prints the level, layer and path of the packages as a table
+func printPackages(w io.Writer, packages []report.Package) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Level\tLayer\tPackage")
+
+	for _, pkg := range packages {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", pkg.Level, pkg.Layer, shortPath(pkg.Path))
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,175 @@
+// Package render prints the results of the checker on the console with clog, the checker itself only returns them
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// quiet discards the printed results
+var quiet bool
+
+// SetQuiet discards the printed results when q is set, for the commands serving the results instead of printing
+// them. It is set before the analyses start.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// Results prints check results, such as the warnings of the mapping
+func Results(results []clog.CheckResult) {
+	if quiet {
+		return
+	}
+
+	for _, result := range results {
+		clog.PrintColorMessage(result)
+	}
+}
+
+// Levels prints the packages of every level, layerNames holds the declared layer name of every level,
+// nil when levels are inferred
+func Levels(packageLevels [][]string, layerNames []string) {
+	var results []clog.CheckResult
+
+	for lvl, packageLevel := range packageLevels {
+		msg := fmt.Sprintf("Level %v packages:\n", lvl)
+
+		if lvl < len(layerNames) {
+			msg = fmt.Sprintf("Level %v (%v) packages:\n", lvl, layerNames[lvl])
+		}
+
+		for _, packageImport := range packageLevel {
+			msg = fmt.Sprintf("%v%v \n", msg, packageImport)
+		}
+
+		results = append(results, clog.NewInfo(msg))
+	}
+
+	Results(results)
+}
+
+// Violations prints the violations, followed by the violations of the test graph and the suppressed violations
+func Violations(violations []checker.Violation) {
+	if quiet {
+		return
+	}
+
+	var found, test, suppressed []checker.Violation
+
+	for _, violation := range violations {
+		switch {
+		case violation.Suppressed:
+			suppressed = append(suppressed, violation)
+		case violation.Test:
+			test = append(test, violation)
+		default:
+			found = append(found, violation)
+		}
+	}
+
+	printViolations(found)
+
+	// the test graph is reported apart from the production code
+	if len(test) > 0 {
+		clog.Info("Test graph violations:\n")
+		printViolations(test)
+	}
+
+	if len(suppressed) == 0 {
+		return
+	}
+
+	clog.Info(fmt.Sprintf("%v suppressed violations:\n", len(suppressed)))
+
+	for _, violation := range suppressed {
+		clog.Info(violation.String())
+	}
+}
+
+// Drift prints the dependencies declared by the configuration that the code no longer has
+func Drift(drift []checker.Drift) {
+	if quiet {
+		return
+	}
+
+	if len(drift) == 0 {
+		return
+	}
+
+	clog.Info("Architecture drift, declared dependencies without imports:\n")
+
+	for _, d := range drift {
+		clog.Warning(d.Message)
+	}
+}
+
+func printViolations(violations []checker.Violation) {
+	for _, violation := range violations {
+		if violation.Severity == checker.SeverityInfo {
+			clog.Info(violation.String())
+		} else {
+			clog.Warning(violation.String())
+		}
+	}
+}
+
+// PackageImports prints the imports of the files of the packages shown for -package-imports name. When name is
+// an import path glob, every package is printed with its path and followed by a summary of the imports with
+// the number of packages importing them.
+func PackageImports(name string, pkgs []checker.PackageImports) {
+	pattern := checker.IsPackagePattern(name)
+
+	if pattern {
+		clog.Info("Packages matching: " + name)
+	} else {
+		clog.Info("Package: " + name)
+	}
+
+	for _, pkg := range pkgs {
+		if pattern {
+			clog.Info("Package: " + pkg.Path + "\n")
+		}
+
+		for _, file := range pkg.Files {
+			msg := fmt.Sprintf("file: %v \n imports: \n", file.File)
+
+			for _, fileImport := range file.Imports {
+				msg = fmt.Sprintf("%v\n<-- %v", msg, fileImport)
+			}
+
+			clog.Info(fmt.Sprintf("%v \n\n", msg))
+		}
+	}
+
+	if pattern && len(pkgs) > 0 {
+		clog.Info(importSummary(checker.ImportCounts(pkgs), len(pkgs)))
+	}
+}
+
+// importSummary lists the imports by the number of packages importing them, then by import path
+func importSummary(importers map[string]int, packages int) string {
+	imports := make([]string, 0, len(importers))
+
+	for pkgImport := range importers {
+		imports = append(imports, pkgImport)
+	}
+
+	sort.Slice(imports, func(i, j int) bool {
+		if importers[imports[i]] != importers[imports[j]] {
+			return importers[imports[i]] > importers[imports[j]]
+		}
+
+		return imports[i] < imports[j]
+	})
+
+	msg := fmt.Sprintf("Imports of the %v matching packages: \n", packages)
+
+	for _, pkgImport := range imports {
+		msg = fmt.Sprintf("%v\n%4d  %v", msg, importers[pkgImport], pkgImport)
+	}
+
+	return fmt.Sprintf("%v \n\n", msg)
+}
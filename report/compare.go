@@ -0,0 +1,146 @@
+package report
+
+import (
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// Comparison is the architecture drift between an old and a new report
+type Comparison struct {
+	Old                 string              `json:"old"`
+	New                 string              `json:"new"`
+	AddedPackages       []string            `json:"addedPackages"`
+	RemovedPackages     []string            `json:"removedPackages"`
+	AddedDependencies   []Edge              `json:"addedDependencies"`
+	RemovedDependencies []Edge              `json:"removedDependencies"`
+	LevelChanges        []LevelChange       `json:"levelChanges"`
+	NewViolations       []checker.Violation `json:"newViolations"`
+	FixedViolations     []checker.Violation `json:"fixedViolations"`
+}
+
+// LevelChange is a package found on a different level in the new report
+type LevelChange struct {
+	Path     string `json:"path"`
+	OldLevel int    `json:"oldLevel"`
+	NewLevel int    `json:"newLevel"`
+	OldLayer string `json:"oldLayer,omitempty"`
+	NewLayer string `json:"newLayer,omitempty"`
+}
+
+// Compare compares two reports, oldName and newName name them in the comparison (ex. the git revisions)
+func Compare(oldName string, oldReport Report, newName string, newReport Report) Comparison {
+	c := Comparison{
+		Old:                 oldName,
+		New:                 newName,
+		AddedPackages:       make([]string, 0),
+		RemovedPackages:     make([]string, 0),
+		AddedDependencies:   make([]Edge, 0),
+		RemovedDependencies: make([]Edge, 0),
+		LevelChanges:        make([]LevelChange, 0),
+		NewViolations:       make([]checker.Violation, 0),
+		FixedViolations:     make([]checker.Violation, 0),
+	}
+
+	oldPackages := packagesByPath(oldReport)
+	newPackages := packagesByPath(newReport)
+
+	for _, pkg := range newReport.Packages {
+		oldPkg, ok := oldPackages[pkg.Path]
+
+		if !ok {
+			c.AddedPackages = append(c.AddedPackages, pkg.Path)
+			continue
+		}
+
+		if oldPkg.Level != pkg.Level || oldPkg.Layer != pkg.Layer {
+			c.LevelChanges = append(c.LevelChanges, LevelChange{
+				Path:     pkg.Path,
+				OldLevel: oldPkg.Level,
+				NewLevel: pkg.Level,
+				OldLayer: oldPkg.Layer,
+				NewLayer: pkg.Layer,
+			})
+		}
+	}
+
+	for _, pkg := range oldReport.Packages {
+		if _, ok := newPackages[pkg.Path]; !ok {
+			c.RemovedPackages = append(c.RemovedPackages, pkg.Path)
+		}
+	}
+
+	c.AddedDependencies = append(c.AddedDependencies, missingEdges(newReport.Edges, oldReport.Edges)...)
+	c.RemovedDependencies = append(c.RemovedDependencies, missingEdges(oldReport.Edges, newReport.Edges)...)
+	c.NewViolations = append(c.NewViolations, missingViolations(newReport.Violations, oldReport.Violations)...)
+	c.FixedViolations = append(c.FixedViolations, missingViolations(oldReport.Violations, newReport.Violations)...)
+
+	return c
+}
+
+// HasNewViolations reports whether the new report has unsuppressed violations missing in the old one
+func (c Comparison) HasNewViolations() bool {
+	for _, violation := range c.NewViolations {
+		if violation.Fails() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func packagesByPath(r Report) map[string]Package {
+	packages := make(map[string]Package, len(r.Packages))
+
+	for _, pkg := range r.Packages {
+		packages[pkg.Path] = pkg
+	}
+
+	return packages
+}
+
+// missingEdges returns the edges of a that are not in b
+func missingEdges(a []Edge, b []Edge) []Edge {
+	inB := make(map[[2]string]bool, len(b))
+
+	for _, edge := range b {
+		inB[[2]string{edge.From, edge.To}] = true
+	}
+
+	var missing []Edge
+
+	for _, edge := range a {
+		if !inB[[2]string{edge.From, edge.To}] {
+			missing = append(missing, edge)
+		}
+	}
+
+	return missing
+}
+
+// violationKey identifies a violation across reports by its fingerprint, reports written before fingerprints
+// were added are keyed by the rule and the packages
+func violationKey(violation checker.Violation) string {
+	if violation.Fingerprint != "" {
+		return violation.Fingerprint
+	}
+
+	return violation.Rule + "\x00" + violation.From + "\x00" + violation.To
+}
+
+// missingViolations returns the violations of a that are not in b
+func missingViolations(a []checker.Violation, b []checker.Violation) []checker.Violation {
+	inB := make(map[string]bool, len(b))
+
+	for _, violation := range b {
+		inB[violationKey(violation)] = true
+	}
+
+	var missing []checker.Violation
+
+	for _, violation := range a {
+		if !inB[violationKey(violation)] {
+			missing = append(missing, violation)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the import edges as CSV, one edge per row
+func WriteCSV(w io.Writer, r Report) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"from", "to", "from_level", "to_level", "violation"}); err != nil {
+		return err
+	}
+
+	for _, edge := range r.Edges {
+		record := []string{
+			edge.From,
+			edge.To,
+			strconv.Itoa(edge.FromLevel),
+			strconv.Itoa(edge.ToLevel),
+			strconv.FormatBool(edge.Violation),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	r := Report{Edges: []Edge{
+		{From: "example.com/app", To: "example.com/app/user", FromLevel: 0, ToLevel: 1},
+		{From: "example.com/app/user", To: "example.com/app/billing", FromLevel: 1, ToLevel: 1, Violation: true},
+		{From: `example.com/app/"quoted",path`, To: "example.com/app/db", FromLevel: 1, ToLevel: 2},
+	}}
+
+	want := `from,to,from_level,to_level,violation
+example.com/app,example.com/app/user,0,1,false
+example.com/app/user,example.com/app/billing,1,1,true
+"example.com/app/""quoted"",path",example.com/app/db,1,2,false
+`
+
+	var buf bytes.Buffer
+
+	if err := WriteCSV(&buf, r); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	if buf.String() != want {
+		t.Errorf("WriteCSV() =\n%v\nwant\n%v", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := WriteCSV(&buf, Report{}); err != nil || buf.String() != "from,to,from_level,to_level,violation\n" {
+		t.Errorf("WriteCSV() of an empty report = %q, %v, want the header", buf.String(), err)
+	}
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCypher writes a Cypher script creating a Package node per package and an IMPORTS relationship per import,
+// to query the dependency graph in Neo4j
+func WriteCypher(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	b.WriteString("CREATE CONSTRAINT package_path IF NOT EXISTS FOR (p:Package) REQUIRE p.path IS UNIQUE;\n")
+
+	for _, pkg := range r.Packages {
+		fmt.Fprintf(&b, "MERGE (p:Package {path: %v}) SET p.module = %v, p.level = %v, p.layer = %v, p.external = %v;\n",
+			cypherString(pkg.Path), cypherString(r.Module), pkg.Level, cypherString(pkg.Layer), pkg.External)
+	}
+
+	rules := make(map[[2]string][]string)
+	codes := make(map[[2]string][]string)
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed {
+			key := [2]string{violation.From, violation.To}
+			rules[key] = append(rules[key], cypherString(violation.Rule))
+			codes[key] = append(codes[key], cypherString(violation.Code))
+		}
+	}
+
+	for _, edge := range r.Edges {
+		key := [2]string{edge.From, edge.To}
+
+		fmt.Fprintf(&b, "MATCH (a:Package {path: %v}), (b:Package {path: %v}) MERGE (a)-[i:IMPORTS]->(b) SET i.violation = %v, i.rules = [%v], i.codes = [%v];\n",
+			cypherString(edge.From), cypherString(edge.To), edge.Violation, strings.Join(rules[key], ", "), strings.Join(codes[key], ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// cypherString quotes a Cypher string literal
+func cypherString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`).Replace(s) + "'"
+}
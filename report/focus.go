@@ -0,0 +1,148 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// Focus returns the part of the report around the package focus: the package, the packages it imports and the
+// packages importing it up to depth imports away, with the edges and violations between them.
+// focus is an import path or a path relative to the module root.
+func Focus(r Report, focus string, depth int) (Report, error) {
+	path, ok := resolvePackage(r, focus)
+	if !ok {
+		return Report{}, fmt.Errorf("package %v not found", focus)
+	}
+
+	imports := make(map[string][]string)
+	importers := make(map[string][]string)
+
+	for _, edge := range r.Edges {
+		imports[edge.From] = append(imports[edge.From], edge.To)
+		importers[edge.To] = append(importers[edge.To], edge.From)
+	}
+
+	selected := map[string]bool{path: true}
+
+	for _, next := range []map[string][]string{imports, importers} {
+		for pkg := range reachable(path, next, depth) {
+			selected[pkg] = true
+		}
+	}
+
+	return subgraph(r, selected, func(Edge) bool { return true }), nil
+}
+
+// ViolationsOnly returns the part of the report with the violating imports: the packages of the unsuppressed
+// violations, their violating edges and the violations
+func ViolationsOnly(r Report) Report {
+	selected := make(map[string]bool)
+
+	for _, edge := range r.Edges {
+		if edge.Violation {
+			selected[edge.From] = true
+			selected[edge.To] = true
+		}
+	}
+
+	violating := subgraph(r, selected, func(edge Edge) bool { return edge.Violation })
+	violating.Violations = make([]checker.Violation, 0)
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed {
+			violating.Violations = append(violating.Violations, violation)
+		}
+	}
+
+	return violating
+}
+
+// subgraph returns the report of the selected packages, with the edges between them kept by keep and the
+// violations between them. Levels without selected packages are left out, the summary is cleared.
+func subgraph(r Report, selected map[string]bool, keep func(Edge) bool) Report {
+	narrowed := r
+	narrowed.Packages = make([]Package, 0)
+	narrowed.Levels = make([]Level, 0)
+	narrowed.Edges = make([]Edge, 0)
+	narrowed.Violations = make([]checker.Violation, 0)
+	narrowed.Summary = nil
+
+	for _, pkg := range r.Packages {
+		if selected[pkg.Path] {
+			narrowed.Packages = append(narrowed.Packages, pkg)
+		}
+	}
+
+	for _, level := range r.Levels {
+		packages := make([]string, 0)
+
+		for _, pkg := range level.Packages {
+			if selected[pkg] {
+				packages = append(packages, pkg)
+			}
+		}
+
+		if len(packages) > 0 {
+			narrowed.Levels = append(narrowed.Levels, Level{Level: level.Level, Layer: level.Layer, Packages: packages})
+		}
+	}
+
+	for _, edge := range r.Edges {
+		if selected[edge.From] && selected[edge.To] && keep(edge) {
+			narrowed.Edges = append(narrowed.Edges, edge)
+		}
+	}
+
+	for _, violation := range r.Violations {
+		if selected[violation.From] && selected[violation.To] {
+			narrowed.Violations = append(narrowed.Violations, violation)
+		}
+	}
+
+	return narrowed
+}
+
+// reachable returns the packages reachable from pkg through next in at most depth steps, with their distance from pkg
+func reachable(pkg string, next map[string][]string, depth int) map[string]int {
+	distance := map[string]int{pkg: 0}
+	queue := []string{pkg}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if distance[current] == depth {
+			continue
+		}
+
+		for _, neighbour := range next[current] {
+			if _, seen := distance[neighbour]; !seen {
+				distance[neighbour] = distance[current] + 1
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	return distance
+}
+
+// resolvePackage returns the package of the report named by an import path or a path relative to the module root
+func resolvePackage(r Report, name string) (string, bool) {
+	candidates := []string{name, strings.TrimSuffix(r.Module+"/"+strings.TrimPrefix(name, "/"), "/")}
+
+	if name == "." {
+		candidates = append(candidates, r.Module)
+	}
+
+	for _, candidate := range candidates {
+		for _, pkg := range r.Packages {
+			if pkg.Path == candidate {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// WriteGitHubAnnotations writes a GitHub Actions workflow command for every import declaration of a violation,
+// so the violations show up as pull request annotations. Suppressed violations are written as notices.
+func WriteGitHubAnnotations(w io.Writer, r Report) error {
+	for _, violation := range r.Violations {
+		command := "error"
+
+		switch {
+		case violation.Suppressed || violation.Severity == checker.SeverityInfo:
+			command = "notice"
+		case violation.Severity == checker.SeverityWarning:
+			command = "warning"
+		}
+
+		message := fmt.Sprintf("%v: %v imports %v. %v", violation.Message, violation.From, violation.To, violation.Suggestion)
+
+		if violation.Suppressed {
+			message = fmt.Sprintf("%v (suppressed: %v)", message, violation.SuppressReason)
+		}
+
+		properties := []string{"title=" + escapeGitHubProperty("uncle-bob "+violation.Code+" "+violation.Rule)}
+
+		// a violation without known import declarations is annotated without a location
+		locations := violation.Locations
+
+		if len(locations) == 0 {
+			if _, err := fmt.Fprintf(w, "::%v %v::%v\n", command, strings.Join(properties, ","), escapeGitHubData(message)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for _, site := range locations {
+			siteProperties := append([]string{
+				"file=" + escapeGitHubProperty(site.File),
+				fmt.Sprintf("line=%v", site.Line),
+			}, properties...)
+
+			if _, err := fmt.Fprintf(w, "::%v %v::%v\n", command, strings.Join(siteProperties, ","), escapeGitHubData(message)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
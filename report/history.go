@@ -0,0 +1,121 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultHistoryFile is the file of the check history, relative to the project root
+const DefaultHistoryFile = ".unclebob/history.jsonl"
+
+// HistoryEntry is the summary of a check run, appended as a line of the history file
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Module string    `json:"module"`
+	// Commit is the abbreviated HEAD commit, when the project is a git repository
+	Commit   string   `json:"commit,omitempty"`
+	Summary  Summary  `json:"summary"`
+	Coupling Coupling `json:"coupling"`
+}
+
+// Coupling measures the coupling of the packages of a report
+type Coupling struct {
+	ImportsPerPackage float64 `json:"importsPerPackage"`
+	MaxFanIn          int     `json:"maxFanIn"`
+	MaxFanOut         int     `json:"maxFanOut"`
+	// Instability and Distance are the mean design metrics of the packages, when the report has metrics
+	Instability *float64 `json:"instability,omitempty"`
+	Distance    *float64 `json:"distance,omitempty"`
+}
+
+// NewHistoryEntry returns the history entry of a summarized report
+func NewHistoryEntry(r Report, summary Summary, commit string, t time.Time) HistoryEntry {
+	entry := HistoryEntry{
+		Time:    t.UTC(),
+		Module:  r.Module,
+		Commit:  commit,
+		Summary: summary,
+	}
+
+	if len(r.Packages) > 0 {
+		entry.Coupling.ImportsPerPackage = float64(len(r.Edges)) / float64(len(r.Packages))
+	}
+
+	entry.Coupling.MaxFanIn, entry.Coupling.MaxFanOut = maxFan(r)
+
+	if len(r.Metrics) > 0 {
+		var instability, distance float64
+
+		for _, m := range r.Metrics {
+			instability += m.Instability
+			distance += m.Distance
+		}
+
+		instability /= float64(len(r.Metrics))
+		distance /= float64(len(r.Metrics))
+
+		entry.Coupling.Instability, entry.Coupling.Distance = &instability, &distance
+	}
+
+	return entry
+}
+
+// AppendHistory appends the entry to the history file at path, creating the file and its directory
+func AppendHistory(path string, entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(data, '\n'))
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// ReadHistory reads the entries of the history file at path, oldest first
+func ReadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var entries []HistoryEntry
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry HistoryEntry
+
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
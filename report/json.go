@@ -0,0 +1,36 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WriteJSON writes the report as an indented JSON document
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r)
+}
+
+// ReadJSON reads a report written with WriteJSON from the file at path
+func ReadJSON(path string) (Report, error) {
+	var r Report
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("%v: %v", path, err)
+	}
+
+	if r.Module == "" {
+		return r, fmt.Errorf("%v is not a JSON report of uncle-bob, it has no module", path)
+	}
+
+	return r, nil
+}
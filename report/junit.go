@@ -0,0 +1,116 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes every rule as a JUnit test case, failing with the violations of the rule.
+// Suppressed violations and violations of a lower severity are listed in the test case output.
+func WriteJUnit(w io.Writer, r Report) error {
+	suite := junitTestSuite{
+		Name: r.Module,
+	}
+
+	for _, rule := range checker.ViolationRules {
+		testCase := junitTestCase{
+			Name:      rule.Code + " " + rule.ID,
+			ClassName: toolName,
+		}
+
+		var failures []string
+		var suppressed []string
+		var reported []string
+
+		for _, violation := range r.Violations {
+			if violation.Rule != rule.ID {
+				continue
+			}
+
+			switch {
+			case violation.Suppressed:
+				suppressed = append(suppressed, violation.String())
+			case !violation.Fails():
+				reported = append(reported, violation.String())
+			default:
+				failures = append(failures, violation.String())
+			}
+		}
+
+		if len(failures) > 0 {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%v violations: %v", len(failures), rule.Description),
+				Type:    rule.Code,
+				Text:    strings.Join(failures, "\n") + "\n" + rule.Suggestion,
+			}
+			suite.Failures++
+		}
+
+		var output []string
+
+		if len(reported) > 0 {
+			output = append(output, fmt.Sprintf("%v violations below the error severity:\n%v", len(reported), strings.Join(reported, "\n")))
+		}
+
+		if len(suppressed) > 0 {
+			output = append(output, fmt.Sprintf("%v suppressed violations:\n%v", len(suppressed), strings.Join(suppressed, "\n")))
+		}
+
+		testCase.SystemOut = strings.Join(output, "\n")
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(junitTestSuites{
+		Name:     toolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	r := Report{Module: "example.com/app", Violations: []checker.Violation{
+		{Rule: checker.RuleSameLevel, From: "example.com/app/user", To: "example.com/app/billing", Severity: checker.SeverityError},
+		{Rule: checker.RuleSameLevel, From: "example.com/app/billing", To: "example.com/app/user", Severity: checker.SeverityError},
+		{Rule: checker.RuleSameLevel, From: "example.com/app/api", To: "example.com/app/web", Severity: checker.SeverityError, Suppressed: true, SuppressReason: "legacy"},
+		{Rule: checker.RuleImportCycle, From: "example.com/app/db", To: "example.com/app/user", Severity: checker.SeverityWarning},
+		{Rule: checker.RuleBannedImport, From: "example.com/app/db", To: "log", Severity: checker.SeverityError, Suppressed: true},
+	}}
+
+	var buf bytes.Buffer
+
+	if err := WriteJUnit(&buf, r); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var suites junitTestSuites
+
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("WriteJUnit() wrote invalid XML: %v", err)
+	}
+
+	if suites.Tests != len(checker.ViolationRules) || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Fatalf("WriteJUnit() = %v tests, %v failures, %v suites, want %v tests, 1 failure, 1 suite", suites.Tests, suites.Failures, len(suites.Suites), len(checker.ViolationRules))
+	}
+
+	suite := suites.Suites[0]
+
+	if suite.Name != r.Module || suite.Tests != suites.Tests || suite.Failures != suites.Failures {
+		t.Errorf("WriteJUnit() suite %v = %v tests, %v failures", suite.Name, suite.Tests, suite.Failures)
+	}
+
+	testCases := make(map[string]junitTestCase)
+
+	for _, testCase := range suite.TestCases {
+		testCases[strings.Fields(testCase.Name)[1]] = testCase
+	}
+
+	sameLevel := testCases[checker.RuleSameLevel]
+
+	if sameLevel.Failure == nil || !strings.HasPrefix(sameLevel.Failure.Message, "2 violations") || !strings.Contains(sameLevel.SystemOut, "1 suppressed violations") {
+		t.Errorf("same-level test case = %+v, want 2 failures and 1 suppressed violation", sameLevel)
+	}
+
+	if cycle := testCases[checker.RuleImportCycle]; cycle.Failure != nil || !strings.Contains(cycle.SystemOut, "1 violations below the error severity") {
+		t.Errorf("import-cycle test case = %+v, want the warning in the output", cycle)
+	}
+
+	if banned := testCases[checker.RuleBannedImport]; banned.Failure != nil || !strings.Contains(banned.SystemOut, "1 suppressed violations") {
+		t.Errorf("banned-import test case = %+v, want the suppressed violation in the output", banned)
+	}
+
+	if layerSkip := testCases[checker.RuleLayerSkip]; layerSkip.Failure != nil || layerSkip.SystemOut != "" {
+		t.Errorf("layer-skip test case = %+v, want a passing test case", layerSkip)
+	}
+}
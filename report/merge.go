@@ -0,0 +1,192 @@
+package report
+
+import (
+	"path"
+	"sort"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// PrefixLocations prefixes the file paths of the violation locations with dir, so that the report of a nested
+// module points at files relative to the root of the analyzed tree
+func (r *Report) PrefixLocations(dir string) {
+	if dir == "" || dir == "." {
+		return
+	}
+
+	for i, violation := range r.Violations {
+		locations := make([]checker.ImportSite, 0, len(violation.Locations))
+
+		for _, location := range violation.Locations {
+			location.File = path.Join(dir, location.File)
+			locations = append(locations, location)
+		}
+
+		r.Violations[i].Locations = locations
+	}
+}
+
+// Merge aggregates the reports of several modules into one report, levels with the same number are merged.
+// The module of the aggregate report is the module of the first report.
+func Merge(reports []Report) Report {
+	if len(reports) == 0 {
+		return Report{}
+	}
+
+	r := Report{
+		Module:     reports[0].Module,
+		Packages:   make([]Package, 0),
+		Levels:     make([]Level, 0),
+		Edges:      make([]Edge, 0),
+		Violations: make([]checker.Violation, 0),
+	}
+
+	for _, moduleReport := range reports {
+		r.Modules = append(r.Modules, moduleReport.Module)
+		r.Partial = r.Partial || moduleReport.Partial
+		r.Packages = append(r.Packages, moduleReport.Packages...)
+		r.Edges = append(r.Edges, moduleReport.Edges...)
+		r.Violations = append(r.Violations, moduleReport.Violations...)
+		r.Drift = append(r.Drift, moduleReport.Drift...)
+		r.Metrics = append(r.Metrics, moduleReport.Metrics...)
+		r.Unchanged = append(r.Unchanged, moduleReport.Unchanged...)
+		r.Untargeted = append(r.Untargeted, moduleReport.Untargeted...)
+
+		for _, level := range moduleReport.Levels {
+			for len(r.Levels) <= level.Level {
+				r.Levels = append(r.Levels, Level{Level: len(r.Levels), Packages: make([]string, 0)})
+			}
+
+			merged := &r.Levels[level.Level]
+			merged.Packages = append(merged.Packages, level.Packages...)
+
+			if merged.Layer == "" {
+				merged.Layer = level.Layer
+			}
+		}
+	}
+
+	sort.SliceStable(r.Packages, func(i, j int) bool {
+		return r.Packages[i].Path < r.Packages[j].Path
+	})
+
+	return r
+}
+
+// Union combines the reports of the same module analyzed for several platforms, names holds the platform of
+// every report. Packages and edges are added to the first report, violations record the platforms they were
+// found on.
+func Union(reports []Report, names []string) Report {
+	if len(reports) == 0 {
+		return Report{}
+	}
+
+	r := reports[0]
+	r.Packages = append([]Package{}, r.Packages...)
+	r.Edges = append([]Edge{}, r.Edges...)
+	r.Violations = make([]checker.Violation, 0)
+
+	packages := packagesByPath(r)
+	edges := make(map[[2]string]int)
+
+	for i, edge := range r.Edges {
+		edges[[2]string{edge.From, edge.To}] = i
+	}
+
+	violations := make(map[string]int)
+
+	for i, platformReport := range reports {
+		r.Partial = r.Partial || platformReport.Partial
+
+		for _, pkg := range platformReport.Packages {
+			if _, ok := packages[pkg.Path]; !ok {
+				packages[pkg.Path] = pkg
+				r.Packages = append(r.Packages, pkg)
+			}
+		}
+
+		for _, edge := range platformReport.Edges {
+			key := [2]string{edge.From, edge.To}
+
+			if j, ok := edges[key]; ok {
+				r.Edges[j].Violation = r.Edges[j].Violation || edge.Violation
+				r.Edges[j].Weight = max(r.Edges[j].Weight, edge.Weight)
+				continue
+			}
+
+			edges[key] = len(r.Edges)
+			r.Edges = append(r.Edges, edge)
+		}
+
+		for _, violation := range platformReport.Violations {
+			key := violationKey(violation)
+
+			if j, ok := violations[key]; ok {
+				r.Violations[j].Platforms = append(r.Violations[j].Platforms, names[i])
+				continue
+			}
+
+			violation.Platforms = []string{names[i]}
+			violations[key] = len(r.Violations)
+			r.Violations = append(r.Violations, violation)
+		}
+	}
+
+	r.Drift = commonDrift(reports)
+	r.Unchanged = unionViolations(reports, func(r Report) []checker.Violation { return r.Unchanged })
+	r.Untargeted = unionViolations(reports, func(r Report) []checker.Violation { return r.Untargeted })
+
+	sort.SliceStable(r.Packages, func(i, j int) bool {
+		return r.Packages[i].Path < r.Packages[j].Path
+	})
+
+	return r
+}
+
+// unionViolations returns the violations of the reports selected by violations, each violation once
+func unionViolations(reports []Report, violations func(Report) []checker.Violation) []checker.Violation {
+	var union []checker.Violation
+
+	seen := make(map[string]bool)
+
+	for _, platformReport := range reports {
+		for _, violation := range violations(platformReport) {
+			if key := violationKey(violation); !seen[key] {
+				seen[key] = true
+				union = append(union, violation)
+			}
+		}
+	}
+
+	return union
+}
+
+// commonDrift returns the drift of the first report found in all reports, a dependency imported on one of the
+// platforms is not drift
+func commonDrift(reports []Report) []checker.Drift {
+	var drift []checker.Drift
+
+	for _, d := range reports[0].Drift {
+		common := true
+
+		for _, platformReport := range reports[1:] {
+			common = common && containsDrift(platformReport.Drift, d)
+		}
+
+		if common {
+			drift = append(drift, d)
+		}
+	}
+
+	return drift
+}
+
+func containsDrift(drift []checker.Drift, d checker.Drift) bool {
+	for _, other := range drift {
+		if other == d {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func TestMerge(t *testing.T) {
+	a := Report{
+		Module:     "example.com/a",
+		Packages:   []Package{{Path: "example.com/a/user", Level: 1}, {Path: "example.com/a", Level: 0}},
+		Levels:     []Level{{Level: 0, Packages: []string{"example.com/a"}}, {Level: 1, Packages: []string{"example.com/a/user"}}},
+		Edges:      []Edge{{From: "example.com/a", To: "example.com/a/user"}},
+		Violations: []checker.Violation{{Rule: checker.RuleSameLevel, From: "example.com/a/user", To: "example.com/a/db"}},
+		Unchanged:  []checker.Violation{{Rule: checker.RuleSameLevel, From: "example.com/a/db", To: "example.com/a/user"}},
+	}
+
+	b := Report{
+		Module:   "example.com/b",
+		Partial:  true,
+		Packages: []Package{{Path: "example.com/b", Level: 0, Layer: "app"}},
+		Levels: []Level{
+			{Level: 0, Layer: "app", Packages: []string{"example.com/b"}},
+			{Level: 1, Layer: "domain", Packages: []string{}},
+			{Level: 2, Layer: "infra", Packages: []string{"example.com/b/db"}},
+		},
+		Drift: []checker.Drift{{Kind: "layer", From: "app", To: "infra"}},
+	}
+
+	r := Merge([]Report{a, b})
+
+	if r.Module != "example.com/a" || !reflect.DeepEqual(r.Modules, []string{"example.com/a", "example.com/b"}) {
+		t.Errorf("Merge() module = %v, modules = %v", r.Module, r.Modules)
+	}
+
+	if !r.Partial {
+		t.Errorf("Merge() of a partial report is not partial")
+	}
+
+	wantLevels := []Level{
+		{Level: 0, Layer: "app", Packages: []string{"example.com/a", "example.com/b"}},
+		{Level: 1, Layer: "domain", Packages: []string{"example.com/a/user"}},
+		{Level: 2, Layer: "infra", Packages: []string{"example.com/b/db"}},
+	}
+
+	if !reflect.DeepEqual(r.Levels, wantLevels) {
+		t.Errorf("Merge() levels = %v, want %v", r.Levels, wantLevels)
+	}
+
+	var paths []string
+
+	for _, pkg := range r.Packages {
+		paths = append(paths, pkg.Path)
+	}
+
+	if want := []string{"example.com/a", "example.com/a/user", "example.com/b"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Merge() packages = %v, want %v", paths, want)
+	}
+
+	if len(r.Edges) != 1 || len(r.Violations) != 1 || len(r.Drift) != 1 || len(r.Unchanged) != 1 {
+		t.Errorf("Merge() = %v edges, %v violations, %v drift, %v unchanged, want 1 of each", len(r.Edges), len(r.Violations), len(r.Drift), len(r.Unchanged))
+	}
+
+	if got := Merge(nil); !reflect.DeepEqual(got, Report{}) {
+		t.Errorf("Merge(nil) = %v, want an empty report", got)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	sameLevel := checker.Violation{Rule: checker.RuleSameLevel, Fingerprint: "f1", From: "app/user", To: "app/billing"}
+	windowsOnly := checker.Violation{Rule: checker.RuleSameLevel, Fingerprint: "f2", From: "app/user", To: "app/winapi"}
+	untargeted := checker.Violation{Rule: checker.RuleSameLevel, Fingerprint: "f3", From: "app/api", To: "app/db"}
+
+	linux := Report{
+		Module:     "app",
+		Packages:   []Package{{Path: "app/user"}, {Path: "app/billing"}, {Path: "app/unix"}},
+		Edges:      []Edge{{From: "app/user", To: "app/billing", Weight: 1}, {From: "app/user", To: "app/unix", Weight: 1}},
+		Violations: []checker.Violation{sameLevel},
+		Drift:      []checker.Drift{{Kind: "layer", From: "app", To: "infra"}, {Kind: "layer", From: "app", To: "unix"}},
+		Untargeted: []checker.Violation{untargeted},
+	}
+
+	windows := Report{
+		Module:   "app",
+		Partial:  true,
+		Packages: []Package{{Path: "app/winapi"}, {Path: "app/user"}, {Path: "app/billing"}},
+		Edges: []Edge{
+			{From: "app/user", To: "app/billing", Weight: 3, Violation: true},
+			{From: "app/user", To: "app/winapi", Weight: 1, Violation: true},
+		},
+		Violations: []checker.Violation{windowsOnly, sameLevel},
+		Drift:      []checker.Drift{{Kind: "layer", From: "app", To: "infra"}},
+		Untargeted: []checker.Violation{untargeted},
+	}
+
+	r := Union([]Report{linux, windows}, []string{"linux/amd64", "windows/amd64"})
+
+	if !r.Partial {
+		t.Errorf("Union() of a partial report is not partial")
+	}
+
+	var paths []string
+
+	for _, pkg := range r.Packages {
+		paths = append(paths, pkg.Path)
+	}
+
+	if want := []string{"app/billing", "app/unix", "app/user", "app/winapi"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Union() packages = %v, want %v", paths, want)
+	}
+
+	wantEdges := []Edge{
+		{From: "app/user", To: "app/billing", Weight: 3, Violation: true},
+		{From: "app/user", To: "app/unix", Weight: 1},
+		{From: "app/user", To: "app/winapi", Weight: 1, Violation: true},
+	}
+
+	if !reflect.DeepEqual(r.Edges, wantEdges) {
+		t.Errorf("Union() edges = %v, want %v", r.Edges, wantEdges)
+	}
+
+	platforms := make(map[string][]string)
+
+	for _, violation := range r.Violations {
+		platforms[violation.Fingerprint] = violation.Platforms
+	}
+
+	wantPlatforms := map[string][]string{"f1": {"linux/amd64", "windows/amd64"}, "f2": {"windows/amd64"}}
+
+	if !reflect.DeepEqual(platforms, wantPlatforms) {
+		t.Errorf("Union() platforms = %v, want %v", platforms, wantPlatforms)
+	}
+
+	if len(r.Untargeted) != 1 {
+		t.Errorf("Union() untargeted = %v, want the violation found on both platforms once", r.Untargeted)
+	}
+
+	if want := []checker.Drift{{Kind: "layer", From: "app", To: "infra"}}; !reflect.DeepEqual(r.Drift, want) {
+		t.Errorf("Union() drift = %v, want %v", r.Drift, want)
+	}
+
+	if len(linux.Edges) != 2 || linux.Edges[0].Weight != 1 || linux.Edges[0].Violation {
+		t.Errorf("Union() changed the edges of the first report: %v", linux.Edges)
+	}
+
+	if linux.Violations[0].Platforms != nil {
+		t.Errorf("Union() changed the violations of the first report: %v", linux.Violations)
+	}
+}
+
+func Test_commonDrift(t *testing.T) {
+	infra := checker.Drift{Kind: "layer", From: "app", To: "infra"}
+	unix := checker.Drift{Kind: "layer", From: "app", To: "unix"}
+	rule := checker.Drift{Kind: "rule", From: "app/*", To: "infra/*"}
+
+	tests := []struct {
+		name    string
+		reports []Report
+		want    []checker.Drift
+	}{
+		{"single report", []Report{{Drift: []checker.Drift{infra, unix}}}, []checker.Drift{infra, unix}},
+		{"common to all", []Report{{Drift: []checker.Drift{infra, unix}}, {Drift: []checker.Drift{unix, infra}}}, []checker.Drift{infra, unix}},
+		{"imported on a platform", []Report{{Drift: []checker.Drift{infra, unix, rule}}, {Drift: []checker.Drift{infra, rule}}, {Drift: []checker.Drift{rule, infra}}}, []checker.Drift{infra, rule}},
+		{"no common drift", []Report{{Drift: []checker.Drift{unix}}, {Drift: []checker.Drift{infra}}}, nil},
+		{"only on a later platform", []Report{{}, {Drift: []checker.Drift{infra}}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonDrift(tt.reports); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commonDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
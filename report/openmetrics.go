@@ -0,0 +1,98 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// WriteOpenMetrics writes the architecture health of the report as OpenMetrics text, to be pushed into Prometheus
+func WriteOpenMetrics(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	metric := func(name string, help string) {
+		fmt.Fprintf(&b, "# TYPE %v gauge\n# HELP %v %v\n", name, name, help)
+	}
+
+	fmt.Fprintf(&b, "# TYPE uncle_bob_module info\n# HELP uncle_bob_module The analyzed module.\n")
+	fmt.Fprintf(&b, "uncle_bob_module_info{module=\"%v\",partial=\"%v\"} 1\n", escapeLabel(r.Module), r.Partial)
+
+	violations := make(map[string]int)
+	suppressed := 0
+
+	for _, violation := range r.Violations {
+		if violation.Suppressed {
+			suppressed++
+		} else {
+			violations[violation.Rule]++
+		}
+	}
+
+	metric("uncle_bob_violations", "Unsuppressed violations by rule.")
+	for _, rule := range checker.ViolationRules {
+		fmt.Fprintf(&b, "uncle_bob_violations{code=\"%v\",rule=\"%v\"} %v\n", escapeLabel(rule.Code), escapeLabel(rule.ID), violations[rule.ID])
+	}
+
+	metric("uncle_bob_suppressed_violations", "Violations suppressed by unclebob:ignore comments.")
+	fmt.Fprintf(&b, "uncle_bob_suppressed_violations %v\n", suppressed)
+
+	metric("uncle_bob_packages", "Packages by level.")
+	for _, level := range r.Levels {
+		fmt.Fprintf(&b, "uncle_bob_packages{level=\"%v\",layer=\"%v\"} %v\n", level.Level, escapeLabel(level.Layer), len(level.Packages))
+	}
+
+	metric("uncle_bob_dependencies", "Imports between the analyzed packages.")
+	fmt.Fprintf(&b, "uncle_bob_dependencies %v\n", len(r.Edges))
+
+	fanIn, fanOut := maxFan(r)
+
+	metric("uncle_bob_max_fan_in", "Highest number of packages importing a single package.")
+	fmt.Fprintf(&b, "uncle_bob_max_fan_in %v\n", fanIn)
+
+	metric("uncle_bob_max_fan_out", "Highest number of packages imported by a single package.")
+	fmt.Fprintf(&b, "uncle_bob_max_fan_out %v\n", fanOut)
+
+	metric("uncle_bob_cycles", "Import cycles, as strongly connected groups of packages.")
+	fmt.Fprintf(&b, "uncle_bob_cycles %v\n", len(importCycles(r)))
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// maxFan returns the highest fan-in and fan-out of the packages of the report
+func maxFan(r Report) (int, int) {
+	fanIn := make(map[string]int)
+	fanOut := make(map[string]int)
+	maxIn, maxOut := 0, 0
+
+	for _, edge := range r.Edges {
+		fanIn[edge.To]++
+		fanOut[edge.From]++
+
+		maxIn = max(maxIn, fanIn[edge.To])
+		maxOut = max(maxOut, fanOut[edge.From])
+	}
+
+	return maxIn, maxOut
+}
+
+// importCycles returns the groups of packages importing each other, directly or through other packages
+func importCycles(r Report) [][]string {
+	imports := make(map[string][]string)
+
+	for _, edge := range r.Edges {
+		imports[edge.From] = append(imports[edge.From], edge.To)
+	}
+
+	return checker.StronglyConnected(imports)
+}
+
+// escapeLabel escapes an OpenMetrics label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,163 @@
+// Package report renders the analysis results in machine-readable formats
+package report
+
+import (
+	"sort"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// Report is the analysis result rendered by the output formats
+type Report struct {
+	Module string `json:"module"`
+	// Modules lists the modules of an aggregate report of several modules
+	Modules []string `json:"modules,omitempty"`
+	// Partial is set when the analysis was interrupted
+	Partial    bool                `json:"partial,omitempty"`
+	Packages   []Package           `json:"packages"`
+	Levels     []Level             `json:"levels"`
+	Edges      []Edge              `json:"edges"`
+	Violations []checker.Violation `json:"violations"`
+	// Drift lists the dependencies declared by the configuration that the code no longer has
+	Drift []checker.Drift `json:"drift,omitempty"`
+	// Metrics are the design metrics of the project packages, when requested
+	Metrics []checker.PackageMetrics `json:"metrics,omitempty"`
+	// Summary is the overview of the report printed after the checks
+	Summary *Summary `json:"summary,omitempty"`
+	// Unchanged and Untargeted are the violations left out of the report by the changed files and the checked
+	// packages, they are only counted
+	Unchanged  []checker.Violation `json:"-"`
+	Untargeted []checker.Violation `json:"-"`
+}
+
+// Package is a package of the analyzed project, or a third-party module pseudo package
+type Package struct {
+	Path     string   `json:"path"`
+	Level    int      `json:"level"`
+	Layer    string   `json:"layer,omitempty"`
+	External bool     `json:"external,omitempty"`
+	Vendored bool     `json:"vendored,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	Imports  []string `json:"imports,omitempty"`
+	// Lines is the number of lines of the package files
+	Lines int `json:"lines,omitempty"`
+	// FanIn is the number of packages importing the package, FanOut the number of packages it imports
+	FanIn  int `json:"fanIn"`
+	FanOut int `json:"fanOut"`
+}
+
+// Level lists the packages of a level
+type Level struct {
+	Level    int      `json:"level"`
+	Layer    string   `json:"layer,omitempty"`
+	Packages []string `json:"packages"`
+}
+
+// Edge is an import of a package by another one
+type Edge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	FromLevel int    `json:"fromLevel"`
+	ToLevel   int    `json:"toLevel"`
+	Violation bool   `json:"violation"`
+	// Weight is the number of files of the importing package importing the imported one
+	Weight int `json:"weight"`
+}
+
+// New builds a report from the package map, the packages by level and the violations found by the checks.
+// layerNames holds the declared layer name of every level, nil when levels are inferred.
+func New(packageMap map[string]checker.PackageInfo, packageLevels [][]string, layerNames []string, violations []checker.Violation) Report {
+	r := Report{
+		Module:     checker.ModPath,
+		Packages:   make([]Package, 0, len(packageMap)),
+		Levels:     make([]Level, 0, len(packageLevels)),
+		Edges:      make([]Edge, 0),
+		Violations: violations,
+	}
+
+	if r.Violations == nil {
+		r.Violations = make([]checker.Violation, 0)
+	}
+
+	for lvl, levelPackages := range packageLevels {
+		level := Level{
+			Level:    lvl,
+			Packages: levelPackages,
+		}
+
+		if lvl < len(layerNames) {
+			level.Layer = layerNames[lvl]
+		}
+
+		r.Levels = append(r.Levels, level)
+	}
+
+	violating := make(map[[2]string]bool)
+
+	for _, violation := range violations {
+		if !violation.Suppressed {
+			violating[[2]string{violation.From, violation.To}] = true
+		}
+	}
+
+	fanIn := make(map[string]int)
+
+	for _, packageInfo := range packageMap {
+		for _, pkgImport := range packageInfo.Imports {
+			fanIn[pkgImport]++
+		}
+	}
+
+	paths := make([]string, 0, len(packageMap))
+
+	for path := range packageMap {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		packageInfo := packageMap[path]
+
+		// imports are kept in source order by the checker, artifacts list them sorted to diff cleanly
+		imports := append([]string{}, packageInfo.Imports...)
+		sort.Strings(imports)
+
+		r.Packages = append(r.Packages, Package{
+			Path:     packageInfo.Path,
+			Level:    packageInfo.Level,
+			Layer:    packageInfo.Layer,
+			External: packageInfo.External,
+			Vendored: packageInfo.Vendored,
+			Files:    packageInfo.Files,
+			Lines:    packageInfo.Lines,
+			FanIn:    fanIn[path],
+			FanOut:   len(imports),
+			Imports:  imports,
+		})
+
+		for _, pkgImport := range imports {
+			r.Edges = append(r.Edges, Edge{
+				From:      path,
+				To:        pkgImport,
+				FromLevel: packageInfo.Level,
+				ToLevel:   packageMap[pkgImport].Level,
+				Violation: violating[[2]string{path, pkgImport}],
+				Weight:    importingFiles(packageInfo.ImportSites[pkgImport]),
+			})
+		}
+	}
+
+	return r
+}
+
+// importingFiles returns the number of files of the import declarations, at least one for every import
+func importingFiles(sites []checker.ImportSite) int {
+	files := make(map[string]bool)
+
+	for _, site := range sites {
+		files[site.File] = true
+	}
+
+	return max(len(files), 1)
+}
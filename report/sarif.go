@@ -0,0 +1,180 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "uncle-bob"
+	toolURI      = "https://github.com/audi70r/uncle-bob"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	HelpURI              string             `json:"helpUri"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation    `json:"physicalLocation"`
+	Properties       *sarifLocationProperties `json:"properties,omitempty"`
+}
+
+type sarifLocationProperties struct {
+	// Blame is the last change of the location, with -blame
+	Blame *checker.Blame `json:"blame"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// WriteSARIF writes the violations as a SARIF 2.1.0 log, with the offending import declarations as locations
+func WriteSARIF(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				InformationURI: toolURI,
+				Rules:          make([]sarifRule, 0, len(checker.ViolationRules)),
+			},
+		},
+		Results: make([]sarifResult, 0, len(r.Violations)),
+	}
+
+	ruleIndex := make(map[string]int)
+
+	for i, rule := range checker.ViolationRules {
+		ruleIndex[rule.ID] = i
+
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   rule.Code,
+			Name:                 rule.ID,
+			HelpURI:              rule.DocURL(),
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			Help:                 sarifMessage{Text: rule.Suggestion},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		})
+	}
+
+	for _, violation := range r.Violations {
+		result := sarifResult{
+			RuleID:    violation.Code,
+			RuleIndex: ruleIndex[violation.Rule],
+			Level:     sarifLevel(violation.Severity),
+			Message:   sarifMessage{Text: violation.Message + ": " + violation.From + " imports " + violation.To + ". " + violation.Suggestion},
+		}
+
+		// code scanning matches the results of successive runs by their fingerprints
+		if violation.Fingerprint != "" {
+			result.PartialFingerprints = map[string]string{"uncleBobFingerprint/v1": violation.Fingerprint}
+		}
+
+		for _, site := range violation.Locations {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: site.File, URIBaseID: "%SRCROOT%"},
+				},
+			}
+
+			if site.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: site.Line}
+			}
+
+			if site.Blame != nil {
+				location.Properties = &sarifLocationProperties{Blame: site.Blame}
+			}
+
+			result.Locations = append(result.Locations, location)
+		}
+
+		if violation.Suppressed {
+			result.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: violation.SuppressReason}}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifLevel returns the SARIF level of a violation severity
+func sarifLevel(severity string) string {
+	switch severity {
+	case checker.SeverityWarning:
+		return "warning"
+	case checker.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	r := Report{Violations: []checker.Violation{
+		{Rule: checker.RuleSameLevel, Code: "UB001", Fingerprint: "f1", Severity: checker.SeverityError, Locations: []checker.ImportSite{{File: "user/user.go", Line: 5}}},
+		{Rule: checker.RuleImportCycle, Code: "UB006", Severity: checker.SeverityWarning, Locations: []checker.ImportSite{{File: "db/db.go"}}},
+		{Rule: checker.RuleBannedImport, Code: "UB012", Severity: checker.SeverityInfo},
+		{Rule: checker.RuleSameLevel, Code: "UB001", Severity: checker.SeverityError, Suppressed: true, SuppressReason: "legacy"},
+	}}
+
+	var buf bytes.Buffer
+
+	if err := WriteSARIF(&buf, r); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("WriteSARIF() wrote invalid JSON: %v", err)
+	}
+
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("WriteSARIF() version %v with %v runs", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != len(checker.ViolationRules) {
+		t.Errorf("WriteSARIF() wrote %v rules, want %v", len(run.Tool.Driver.Rules), len(checker.ViolationRules))
+	}
+
+	wantLevels := []string{"error", "warning", "note", "error"}
+
+	if len(run.Results) != len(wantLevels) {
+		t.Fatalf("WriteSARIF() wrote %v results, want %v", len(run.Results), len(wantLevels))
+	}
+
+	for i, result := range run.Results {
+		if rule := run.Tool.Driver.Rules[result.RuleIndex]; rule.ID != result.RuleID || rule.Name != r.Violations[i].Rule {
+			t.Errorf("result %v of rule %v has the rule index of %v", i, result.RuleID, rule.ID)
+		}
+
+		if result.Level != wantLevels[i] {
+			t.Errorf("result %v level = %v, want %v", i, result.Level, wantLevels[i])
+		}
+	}
+
+	if got := run.Results[0]; got.PartialFingerprints["uncleBobFingerprint/v1"] != "f1" || got.Locations[0].PhysicalLocation.Region.StartLine != 5 {
+		t.Errorf("result 0 = %+v, want the fingerprint and the line of the import", got)
+	}
+
+	if got := run.Results[1].Locations[0].PhysicalLocation; got.Region != nil || got.ArtifactLocation.URI != "db/db.go" {
+		t.Errorf("result 1 location = %+v, want the file without a region", got)
+	}
+
+	if got := run.Results[3].Suppressions; len(got) != 1 || got[0].Kind != "inSource" || got[0].Justification != "legacy" {
+		t.Errorf("result 3 suppressions = %+v, want the in source suppression with its reason", got)
+	}
+
+	if len(run.Results[0].Suppressions) != 0 {
+		t.Errorf("result 0 is suppressed")
+	}
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// WriteSlack writes the summary of the report as a Slack incoming webhook message
+func WriteSlack(w io.Writer, r Report) error {
+	s := Summarize(r, DefaultTopOffenders)
+
+	if r.Summary != nil {
+		s = *r.Summary
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%v*: %v violations in `%v`", toolName, s.Violations, r.Module)
+
+	if s.Suppressed > 0 {
+		fmt.Fprintf(&b, ", %v suppressed", s.Suppressed)
+	}
+
+	for _, rule := range s.Rules {
+		fmt.Fprintf(&b, "\n• %v %v: %v", rule.Code, rule.Rule, rule.Violations)
+	}
+
+	if s.Cycles > 0 {
+		fmt.Fprintf(&b, "\n%v import cycles", s.Cycles)
+	}
+
+	if len(s.Offenders) > 0 {
+		var offenders []string
+
+		for _, offender := range s.Offenders {
+			offenders = append(offenders, fmt.Sprintf("`%v` (%v)", shortPath(offender.Package), offender.Causes))
+		}
+
+		fmt.Fprintf(&b, "\nTop offenders: %v", strings.Join(offenders, ", "))
+	}
+
+	return json.NewEncoder(w).Encode(slackMessage{Text: b.String()})
+}
+
+// shortPath trims the module path from a package import path for display
+func shortPath(importPath string) string {
+	if importPath == checker.ModPath {
+		return "/"
+	}
+
+	return strings.TrimPrefix(importPath, checker.ModPath)
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// DefaultSnapshotFile is the file of the approved dependencies, relative to the project root
+const DefaultSnapshotFile = ".unclebob/approved.json"
+
+// Snapshot is the approved dependency graph of a project, checks against it fail on dependencies it does not have
+type Snapshot struct {
+	Module       string         `json:"module"`
+	Dependencies []ApprovedEdge `json:"dependencies"`
+}
+
+// ApprovedEdge is an approved import of a package by another one
+type ApprovedEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NewSnapshot returns the snapshot of the dependencies of the report, sorted to diff cleanly
+func NewSnapshot(r Report) Snapshot {
+	s := Snapshot{
+		Module:       r.Module,
+		Dependencies: make([]ApprovedEdge, 0, len(r.Edges)),
+	}
+
+	for _, edge := range r.Edges {
+		s.Dependencies = append(s.Dependencies, ApprovedEdge{From: edge.From, To: edge.To})
+	}
+
+	sort.Slice(s.Dependencies, func(i, j int) bool {
+		if s.Dependencies[i].From != s.Dependencies[j].From {
+			return s.Dependencies[i].From < s.Dependencies[j].From
+		}
+
+		return s.Dependencies[i].To < s.Dependencies[j].To
+	})
+
+	return s
+}
+
+// Unapproved returns the edges of the report missing in the snapshot
+func (s Snapshot) Unapproved(r Report) []Edge {
+	approved := make([]Edge, 0, len(s.Dependencies))
+
+	for _, edge := range s.Dependencies {
+		approved = append(approved, Edge{From: edge.From, To: edge.To})
+	}
+
+	return missingEdges(r.Edges, approved)
+}
+
+// Approves reports whether the import of a violation is an approved dependency
+func (s Snapshot) Approves(violation checker.Violation) bool {
+	for _, edge := range s.Dependencies {
+		if edge.From == violation.From && edge.To == violation.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Removed returns the approved dependencies missing in the report
+func (s Snapshot) Removed(r Report) []ApprovedEdge {
+	present := make(map[ApprovedEdge]bool, len(r.Edges))
+
+	for _, edge := range r.Edges {
+		present[ApprovedEdge{From: edge.From, To: edge.To}] = true
+	}
+
+	var removed []ApprovedEdge
+
+	for _, edge := range s.Dependencies {
+		if !present[edge] {
+			removed = append(removed, edge)
+		}
+	}
+
+	return removed
+}
+
+// ReadSnapshot reads the snapshot file at path
+func ReadSnapshot(path string) (Snapshot, error) {
+	var s Snapshot
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%v: %v", path, err)
+	}
+
+	return s, nil
+}
+
+// WriteSnapshot writes the snapshot to the file at path, creating its directory
+func WriteSnapshot(path string, s Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
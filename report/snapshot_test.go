@@ -0,0 +1,125 @@
+package report
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	r := Report{
+		Module: "example.com/app",
+		Edges: []Edge{
+			{From: "example.com/app/user", To: "example.com/app/db", Weight: 2},
+			{From: "example.com/app/api", To: "example.com/app/user", Violation: true},
+			{From: "example.com/app/api", To: "example.com/app/db"},
+		},
+	}
+
+	want := Snapshot{
+		Module: "example.com/app",
+		Dependencies: []ApprovedEdge{
+			{From: "example.com/app/api", To: "example.com/app/db"},
+			{From: "example.com/app/api", To: "example.com/app/user"},
+			{From: "example.com/app/user", To: "example.com/app/db"},
+		},
+	}
+
+	if got := NewSnapshot(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSnapshot() = %v, want %v", got, want)
+	}
+
+	if got := NewSnapshot(Report{}); got.Dependencies == nil || len(got.Dependencies) != 0 {
+		t.Errorf("NewSnapshot() of an empty report = %#v, want no dependencies", got.Dependencies)
+	}
+}
+
+func TestSnapshot_Unapproved(t *testing.T) {
+	s := Snapshot{Dependencies: []ApprovedEdge{
+		{From: "api", To: "user"},
+		{From: "user", To: "db"},
+		{From: "api", To: "legacy"},
+	}}
+
+	r := Report{Edges: []Edge{
+		{From: "api", To: "user"},
+		{From: "user", To: "db"},
+		{From: "user", To: "api", Violation: true},
+		{From: "db", To: "user"},
+	}}
+
+	unapproved := s.Unapproved(r)
+
+	if want := []Edge{{From: "user", To: "api", Violation: true}, {From: "db", To: "user"}}; !reflect.DeepEqual(unapproved, want) {
+		t.Errorf("Unapproved() = %v, want %v", unapproved, want)
+	}
+
+	if want := []ApprovedEdge{{From: "api", To: "legacy"}}; !reflect.DeepEqual(s.Removed(r), want) {
+		t.Errorf("Removed() = %v, want %v", s.Removed(r), want)
+	}
+
+	approved := NewSnapshot(r)
+
+	if got := approved.Unapproved(r); len(got) != 0 {
+		t.Errorf("Unapproved() of the snapshot of the report = %v, want none", got)
+	}
+
+	if got := approved.Removed(r); len(got) != 0 {
+		t.Errorf("Removed() of the snapshot of the report = %v, want none", got)
+	}
+}
+
+func TestSnapshot_Approves(t *testing.T) {
+	s := Snapshot{Dependencies: []ApprovedEdge{{From: "api", To: "user"}}}
+
+	tests := []struct {
+		name      string
+		violation checker.Violation
+		want      bool
+	}{
+		{"approved dependency", checker.Violation{Rule: checker.RuleSameLevel, From: "api", To: "user"}, true},
+		{"reversed dependency", checker.Violation{Rule: checker.RuleSameLevel, From: "user", To: "api"}, false},
+		{"other dependency", checker.Violation{Rule: checker.RuleSameLevel, From: "api", To: "db"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Approves(tt.violation); got != tt.want {
+				t.Errorf("Approves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "approved.json")
+
+	if _, err := ReadSnapshot(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadSnapshot() of a missing file error = %v, want os.ErrNotExist", err)
+	}
+
+	want := NewSnapshot(Report{Module: "example.com/app", Edges: []Edge{{From: "api", To: "user"}}})
+
+	if err := WriteSnapshot(path, want); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	if got, err := ReadSnapshot(path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSnapshot() = %v, %v, want %v", got, err, want)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+
+	if err := os.WriteFile(corrupt, []byte(`{"module": "example.com/app", "dependencies": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadSnapshot(corrupt); err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadSnapshot() of a corrupt file error = %v, want a decoding error", err)
+	}
+}
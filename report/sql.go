@@ -0,0 +1,117 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sqlSchema creates the tables of WriteSQL, every run of the analysis adds a row to runs and the rows
+// of the other tables reference it, so that runs can be compared
+const sqlSchema = `CREATE TABLE IF NOT EXISTS runs (
+  id INTEGER PRIMARY KEY,
+  time TEXT NOT NULL,
+  module TEXT NOT NULL,
+  partial INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS levels (
+  run INTEGER NOT NULL REFERENCES runs(id),
+  level INTEGER NOT NULL,
+  layer TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS packages (
+  run INTEGER NOT NULL REFERENCES runs(id),
+  path TEXT NOT NULL,
+  level INTEGER NOT NULL,
+  layer TEXT NOT NULL,
+  external INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS edges (
+  run INTEGER NOT NULL REFERENCES runs(id),
+  from_package TEXT NOT NULL,
+  to_package TEXT NOT NULL,
+  from_level INTEGER NOT NULL,
+  to_level INTEGER NOT NULL,
+  violation INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS violations (
+  run INTEGER NOT NULL REFERENCES runs(id),
+  id TEXT NOT NULL,
+  code TEXT NOT NULL,
+  rule TEXT NOT NULL,
+  severity TEXT NOT NULL,
+  from_package TEXT NOT NULL,
+  to_package TEXT NOT NULL,
+  from_level INTEGER NOT NULL,
+  to_level INTEGER NOT NULL,
+  message TEXT NOT NULL,
+  suppressed INTEGER NOT NULL,
+  suppress_reason TEXT NOT NULL,
+  fingerprint TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS violation_locations (
+  run INTEGER NOT NULL REFERENCES runs(id),
+  violation TEXT NOT NULL,
+  file TEXT NOT NULL,
+  line INTEGER NOT NULL
+);
+`
+
+// sqlRun is the id of the run inserted by the script
+const sqlRun = "(SELECT max(id) FROM runs)"
+
+// WriteSQL writes the packages, levels, edges and violations of the report as a SQLite script, which creates
+// the tables when needed and adds the report as a new run in one transaction
+func WriteSQL(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	b.WriteString("BEGIN TRANSACTION;\n")
+	b.WriteString(sqlSchema)
+
+	fmt.Fprintf(&b, "INSERT INTO runs (time, module, partial) VALUES (strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now'), %v, %v);\n",
+		sqlString(r.Module), sqlBool(r.Partial))
+
+	for _, level := range r.Levels {
+		fmt.Fprintf(&b, "INSERT INTO levels VALUES (%v, %v, %v);\n", sqlRun, level.Level, sqlString(level.Layer))
+	}
+
+	for _, pkg := range r.Packages {
+		fmt.Fprintf(&b, "INSERT INTO packages VALUES (%v, %v, %v, %v, %v);\n",
+			sqlRun, sqlString(pkg.Path), pkg.Level, sqlString(pkg.Layer), sqlBool(pkg.External))
+	}
+
+	for _, edge := range r.Edges {
+		fmt.Fprintf(&b, "INSERT INTO edges VALUES (%v, %v, %v, %v, %v, %v);\n",
+			sqlRun, sqlString(edge.From), sqlString(edge.To), edge.FromLevel, edge.ToLevel, sqlBool(edge.Violation))
+	}
+
+	for _, v := range r.Violations {
+		fmt.Fprintf(&b, "INSERT INTO violations VALUES (%v, %v, %v, %v, %v, %v, %v, %v, %v, %v, %v, %v, %v);\n",
+			sqlRun, sqlString(v.ID), sqlString(v.Code), sqlString(v.Rule), sqlString(v.Severity), sqlString(v.From), sqlString(v.To),
+			v.FromLevel, v.ToLevel, sqlString(v.Message), sqlBool(v.Suppressed), sqlString(v.SuppressReason), sqlString(v.Fingerprint))
+
+		for _, location := range v.Locations {
+			fmt.Fprintf(&b, "INSERT INTO violation_locations VALUES (%v, %v, %v, %v);\n",
+				sqlRun, sqlString(v.ID), sqlString(location.File), location.Line)
+		}
+	}
+
+	b.WriteString("COMMIT;\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// sqlString quotes a SQL string literal
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sqlBool(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
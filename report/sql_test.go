@@ -0,0 +1,25 @@
+package report
+
+import "testing"
+
+func Test_sqlString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"example.com/app/user", `'example.com/app/user'`},
+		{"", `''`},
+		{"it's", `'it''s'`},
+		{"''", `''''''`},
+		{`C:\app`, `'C:\app'`},
+		{"first\nsecond", "'first\nsecond'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := sqlString(tt.s); got != tt.want {
+				t.Errorf("sqlString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// DefaultTopOffenders is the number of packages ranked as the top offenders of a summary
+const DefaultTopOffenders = 5
+
+// Summary is an overview of the report, printed after the checks and included in the structured outputs
+type Summary struct {
+	Packages   int            `json:"packages"`
+	Levels     []LevelSummary `json:"levels"`
+	Edges      int            `json:"edges"`
+	Violations int            `json:"violations"`
+	Suppressed int            `json:"suppressed"`
+	// Unchanged and Untargeted are the numbers of violations left out by the changed files and the checked packages
+	Unchanged  int           `json:"unchanged,omitempty"`
+	Untargeted int           `json:"untargeted,omitempty"`
+	Rules      []RuleSummary `json:"rules"`
+	Cycles     int           `json:"cycles"`
+	// Drift is the number of declared dependencies the code no longer has
+	Drift int `json:"drift"`
+	// Offenders are the packages causing the most violations by their imports, largest first
+	Offenders []Offender `json:"offenders"`
+	// Suffering are the packages suffering the most violations, as the imported packages, largest first
+	Suffering []Offender `json:"suffering"`
+}
+
+// LevelSummary is the number of packages of a level
+type LevelSummary struct {
+	Level    int    `json:"level"`
+	Layer    string `json:"layer,omitempty"`
+	Packages int    `json:"packages"`
+}
+
+// RuleSummary is the number of unsuppressed violations of a rule
+type RuleSummary struct {
+	Code       string `json:"code"`
+	Rule       string `json:"rule"`
+	Violations int    `json:"violations"`
+}
+
+// Offender is a package with the number of unsuppressed violations of its imports, Causes, and of the imports
+// of the package, Suffers
+type Offender struct {
+	Package string `json:"package"`
+	Causes  int    `json:"causes"`
+	Suffers int    `json:"suffers"`
+}
+
+// Summarize counts the packages per level, the edges, the violations per rule and the cycles of the report,
+// and ranks the top packages causing and suffering violations. Rules without violations are left out.
+func Summarize(r Report, top int) Summary {
+	s := Summary{
+		Packages:   len(r.Packages),
+		Levels:     make([]LevelSummary, 0, len(r.Levels)),
+		Edges:      len(r.Edges),
+		Rules:      make([]RuleSummary, 0),
+		Cycles:     len(importCycles(r)),
+		Drift:      len(r.Drift),
+		Unchanged:  len(r.Unchanged),
+		Untargeted: len(r.Untargeted),
+	}
+
+	for _, level := range r.Levels {
+		s.Levels = append(s.Levels, LevelSummary{Level: level.Level, Layer: level.Layer, Packages: len(level.Packages)})
+	}
+
+	rules := make(map[string]int)
+
+	for _, violation := range r.Violations {
+		if violation.Suppressed {
+			s.Suppressed++
+			continue
+		}
+
+		s.Violations++
+		rules[violation.Rule]++
+	}
+
+	for _, rule := range checker.ViolationRules {
+		if rules[rule.ID] > 0 {
+			s.Rules = append(s.Rules, RuleSummary{Code: rule.Code, Rule: rule.ID, Violations: rules[rule.ID]})
+		}
+	}
+
+	offenders := Offenders(r)
+
+	s.Offenders = rankOffenders(offenders, top, func(o Offender) int { return o.Causes })
+	s.Suffering = rankOffenders(offenders, top, func(o Offender) int { return o.Suffers })
+
+	return s
+}
+
+// Offenders returns the packages causing or suffering unsuppressed violations, sorted by path
+func Offenders(r Report) []Offender {
+	counts := make(map[string]*Offender)
+
+	offender := func(path string) *Offender {
+		if counts[path] == nil {
+			counts[path] = &Offender{Package: path}
+		}
+
+		return counts[path]
+	}
+
+	for _, violation := range r.Violations {
+		if violation.Suppressed {
+			continue
+		}
+
+		offender(violation.From).Causes++
+		offender(violation.To).Suffers++
+	}
+
+	offenders := make([]Offender, 0, len(counts))
+
+	for _, o := range counts {
+		offenders = append(offenders, *o)
+	}
+
+	sort.Slice(offenders, func(i, j int) bool { return offenders[i].Package < offenders[j].Package })
+
+	return offenders
+}
+
+// rankOffenders returns the top offenders by count, largest first, leaving out the packages with a count of 0
+func rankOffenders(offenders []Offender, top int, count func(Offender) int) []Offender {
+	ranked := make([]Offender, 0)
+
+	for _, o := range offenders {
+		if count(o) > 0 {
+			ranked = append(ranked, o)
+		}
+	}
+
+	// the offenders are sorted by path, the stable sort keeps ties in that order
+	sort.SliceStable(ranked, func(i, j int) bool { return count(ranked[i]) > count(ranked[j]) })
+
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+
+	return ranked
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/audi70r/uncle-bob/checker"
+)
+
+// templateFuncs are the functions available to report templates, next to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// short trims the module path from the import paths of the module, like the labels of the graphs
+	"short": func(path string) string {
+		if path == checker.ModPath {
+			return path
+		}
+
+		return strings.TrimPrefix(path, checker.ModPath+"/")
+	},
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+
+		return string(data), err
+	},
+}
+
+// ParseTemplate reads a text/template file executed with the Report by WriteTemplate
+func ParseTemplate(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+}
+
+// WriteTemplate executes a template of ParseTemplate with the report
+func WriteTemplate(w io.Writer, r Report, tmpl *template.Template) error {
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/render"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/visualizer"
+)
+
+// server holds the latest analysis of the project. The analysis uses global state so it is serialized by
+// analysisMu, mu only guards the latest report so that it is served during an analysis.
+type server struct {
+	workDir    string
+	configPath string
+	flags      *analysisFlags
+
+	analysisMu sync.Mutex
+
+	mu     sync.Mutex
+	report report.Report
+	err    error
+}
+
+// runServe hosts the HTML report and a JSON API of the project in the working directory
+func runServe(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob serve", flag.ContinueOnError)
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	addr := flagSet.String("addr", ":8080", "address to listen on")
+	watch := flagSet.Bool("watch", false, "re-analyze when a .go file or the config file changes")
+	interval := flagSet.Duration("interval", 2*time.Second, "how often to look for file changes with -watch")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	PrintAA()
+
+	workDir := locateProject(&af)
+
+	// fail early on a broken config, later config errors are returned by the API
+	loadConfig(workDir, af.configPath)
+
+	// the signals stop the running analysis too
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the results are served, the console only shows the outcome of the analyses
+	render.SetQuiet(true)
+
+	s := &server{workDir: workDir, configPath: af.configPath, flags: &af}
+	s.analyze(ctx)
+
+	httpServer := &http.Server{Addr: *addr, Handler: s.routes()}
+
+	if *watch {
+		go s.watch(ctx, *interval)
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	clog.Info("Serving the report of " + checker.ModPath + " on " + *addr)
+
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", s.handleHTML)
+	mux.HandleFunc("GET /graph.svg", s.handleSVG)
+	mux.HandleFunc("GET /api/report", s.handleReport)
+	mux.HandleFunc("POST /api/analyze", s.handleAnalyze)
+
+	return mux
+}
+
+// analyze re-reads go.mod and the config, re-runs the analysis and keeps its report. An analysis canceled through
+// ctx keeps the previous report, one exceeding -timeout is reported as error.
+func (s *server) analyze(ctx context.Context) {
+	s.analysisMu.Lock()
+	defer s.analysisMu.Unlock()
+
+	err := checker.LocateModule(s.workDir, s.flags.modulePath)
+
+	var cfg checker.Config
+	if err == nil {
+		cfg, err = checker.LoadConfig(s.configFile(), s.configPath == "")
+	}
+
+	if err != nil {
+		s.update(s.report, err)
+		clog.Error(err.Error())
+		return
+	}
+
+	ctx, cancel := s.flags.withTimeout(ctx)
+	defer cancel()
+
+	r, err := analyze(ctx, s.workDir, cfg, s.flags)
+
+	if errors.Is(err, context.Canceled) {
+		clog.Warning("Analysis canceled, the previous report is kept")
+		return
+	}
+
+	if err != nil {
+		s.update(s.report, err)
+		clog.Error(err.Error())
+		return
+	}
+
+	s.update(r, nil)
+	clog.Info(fmt.Sprintf("Analyzed %v packages, found %v violations", len(r.Packages), len(r.Violations)))
+}
+
+func (s *server) configFile() string {
+	if s.configPath == "" {
+		return filepath.Join(s.workDir, checker.DefaultConfigFile)
+	}
+
+	return s.configPath
+}
+
+// update replaces the latest report and error
+func (s *server) update(r report.Report, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report, s.err = r, err
+}
+
+// current returns the latest report, or the error of the latest analysis
+func (s *server) current() (report.Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.report, s.err
+}
+
+func (s *server) handleHTML(w http.ResponseWriter, r *http.Request) {
+	s.write(w, "text/html; charset=utf-8", func(w io.Writer, rep report.Report) error {
+		return visualizer.GenerateHTMLReport(r.Context(), w, rep)
+	})
+}
+
+func (s *server) handleSVG(w http.ResponseWriter, r *http.Request) {
+	s.write(w, "image/svg+xml", func(w io.Writer, rep report.Report) error {
+		return visualizer.GenerateSVG(r.Context(), w, rep)
+	})
+}
+
+func (s *server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.write(w, "application/json", report.WriteJSON)
+}
+
+// handleAnalyze re-runs the analysis, it is canceled when the client goes away
+func (s *server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	s.analyze(r.Context())
+	s.write(w, "application/json", report.WriteJSON)
+}
+
+// write renders the latest report, analysis errors are returned as 500
+func (s *server) write(w http.ResponseWriter, contentType string, render func(io.Writer, report.Report) error) {
+	rep, err := s.current()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if err := render(w, rep); err != nil {
+		clog.Error(err.Error())
+	}
+}
+
+// watch polls the project files and re-analyzes when they change
+func (s *server) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := s.fingerprint()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := s.fingerprint(); current != last {
+				last = current
+				clog.Info("Change detected, re-analyzing")
+				s.analyze(ctx)
+			}
+		}
+	}
+}
+
+// fingerprint summarizes the .go files, go.mod and the config file by their count, size and modification time
+func (s *server) fingerprint() string {
+	var count, size int64
+	var latest time.Time
+
+	add := func(info fs.FileInfo) {
+		count++
+		size += info.Size()
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	filepath.WalkDir(s.workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if strings.HasSuffix(path, ".go") || d.Name() == "go.mod" {
+			if info, err := d.Info(); err == nil {
+				add(info)
+			}
+		}
+
+		return nil
+	})
+
+	if info, err := os.Stat(s.configFile()); err == nil {
+		add(info)
+	}
+
+	return fmt.Sprintf("%v/%v/%v", count, size, latest.UnixNano())
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// writeProject writes a module with a package importing another one to a temporary directory
+func writeProject(t *testing.T) string {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":       "module example.com/served\n\ngo 1.22\n",
+		"main.go":      "package main\n\nimport _ \"example.com/served/user\"\n\nfunc main() {}\n",
+		"user/user.go": "package user\n\nimport _ \"example.com/served/store\"\n",
+		"store/db.go":  "package store\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// request sends a request without a body and returns the response with its body
+func request(t *testing.T, method string, url string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, string(body)
+}
+
+func TestServer_routes(t *testing.T) {
+	s := &server{workDir: writeProject(t), flags: &analysisFlags{}}
+
+	httpServer := httptest.NewServer(s.routes())
+	defer httpServer.Close()
+
+	resp, body := request(t, http.MethodGet, httpServer.URL+"/api/report")
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(body) == "" {
+		t.Fatalf("GET /api/report before the analysis = %v %q", resp.Status, body)
+	}
+
+	resp, body = request(t, http.MethodPost, httpServer.URL+"/api/analyze")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("POST /api/analyze = %v %v: %v", resp.Status, resp.Header.Get("Content-Type"), body)
+	}
+
+	var analyzed report.Report
+
+	if err := json.Unmarshal([]byte(body), &analyzed); err != nil {
+		t.Fatalf("POST /api/analyze returned invalid JSON: %v", err)
+	}
+
+	if analyzed.Module != "example.com/served" || len(analyzed.Packages) != 3 || len(analyzed.Edges) != 2 {
+		t.Errorf("POST /api/analyze = module %v with %v packages and %v edges, want example.com/served with 3 packages and 2 edges", analyzed.Module, len(analyzed.Packages), len(analyzed.Edges))
+	}
+
+	tests := []struct {
+		method      string
+		path        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{http.MethodGet, "/", http.StatusOK, "text/html; charset=utf-8", "example.com/served/user"},
+		{http.MethodGet, "/graph.svg", http.StatusOK, "image/svg+xml", "<svg"},
+		{http.MethodGet, "/api/report", http.StatusOK, "application/json", `"module": "example.com/served"`},
+		{http.MethodGet, "/api/analyze", http.StatusMethodNotAllowed, "", ""},
+		{http.MethodPost, "/api/report", http.StatusMethodNotAllowed, "", ""},
+		{http.MethodGet, "/missing", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp, body := request(t, tt.method, httpServer.URL+tt.path)
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %v, want %v", resp.StatusCode, tt.status)
+			}
+
+			if tt.contentType != "" && resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %v, want %v", resp.Header.Get("Content-Type"), tt.contentType)
+			}
+
+			if !strings.Contains(body, tt.contains) {
+				t.Errorf("body does not contain %q", tt.contains)
+			}
+		})
+	}
+}
+
+func TestServer_analysisError(t *testing.T) {
+	s := &server{workDir: t.TempDir(), flags: &analysisFlags{}}
+	s.update(report.Report{Module: "example.com/previous"}, nil)
+
+	httpServer := httptest.NewServer(s.routes())
+	defer httpServer.Close()
+
+	resp, body := request(t, http.MethodPost, httpServer.URL+"/api/analyze")
+	if resp.StatusCode != http.StatusInternalServerError || !strings.Contains(body, "go.mod") {
+		t.Errorf("POST /api/analyze without go.mod = %v %q, want the error as 500", resp.Status, body)
+	}
+
+	if rep, err := s.current(); err == nil || rep.Module != "example.com/previous" {
+		t.Errorf("current() = %v, %v, want the previous report with the error", rep.Module, err)
+	}
+}
+
+func TestServer_servesDuringAnalysis(t *testing.T) {
+	s := &server{workDir: t.TempDir(), flags: &analysisFlags{}}
+	s.update(report.Report{Module: "example.com/previous"}, nil)
+
+	// a running analysis holds the analysis lock
+	s.analysisMu.Lock()
+	defer s.analysisMu.Unlock()
+
+	httpServer := httptest.NewServer(s.routes())
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/api/report", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("GET /api/report waits for the running analysis")
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /api/report during an analysis = %v, want the previous report", resp.Status)
+	}
+}
+
+// TestServer_rendersDuringAnalysis renders the report while it is re-analyzed, run with -race it checks that the
+// renderers do not share state with the analysis
+func TestServer_rendersDuringAnalysis(t *testing.T) {
+	s := &server{workDir: writeProject(t), flags: &analysisFlags{}}
+	s.analyze(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 5; i++ {
+			s.analyze(context.Background())
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+
+		for _, handler := range []http.HandlerFunc{s.handleHTML, s.handleSVG, s.handleReport} {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("rendering during an analysis = %v", rec.Code)
+			}
+		}
+	}
+}
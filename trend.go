@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+	"github.com/audi70r/uncle-bob/utilities/git"
+)
+
+// runTrend prints how the summaries of the check runs recorded in the history file evolved
+func runTrend(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob trend", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob trend [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	history := flagSet.String("history", report.DefaultHistoryFile, "history file written by check")
+	limit := flagSet.Int("limit", 0, "only show the last runs, 0 shows all of them")
+	format := flagSet.String("format", "text", "output format: text, json")
+
+	parseFlags(flagSet, args)
+
+	validateTextJSON(*format)
+
+	if *limit < 0 {
+		clog.Error("-limit can not be negative")
+		exit(exitConfigError)
+	}
+
+	entries, err := report.ReadHistory(*history)
+	if errors.Is(err, fs.ErrNotExist) {
+		clog.Error(fmt.Sprintf("no history in %v, it is recorded by every check run", *history))
+		exit(exitConfigError)
+	}
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+
+	if *format == "json" {
+		if entries == nil {
+			entries = []report.HistoryEntry{}
+		}
+
+		writeJSON(entries)
+
+		return
+	}
+
+	printTrend(os.Stdout, entries)
+}
+
+// printTrend prints the history entries as a table, followed by the changes between the first and the last one
+func printTrend(w io.Writer, entries []report.HistoryEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Time\tCommit\tPackages\tImports\tViolations\tSuppressed\tCycles\tImports/package\tFan-in\tFan-out\tI\tD")
+
+	for _, entry := range entries {
+		s, c := entry.Summary, entry.Coupling
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%.2f\t%v\t%v\t%v\t%v\n",
+			entry.Time.Local().Format(time.DateTime), orDash(entry.Commit), s.Packages, s.Edges, s.Violations, s.Suppressed,
+			s.Cycles, c.ImportsPerPackage, c.MaxFanIn, c.MaxFanOut, formatMean(c.Instability), formatMean(c.Distance))
+	}
+
+	tw.Flush()
+
+	if len(entries) < 2 {
+		return
+	}
+
+	first, last := entries[0].Summary, entries[len(entries)-1].Summary
+
+	fmt.Fprintf(w, "\nSince %v: %v violations, %v packages, %v imports, %v cycles\n",
+		entries[0].Time.Local().Format(time.DateTime), signed(last.Violations-first.Violations),
+		signed(last.Packages-first.Packages), signed(last.Edges-first.Edges), signed(last.Cycles-first.Cycles))
+}
+
+// recordHistory appends the summary of the report to the history file, a history that can not be written
+// does not fail the check
+func recordHistory(path string, r report.Report, summary report.Summary) {
+	// outside of a git repository the runs are only told apart by their time
+	commit, _ := git.Head(".")
+
+	if err := report.AppendHistory(path, report.NewHistoryEntry(r, summary, commit, time.Now())); err != nil {
+		clog.Warning("Could not record the history: " + err.Error())
+	}
+}
+
+// formatMean formats a mean design metric of the history, which is only recorded with -metrics
+func formatMean(mean *float64) string {
+	if mean == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.2f", *mean)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// signed formats a change with its sign
+func signed(change int) string {
+	return fmt.Sprintf("%+d", change)
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// panes of the terminal UI
+const (
+	paneLevels = iota
+	panePackage
+	paneViolations
+)
+
+var paneNames = []string{"Levels", "Package", "Violations"}
+
+// tuiRow is a line of a pane, pkg is the package selected by enter
+type tuiRow struct {
+	text string
+	pkg  string
+}
+
+// tui is the state of the terminal UI
+type tui struct {
+	r          report.Report
+	packageMap map[string]checker.PackageInfo
+	pane       int
+	cursor     [3]int
+	// selected is the package shown by the package pane
+	selected  string
+	filter    string
+	filtering bool
+	width     int
+	height    int
+}
+
+// runTUI analyzes the project and explores the results in a terminal UI
+func runTUI(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob tui", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob tui [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	// the results are explored in the UI, the levels and violations are not printed
+	clog.SetOutput(io.Discard)
+	r, packageMap, err := analyzePackages(ctx, workDir, cfg, &af)
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		clog.Error("the terminal UI needs an interactive unix terminal: " + err.Error())
+		exit(exitConfigError)
+	}
+
+	t := &tui{r: r, packageMap: packageMap, width: 80, height: 24}
+	t.width, t.height = terminalSize(t.width, t.height)
+
+	err = t.run(bufio.NewReader(os.Stdin), os.Stdout)
+
+	restore()
+	fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H")
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+}
+
+// rawTerminal switches the terminal to raw mode with stty, it returns the function restoring the previous mode
+func rawTerminal() (func(), error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() { stty(strings.TrimSpace(state)) }, nil
+}
+
+// terminalSize returns the size of the terminal, or the given default size when it is unknown
+func terminalSize(width int, height int) (int, int) {
+	size, err := stty("size")
+	if err != nil {
+		return width, height
+	}
+
+	var rows, cols int
+
+	// pseudo terminals without a size report 0 0
+	if _, err := fmt.Sscan(size, &rows, &cols); err != nil || rows == 0 || cols == 0 {
+		return width, height
+	}
+
+	return cols, rows
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+
+	return string(out), err
+}
+
+// run renders the UI and handles the keys until q or ctrl+c is pressed
+func (t *tui) run(in *bufio.Reader, out io.Writer) error {
+	for {
+		fmt.Fprint(out, t.render())
+
+		key, err := readKey(in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !t.handleKey(key) {
+			return nil
+		}
+	}
+}
+
+// readKey reads a key press, arrow keys are returned as "up", "down", "left" and "right"
+func readKey(in *bufio.Reader) (string, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	if b != 0x1b || in.Buffered() == 0 {
+		return string(b), nil
+	}
+
+	seq := make([]byte, 2)
+	if _, err := io.ReadFull(in, seq); err != nil {
+		return "", err
+	}
+
+	switch string(seq) {
+	case "[A":
+		return "up", nil
+	case "[B":
+		return "down", nil
+	case "[C":
+		return "right", nil
+	case "[D":
+		return "left", nil
+	}
+
+	return "", nil
+}
+
+// handleKey updates the state for a key press, it returns false to quit
+func (t *tui) handleKey(key string) bool {
+	if t.filtering {
+		switch key {
+		case "\r", "\n", "\x1b":
+			t.filtering = false
+		case "\x7f", "\b":
+			if t.filter != "" {
+				t.filter = t.filter[:len(t.filter)-1]
+			}
+		default:
+			if len(key) == 1 && key[0] >= ' ' {
+				t.filter += key
+			}
+		}
+
+		t.cursor[t.pane] = 0
+
+		return true
+	}
+
+	rows := t.rows()
+
+	switch key {
+	case "q", "\x03":
+		return false
+	case "\t", "right", "l":
+		t.pane = (t.pane + 1) % len(paneNames)
+	case "left", "h":
+		t.pane = (t.pane + len(paneNames) - 1) % len(paneNames)
+	case "1", "2", "3":
+		t.pane = int(key[0] - '1')
+	case "down", "j":
+		if t.cursor[t.pane] < len(rows)-1 {
+			t.cursor[t.pane]++
+		}
+	case "up", "k":
+		if t.cursor[t.pane] > 0 {
+			t.cursor[t.pane]--
+		}
+	case "/":
+		t.filtering = true
+		t.filter = ""
+	case "\r", "\n":
+		if t.cursor[t.pane] < len(rows) && rows[t.cursor[t.pane]].pkg != "" {
+			t.selected = rows[t.cursor[t.pane]].pkg
+			t.pane = panePackage
+			t.cursor[panePackage] = 0
+		}
+	}
+
+	return true
+}
+
+// rows returns the lines of the active pane matching the filter
+func (t *tui) rows() []tuiRow {
+	var rows []tuiRow
+
+	switch t.pane {
+	case paneLevels:
+		for _, level := range t.r.Levels {
+			for _, pkg := range level.Packages {
+				rows = append(rows, tuiRow{text: fmt.Sprintf("Lv%v  %v  %v", level.Level, level.Layer, shortPath(pkg)), pkg: pkg})
+			}
+		}
+	case panePackage:
+		rows = t.packageRows()
+	case paneViolations:
+		for _, violation := range t.r.Violations {
+			text := fmt.Sprintf("%v  %v  %v <-- %v", violation.ID, violation.Code, shortPath(violation.From), shortPath(violation.To))
+
+			if !violation.Fails() {
+				text += "  (" + violation.Severity + ")"
+			}
+
+			rows = append(rows, tuiRow{text: text, pkg: violation.From})
+		}
+	}
+
+	if t.filter == "" {
+		return rows
+	}
+
+	var filtered []tuiRow
+
+	for _, row := range rows {
+		if strings.Contains(row.text, t.filter) {
+			filtered = append(filtered, row)
+		}
+	}
+
+	return filtered
+}
+
+// packageRows lists the imports and the importers of the selected package
+func (t *tui) packageRows() []tuiRow {
+	if t.selected == "" {
+		return []tuiRow{{text: "Select a package with enter in the levels or violations pane"}}
+	}
+
+	packageInfo := t.packageMap[t.selected]
+
+	rows := []tuiRow{{text: fmt.Sprintf("%v  level %v  %v", shortPath(t.selected), packageInfo.Level, packageInfo.Layer)}}
+	rows = append(rows, tuiRow{text: "Imports:"})
+
+	for _, pkgImport := range packageInfo.Imports {
+		rows = append(rows, tuiRow{text: fmt.Sprintf("  --> Lv%v  %v", t.packageMap[pkgImport].Level, shortPath(pkgImport)), pkg: pkgImport})
+	}
+
+	rows = append(rows, tuiRow{text: "Importers:"})
+
+	for _, importer := range checker.Importers(t.packageMap, t.selected) {
+		rows = append(rows, tuiRow{text: fmt.Sprintf("  <-- Lv%v  %v", importer.Level, shortPath(importer.Path)), pkg: importer.Path})
+	}
+
+	return rows
+}
+
+// render draws the whole screen, the terminal is in raw mode so lines end with \r\n
+func (t *tui) render() string {
+	var b strings.Builder
+
+	b.WriteString("\x1b[2J\x1b[H")
+
+	for i, name := range paneNames {
+		tab := fmt.Sprintf(" %v %v ", i+1, name)
+
+		if i == t.pane {
+			tab = "\x1b[7m" + tab + "\x1b[0m"
+		}
+
+		b.WriteString(tab)
+	}
+
+	b.WriteString("\r\n\r\n")
+
+	rows := t.rows()
+	visible := max(t.height-4, 1)
+	cursor := min(t.cursor[t.pane], max(len(rows)-1, 0))
+	first := max(cursor-visible+1, 0)
+
+	for i := first; i < len(rows) && i < first+visible; i++ {
+		text := rows[i].text
+
+		if len(text) > t.width {
+			text = text[:t.width]
+		}
+
+		if i == cursor {
+			text = "\x1b[7m" + text + "\x1b[0m"
+		}
+
+		b.WriteString(text + "\r\n")
+	}
+
+	b.WriteString(fmt.Sprintf("\x1b[%v;1H", t.height))
+
+	if t.filtering {
+		b.WriteString("/" + t.filter)
+	} else {
+		b.WriteString("tab/1-3 pane  j/k move  enter select  / filter  q quit")
+
+		if t.filter != "" {
+			b.WriteString("  filter: " + t.filter)
+		}
+	}
+
+	return b.String()
+}
@@ -2,11 +2,20 @@ package clog
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
 type resultType string
 type color string
 
+var output io.Writer = os.Stdout
+
+// SetOutput sets the destination of printed messages, os.Stdout by default
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 // CheckResult is the result of dependency checking
 type CheckResult struct {
 	resultType resultType
@@ -56,6 +65,16 @@ func NewWarning(msg string) CheckResult {
 	}
 }
 
+// IsError reports whether the result is an error
+func (cr CheckResult) IsError() bool {
+	return cr.resultType == resultErr
+}
+
 func PrintColorMessage(cr CheckResult) {
-	fmt.Printf("%s%-11s%s\n%s", cr.color, "["+cr.resultType+"]", cr.Message, reset)
+	if newHandler != nil {
+		logRecord(cr)
+		return
+	}
+
+	fmt.Fprintf(output, "%s%-11s%s\n%s", cr.color, "["+cr.resultType+"]", cr.Message, reset)
 }
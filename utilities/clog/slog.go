@@ -0,0 +1,51 @@
+package clog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// newHandler builds the slog handler receiving the messages on the output, the messages are printed in color when nil
+var newHandler func(w io.Writer) slog.Handler
+
+// SetHandler sends the messages as slog records to the handler built by newHandler on the output, so that they
+// can be shipped to log aggregation. nil restores the colored output.
+//
+//	clog.SetHandler(func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, nil) })
+func SetHandler(h func(w io.Writer) slog.Handler) {
+	newHandler = h
+}
+
+// Structured reports whether the messages are sent to a slog handler instead of printed in color
+func Structured() bool {
+	return newHandler != nil
+}
+
+// Level returns the slog level of the result
+func (cr CheckResult) Level() slog.Level {
+	switch cr.resultType {
+	case resultErr:
+		return slog.LevelError
+	case resultWarning:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// logRecord sends the result to the slog handler, the message is trimmed of the spacing of the colored output
+func logRecord(cr CheckResult) {
+	handler := newHandler(output)
+	ctx := context.Background()
+
+	if !handler.Enabled(ctx, cr.Level()) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), cr.Level(), strings.TrimSpace(cr.Message), 0)
+
+	handler.Handle(ctx, record)
+}
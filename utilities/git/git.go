@@ -0,0 +1,257 @@
+// Package git runs the git commands needed to compare the project with other revisions
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// run runs git in dir and returns its output
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %v: %v %v", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// ChangedFiles returns the files under dir changed in the working tree since its merge base with ref, including
+// untracked files, like "git diff ref...HEAD" the changes made on ref since the branch left it are not included.
+// The paths are relative to dir and slash separated.
+func ChangedFiles(dir string, ref string) ([]string, error) {
+	base, err := run(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := run(dir, "diff", "--name-only", "--relative", strings.TrimSpace(base), "--")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := run(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, line := range strings.Split(diff+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, filepath.ToSlash(line))
+		}
+	}
+
+	return files, nil
+}
+
+// Head returns the abbreviated commit hash of HEAD of the repository containing dir
+func Head(dir string) (string, error) {
+	head, err := run(dir, "rev-parse", "--short", "HEAD")
+
+	return strings.TrimSpace(head), err
+}
+
+// Commit is a commit of the history
+type Commit struct {
+	Hash string
+	// Short is the abbreviated hash
+	Short   string
+	Time    time.Time
+	Subject string
+}
+
+// commitFormat is the git log format of the fields of Commit, separated by unit separators
+const commitFormat = "--format=%H%x1f%h%x1f%ct%x1f%s"
+
+// Commits returns the commit since and the commits following it up to HEAD, oldest first. Merged branches are
+// not followed, their changes appear with the merge commit.
+func Commits(dir string, since string) ([]Commit, error) {
+	first, err := run(dir, "log", "-1", commitFormat, since, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := run(dir, "log", "--reverse", "--first-parent", commitFormat, since+"..HEAD", "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+
+	for _, line := range strings.Split(first+rest, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("git log: unexpected output %q", line)
+		}
+
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git log: invalid commit time %q", fields[2])
+		}
+
+		commits = append(commits, Commit{Hash: fields[0], Short: fields[1], Time: time.Unix(seconds, 0).UTC(), Subject: fields[3]})
+	}
+
+	return commits, nil
+}
+
+// BlameLine is the last change of a line
+type BlameLine struct {
+	// Commit is empty when the line is not committed yet
+	Commit string
+	Author string
+	Email  string
+	Time   time.Time
+}
+
+// Blame returns the last change of the line of file, relative to dir
+func Blame(dir string, file string, line int) (BlameLine, error) {
+	out, err := run(dir, "blame", "--porcelain", "-L", fmt.Sprintf("%v,%v", line, line), "--", file)
+	if err != nil {
+		return BlameLine{}, err
+	}
+
+	var blame BlameLine
+
+	for i, field := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(field, " ")
+
+		switch {
+		case i == 0:
+			if strings.Trim(key, "0") != "" {
+				blame.Commit = key
+			}
+		case key == "author":
+			blame.Author = value
+		case key == "author-mail":
+			blame.Email = strings.Trim(value, "<>")
+		case key == "author-time":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return BlameLine{}, fmt.Errorf("git blame %v:%v: invalid author-time %q", file, line, value)
+			}
+
+			blame.Time = time.Unix(seconds, 0).UTC()
+		}
+	}
+
+	return blame, nil
+}
+
+// Export writes the tree of the revision rev of the repository containing dir to dest,
+// it returns the directory in dest matching dir
+func Export(dir string, rev string, dest string) (string, error) {
+	topLevel, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	prefix, err := run(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", "archive", "--format=tar", rev)
+	cmd.Dir = strings.TrimSpace(topLevel)
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	extractErr := extractTar(stdout, dest)
+
+	// drain the output so that git does not block when the extraction failed
+	io.Copy(io.Discard, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		return "", fmt.Errorf("git archive %v: %v %v", rev, err, strings.TrimSpace(stderr.String()))
+	}
+
+	if extractErr != nil {
+		return "", extractErr
+	}
+
+	return filepath.Join(dest, filepath.FromSlash(strings.TrimSpace(prefix))), nil
+}
+
+// extractTar writes the directories and regular files of a tar archive to dest, links are skipped
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(path.Clean(header.Name))
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path in archive: %v", header.Name)
+		}
+
+		target := filepath.Join(dest, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// repository creates a git repository with a commit of main.go on the main branch
+func repository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "uncle-bob")
+	t.Setenv("GIT_AUTHOR_EMAIL", "uncle-bob@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "uncle-bob")
+	t.Setenv("GIT_COMMITTER_EMAIL", "uncle-bob@example.com")
+
+	dir := t.TempDir()
+
+	git(t, dir, "init", "-q", "-b", "main")
+	commitFile(t, dir, "main.go")
+
+	return dir
+}
+
+// git runs git in dir and fails the test on errors
+func git(t *testing.T, dir string, args ...string) {
+	if _, err := run(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFile writes and commits a file in dir
+func commitFile(t *testing.T, dir string, name string) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("package "+filepath.Base(filepath.Dir(path))+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	git(t, dir, "add", name)
+	git(t, dir, "commit", "-q", "-m", "add "+name)
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := repository(t)
+
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "user/user.go")
+
+	// main moves on after the branch left it, its changes are not changes of the branch
+	git(t, dir, "checkout", "-q", "main")
+	commitFile(t, dir, "billing/billing.go")
+	git(t, dir, "checkout", "-q", "feature")
+
+	if err := os.WriteFile(filepath.Join(dir, "user", "repo.go"), []byte("package user\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ChangedFiles(dir, "main")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+
+	sort.Strings(files)
+
+	if want := []string{"user/repo.go", "user/user.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ChangedFiles() = %q, want %q", files, want)
+	}
+
+	files, err = ChangedFiles(filepath.Join(dir, "user"), "main")
+	if err != nil {
+		t.Fatalf("ChangedFiles() of a subdirectory error = %v", err)
+	}
+
+	sort.Strings(files)
+
+	if want := []string{"repo.go", "user.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ChangedFiles() of a subdirectory = %q, want %q", files, want)
+	}
+}
+
+func TestBlame(t *testing.T) {
+	dir := repository(t)
+
+	committed, err := Blame(dir, "main.go", 1)
+	if err != nil {
+		t.Fatalf("Blame() error = %v", err)
+	}
+
+	head, err := run(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if committed.Commit != strings.TrimSpace(head) || committed.Author != "uncle-bob" || committed.Email != "uncle-bob@example.com" || committed.Time.IsZero() {
+		t.Errorf("Blame() of a committed line = %+v, want the commit %v of uncle-bob", committed, head)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport _ \"fmt\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	uncommitted, err := Blame(dir, "main.go", 3)
+	if err != nil || uncommitted.Commit != "" {
+		t.Errorf("Blame() of an uncommitted line = %+v, %v, want no commit", uncommitted, err)
+	}
+
+	if _, err := Blame(dir, "main.go", 10); err == nil {
+		t.Error("Blame() of a missing line returned no error")
+	}
+}
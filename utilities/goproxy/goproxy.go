@@ -0,0 +1,194 @@
+// Package goproxy downloads modules from the module proxies of GOPROXY, without the go command
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+)
+
+// DefaultProxies is the GOPROXY used when the variable is not set, as by the go command
+const DefaultProxies = "https://proxy.golang.org,direct"
+
+// errNotFound tells that a proxy does not have the module, the next proxy is tried
+var errNotFound = errors.New("not found")
+
+// Proxy is a module proxy of GOPROXY
+type Proxy struct {
+	URL string
+	// FallBack tells that the next proxy is tried after any error of this one, the proxy is followed by "|" in
+	// GOPROXY. After "," the next proxy is only tried when this one does not have the module.
+	FallBack bool
+}
+
+// Proxies returns the module proxies of the GOPROXY environment variable, the direct entries are left out and
+// the entries after off are not reached, as by the go command
+func Proxies() []Proxy {
+	value := os.Getenv("GOPROXY")
+	if value == "" {
+		value = DefaultProxies
+	}
+
+	var proxies []Proxy
+
+	for value != "" {
+		entry, separator := value, byte(0)
+		value = ""
+
+		if i := strings.IndexAny(entry, ",|"); i >= 0 {
+			entry, separator, value = entry[:i], entry[i], entry[i+1:]
+		}
+
+		switch entry = strings.TrimSpace(entry); entry {
+		case "off":
+			return proxies
+		case "", "direct":
+			continue
+		}
+
+		proxies = append(proxies, Proxy{URL: strings.TrimSuffix(entry, "/"), FallBack: separator == '|'})
+	}
+
+	return proxies
+}
+
+// Download downloads the module modulePath at version, a version, a query such as a branch name, or latest when
+// empty, from the first proxy of GOPROXY having it, and extracts it to dest, which must not exist. It returns
+// the resolved version.
+func Download(ctx context.Context, modulePath string, version string, dest string) (string, error) {
+	if err := module.CheckPath(modulePath); err != nil {
+		return "", err
+	}
+
+	proxies := Proxies()
+	if len(proxies) == 0 {
+		return "", fmt.Errorf("GOPROXY=%v has no module proxy to download %v from", os.Getenv("GOPROXY"), modulePath)
+	}
+
+	var errs []error
+
+	for _, proxy := range proxies {
+		resolved, err := download(ctx, proxy.URL, modulePath, version, dest)
+
+		if err == nil {
+			return resolved, nil
+		}
+
+		if !proxy.FallBack && !errors.Is(err, errNotFound) {
+			return "", err
+		}
+
+		errs = append(errs, err)
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// download downloads the module from the proxy URL and extracts it to dest
+func download(ctx context.Context, proxy string, modulePath string, version string, dest string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	infoURL := proxy + "/" + escapedPath + "/@latest"
+
+	if version != "" && version != "latest" {
+		escapedVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return "", err
+		}
+
+		infoURL = proxy + "/" + escapedPath + "/@v/" + escapedVersion + ".info"
+	}
+
+	var info struct {
+		Version string
+	}
+
+	body, err := get(ctx, infoURL)
+	if err != nil {
+		return "", err
+	}
+
+	err = json.NewDecoder(body).Decode(&info)
+	body.Close()
+
+	if err != nil || info.Version == "" {
+		return "", fmt.Errorf("%v: invalid version info", infoURL)
+	}
+
+	escapedVersion, err := module.EscapeVersion(info.Version)
+	if err != nil {
+		return "", err
+	}
+
+	// the zip file is read randomly by the extraction, it is downloaded to a temporary file first
+	zipFile, err := os.CreateTemp("", "uncle-bob-module-*.zip")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(zipFile.Name())
+
+	body, err = get(ctx, proxy+"/"+escapedPath+"/@v/"+escapedVersion+".zip")
+	if err != nil {
+		zipFile.Close()
+		return "", err
+	}
+
+	_, err = io.Copy(zipFile, body)
+	body.Close()
+
+	if closeErr := zipFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := zip.Unzip(dest, module.Version{Path: modulePath, Version: info.Version}, zipFile.Name()); err != nil {
+		return "", err
+	}
+
+	return info.Version, nil
+}
+
+// get requests url, the missing modules of a proxy (404 and 410) are errNotFound
+func get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+
+	// proxies explain the error on the first line of the body
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+
+	line, _, _ := strings.Cut(strings.TrimSpace(string(message)), "\n")
+	err = fmt.Errorf("%v: %v %v", url, resp.Status, line)
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: %v", errNotFound, err)
+	}
+
+	return nil, err
+}
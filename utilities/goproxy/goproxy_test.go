@@ -0,0 +1,213 @@
+package goproxy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+)
+
+func TestProxies(t *testing.T) {
+	tests := []struct {
+		goproxy string
+		want    []Proxy
+	}{
+		{"", []Proxy{{URL: "https://proxy.golang.org"}}},
+		{"off", nil},
+		{"direct", nil},
+		{"https://a.example.com/,direct", []Proxy{{URL: "https://a.example.com"}}},
+		{"https://a.example.com|https://b.example.com, off", []Proxy{{URL: "https://a.example.com", FallBack: true}, {URL: "https://b.example.com"}}},
+		{"https://a.example.com,https://b.example.com|https://c.example.com", []Proxy{{URL: "https://a.example.com"}, {URL: "https://b.example.com", FallBack: true}, {URL: "https://c.example.com"}}},
+		{"https://a.example.com,off,https://b.example.com", []Proxy{{URL: "https://a.example.com"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goproxy, func(t *testing.T) {
+			t.Setenv("GOPROXY", tt.goproxy)
+
+			if got := Proxies(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Proxies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// moduleZip returns the zip file of the module m with a go.mod and a Go file
+func moduleZip(t *testing.T, m module.Version) []byte {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+m.Path+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "lib.go"), []byte("package lib\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := zip.CreateFromDir(&buf, m, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// newProxy starts a module proxy serving the module m, the requested paths are appended to requests
+func newProxy(t *testing.T, m module.Version, requests *[]string) *httptest.Server {
+	escapedPath, _ := module.EscapePath(m.Path)
+	moduleZip := moduleZip(t, m)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/" + escapedPath + "/@latest", "/" + escapedPath + "/@v/" + m.Version + ".info", "/" + escapedPath + "/@v/main.info":
+			w.Write([]byte(`{"Version":"` + m.Version + `","Time":"2024-01-02T03:04:05Z"}`))
+		case "/" + escapedPath + "/@v/" + m.Version + ".zip":
+			w.Write(moduleZip)
+		default:
+			http.Error(w, "not found: unknown revision\nmore details", http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDownload(t *testing.T) {
+	m := module.Version{Path: "example.com/Lib", Version: "v1.2.0"}
+
+	tests := []struct {
+		version      string
+		wantRequests []string
+	}{
+		{"", []string{"/example.com/!lib/@latest", "/example.com/!lib/@v/v1.2.0.zip"}},
+		{"latest", []string{"/example.com/!lib/@latest", "/example.com/!lib/@v/v1.2.0.zip"}},
+		{"v1.2.0", []string{"/example.com/!lib/@v/v1.2.0.info", "/example.com/!lib/@v/v1.2.0.zip"}},
+		{"main", []string{"/example.com/!lib/@v/main.info", "/example.com/!lib/@v/v1.2.0.zip"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			var requests []string
+
+			server := newProxy(t, m, &requests)
+			t.Setenv("GOPROXY", server.URL)
+
+			dest := filepath.Join(t.TempDir(), "module")
+
+			version, err := Download(context.Background(), m.Path, tt.version, dest)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+
+			if version != m.Version {
+				t.Errorf("Download() = %v, want %v", version, m.Version)
+			}
+
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+
+			if _, err := os.Stat(filepath.Join(dest, "lib.go")); err != nil {
+				t.Errorf("the module is not extracted: %v", err)
+			}
+		})
+	}
+}
+
+func TestDownload_fallthrough(t *testing.T) {
+	m := module.Version{Path: "example.com/lib", Version: "v1.2.0"}
+
+	status := func(code int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(code), code)
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	var requests []string
+
+	missing, gone, failing := status(http.StatusNotFound), status(http.StatusGone), status(http.StatusInternalServerError)
+	proxy := newProxy(t, m, &requests)
+
+	t.Run("404 and 410", func(t *testing.T) {
+		t.Setenv("GOPROXY", missing.URL+","+gone.URL+"|"+proxy.URL)
+
+		version, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+		if err != nil || version != m.Version {
+			t.Errorf("Download() = %v, %v, want %v", version, err, m.Version)
+		}
+	})
+
+	t.Run("not found anywhere", func(t *testing.T) {
+		t.Setenv("GOPROXY", missing.URL+","+gone.URL)
+
+		_, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+		if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "410") {
+			t.Errorf("Download() error = %v, want the errors of both proxies", err)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		requests = nil
+		t.Setenv("GOPROXY", failing.URL+","+proxy.URL)
+
+		_, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+		if err == nil || len(requests) > 0 {
+			t.Errorf("Download() error = %v after %v requests to the next proxy, want the error of the first proxy", err, len(requests))
+		}
+	})
+
+	t.Run("server error before |", func(t *testing.T) {
+		requests = nil
+		t.Setenv("GOPROXY", failing.URL+"|"+proxy.URL)
+
+		version, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+		if err != nil || version != m.Version || len(requests) == 0 {
+			t.Errorf("Download() = %v, %v, want %v from the next proxy", version, err, m.Version)
+		}
+	})
+
+	t.Run("server error after |", func(t *testing.T) {
+		requests = nil
+		t.Setenv("GOPROXY", missing.URL+"|"+failing.URL+","+proxy.URL)
+
+		_, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+		if err == nil || len(requests) > 0 {
+			t.Errorf("Download() error = %v after %v requests to the last proxy, want the error of the second proxy", err, len(requests))
+		}
+	})
+
+	t.Run("first line of the error", func(t *testing.T) {
+		t.Setenv("GOPROXY", proxy.URL)
+
+		_, err := Download(context.Background(), m.Path, "v9.9.9", filepath.Join(t.TempDir(), "module"))
+		if err == nil || !strings.Contains(err.Error(), "unknown revision") || strings.Contains(err.Error(), "more details") {
+			t.Errorf("Download() error = %v, want the first line of the proxy message", err)
+		}
+	})
+
+	for _, goproxy := range []string{"off", "direct"} {
+		t.Run(goproxy, func(t *testing.T) {
+			t.Setenv("GOPROXY", goproxy)
+
+			_, err := Download(context.Background(), m.Path, "", filepath.Join(t.TempDir(), "module"))
+			if err == nil || !strings.Contains(err.Error(), "no module proxy") {
+				t.Errorf("Download() error = %v, want no module proxy", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package visualizer
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+const (
+	badgeLabel     = "architecture"
+	badgeCharWidth = 7
+	badgePadding   = 10
+	badgeClean     = "#4c1"
+	badgeViolation = "#e05d44"
+)
+
+// GenerateBadge renders a shields style badge with the number of unsuppressed violations of the report
+func GenerateBadge(ctx context.Context, w io.Writer, r report.Report) error {
+	count := 0
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed {
+			count++
+		}
+	}
+
+	message, color := "clean", badgeClean
+
+	switch {
+	case count == 1:
+		message, color = "1 violation", badgeViolation
+	case count > 1:
+		message, color = fmt.Sprintf("%v violations", count), badgeViolation
+	}
+
+	labelWidth := len(badgeLabel)*badgeCharWidth + badgePadding
+	messageWidth := len(message)*badgeCharWidth + badgePadding
+	width := labelWidth + messageWidth
+
+	out := &errWriter{ctx: ctx, w: w}
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="20" role="img" aria-label="%v: %v">`+"\n", width, badgeLabel, html.EscapeString(message))
+	out.printf(`<title>%v: %v</title>`+"\n", badgeLabel, html.EscapeString(message))
+	out.printf(`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	out.printf(`<clipPath id="r"><rect width="%v" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	out.printf(`<g clip-path="url(#r)"><rect width="%v" height="20" fill="#555"/><rect x="%v" width="%v" height="20" fill="%v"/><rect width="%v" height="20" fill="url(#s)"/></g>`+"\n",
+		labelWidth, labelWidth, messageWidth, color, width)
+	out.printf(`<g fill="#fff" text-anchor="middle" font-family="Verdana, Geneva, DejaVu Sans, sans-serif" font-size="11">` + "\n")
+	out.printf(`<text x="%v" y="15" fill="#010101" fill-opacity=".3">%v</text><text x="%v" y="14">%v</text>`+"\n",
+		labelWidth/2, badgeLabel, labelWidth/2, badgeLabel)
+	out.printf(`<text x="%v" y="15" fill="#010101" fill-opacity=".3">%v</text><text x="%v" y="14">%v</text>`+"\n",
+		labelWidth+messageWidth/2, html.EscapeString(message), labelWidth+messageWidth/2, html.EscapeString(message))
+	out.printf("</g>\n</svg>\n")
+
+	return out.err
+}
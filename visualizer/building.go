@@ -0,0 +1,230 @@
+package visualizer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// Dimensions of the building data, in arbitrary units
+const (
+	buildingCell       = 10
+	buildingFootprint  = 6
+	buildingMaxHeight  = 10
+	buildingFloorSpace = 12
+)
+
+// BuildingData is the package graph as a building for 3D renderers: every level, or every group of layers mapped
+// by the config, is a floor and every package a block on its floor. Packages are placed on a grid per floor centered at the origin, in the order of the layered
+// layout, so that packages importing each other stay close.
+type BuildingData struct {
+	Module      string       `json:"module"`
+	Floors      []Floor      `json:"floors"`
+	Blocks      []Block      `json:"blocks"`
+	Connections []Connection `json:"connections"`
+}
+
+// Floor is a level of the graph at its elevation. Level is -1 for the packages without a level and for the floors
+// mapped from Layers by the config.
+type Floor struct {
+	Floor     int      `json:"floor"`
+	Level     int      `json:"level"`
+	Layer     string   `json:"layer,omitempty"`
+	Layers    []string `json:"layers,omitempty"`
+	Label     string   `json:"label"`
+	Elevation float64  `json:"elevation"`
+}
+
+// Block is a package on its floor. X and Z are the center of the block, Height grows with the lines of the
+// package and Intensity, from 0 to 1, with its fan-in.
+type Block struct {
+	Package    string  `json:"package"`
+	Label      string  `json:"label"`
+	Floor      int     `json:"floor"`
+	X          float64 `json:"x"`
+	Z          float64 `json:"z"`
+	Width      float64 `json:"width"`
+	Depth      float64 `json:"depth"`
+	Height     float64 `json:"height"`
+	Intensity  float64 `json:"intensity"`
+	Lines      int     `json:"lines"`
+	Files      int     `json:"files"`
+	FanIn      int     `json:"fanIn"`
+	FanOut     int     `json:"fanOut"`
+	Violations int     `json:"violations"`
+	External   bool    `json:"external,omitempty"`
+}
+
+// Connection is an import between two blocks
+type Connection struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Weight    int    `json:"weight"`
+	Violation bool   `json:"violation"`
+}
+
+// BuildingOptions are the options of the building data
+type BuildingOptions struct {
+	// Floors maps the layers to the floors from the ground floor up, a floor per level when empty. The packages
+	// of the other layers are placed on an additional top floor.
+	Floors []checker.Floor
+}
+
+// GenerateBuildingData writes the building data of the report as JSON, to feed custom renderers
+// (Unity, Blender, WebGL)
+func GenerateBuildingData(ctx context.Context, w io.Writer, r report.Report) error {
+	return GenerateBuildingDataWithOptions(ctx, w, r, BuildingOptions{})
+}
+
+// GenerateBuildingDataWithOptions writes the building data like GenerateBuildingData, with the given options
+func GenerateBuildingDataWithOptions(ctx context.Context, w io.Writer, r report.Report, opts BuildingOptions) error {
+	data := prepareVisualizationData(ctx, r, opts.Floors)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(data)
+}
+
+// prepareVisualizationData places the packages of the report on the floors of the building, a floor per level
+// or the floors mapped from the layers
+func prepareVisualizationData(ctx context.Context, r report.Report, floorLayers []checker.Floor) BuildingData {
+	layout := layoutGraph(ctx, r)
+
+	data := BuildingData{
+		Module:      r.Module,
+		Floors:      make([]Floor, 0, len(layout.rows)),
+		Blocks:      make([]Block, 0, len(r.Packages)),
+		Connections: make([]Connection, 0, len(r.Edges)),
+	}
+
+	packages := make(map[string]report.Package, len(r.Packages))
+	maxLines, maxFanIn := 0, 0
+
+	for _, pkg := range r.Packages {
+		packages[pkg.Path] = pkg
+		maxLines = max(maxLines, pkg.Lines)
+		maxFanIn = max(maxFanIn, pkg.FanIn)
+	}
+
+	violations := make(map[string]int)
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed {
+			violations[violation.From]++
+		}
+	}
+
+	var rows [][]*svgNode
+
+	if len(floorLayers) == 0 {
+		// the rows of the layout are the levels, followed by the packages without a level
+		for i, row := range layout.rows {
+			floor := Floor{Floor: i, Level: -1, Label: layout.rowLabels[i]}
+
+			if i < len(r.Levels) {
+				floor.Level, floor.Layer = r.Levels[i].Level, r.Levels[i].Layer
+			}
+
+			data.Floors = append(data.Floors, floor)
+			rows = append(rows, row)
+		}
+	} else {
+		data.Floors, rows = mapFloors(layout, packages, floorLayers)
+	}
+
+	for i, row := range rows {
+		data.Floors[i].Elevation = float64(i * buildingFloorSpace)
+
+		columns := int(math.Ceil(math.Sqrt(float64(len(row)))))
+
+		for j, node := range row {
+			pkg := packages[node.path]
+
+			block := Block{
+				Package:    node.path,
+				Label:      node.label,
+				Floor:      i,
+				X:          float64(j%columns*buildingCell) - float64((columns-1)*buildingCell)/2,
+				Z:          float64(j/columns*buildingCell) - float64((len(row)-1)/columns*buildingCell)/2,
+				Width:      buildingFootprint,
+				Depth:      buildingFootprint,
+				Height:     1,
+				Lines:      pkg.Lines,
+				Files:      len(pkg.Files),
+				FanIn:      pkg.FanIn,
+				FanOut:     pkg.FanOut,
+				Violations: violations[node.path],
+				External:   pkg.External,
+			}
+
+			if maxLines > 0 {
+				block.Height += float64(buildingMaxHeight-1) * float64(pkg.Lines) / float64(maxLines)
+			}
+
+			if maxFanIn > 0 {
+				block.Intensity = float64(pkg.FanIn) / float64(maxFanIn)
+			}
+
+			block.Height = math.Round(block.Height*100) / 100
+			block.Intensity = math.Round(block.Intensity*100) / 100
+
+			data.Blocks = append(data.Blocks, block)
+		}
+	}
+
+	for _, edge := range r.Edges {
+		data.Connections = append(data.Connections, Connection{From: edge.From, To: edge.To, Weight: edge.Weight, Violation: edge.Violation})
+	}
+
+	return data
+}
+
+// mapFloors returns the floors of the layers and their packages, in the order of the layout. The packages of the
+// other layers are placed on an additional top floor.
+func mapFloors(layout svgLayout, packages map[string]report.Package, floorLayers []checker.Floor) ([]Floor, [][]*svgNode) {
+	floors := make([]Floor, 0, len(floorLayers)+1)
+	floorOf := make(map[string]int)
+
+	for i, floor := range floorLayers {
+		label := floor.Label
+		if label == "" {
+			label = strings.Join(floor.Layers, ", ")
+		}
+
+		floors = append(floors, Floor{Floor: i, Level: -1, Layers: floor.Layers, Label: label})
+
+		for _, layer := range floor.Layers {
+			floorOf[layer] = i
+		}
+	}
+
+	rows := make([][]*svgNode, len(floors))
+	var others []*svgNode
+
+	for _, row := range layout.rows {
+		for _, node := range row {
+			if i, ok := floorOf[packages[node.path].Layer]; ok {
+				rows[i] = append(rows[i], node)
+			} else {
+				others = append(others, node)
+			}
+		}
+	}
+
+	if len(others) > 0 {
+		floors = append(floors, Floor{Floor: len(floors), Level: -1, Label: "Other packages"})
+		rows = append(rows, others)
+	}
+
+	return floors, rows
+}
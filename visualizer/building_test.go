@@ -0,0 +1,92 @@
+package visualizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func Test_prepareVisualizationData(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar", Lines: 50},
+			{Path: "github.com/foo/bar/a", Level: 1, Lines: 100, FanIn: 1},
+			{Path: "github.com/foo/bar/b", Level: 1, Lines: 10, FanIn: 1},
+			{Path: "github.com/foo/bar/c", Level: 1, Lines: 10, FanIn: 2},
+			{Path: "github.com/foo/bar/d", Level: 1, Lines: 10},
+		},
+		Levels: []report.Level{
+			{Level: 0, Packages: []string{"github.com/foo/bar"}},
+			{Level: 1, Packages: []string{"github.com/foo/bar/a", "github.com/foo/bar/b", "github.com/foo/bar/c", "github.com/foo/bar/d"}},
+		},
+	}
+
+	data := prepareVisualizationData(context.Background(), r, nil)
+
+	if len(data.Floors) != 2 || data.Floors[1].Level != 1 || data.Floors[1].Elevation != buildingFloorSpace {
+		t.Fatalf("prepareVisualizationData() floors = %+v, want the two levels", data.Floors)
+	}
+
+	blocks := make(map[string]Block)
+	sumX, sumZ := 0.0, 0.0
+
+	for _, block := range data.Blocks {
+		blocks[block.Package] = block
+
+		if block.Floor == 1 {
+			sumX += block.X
+			sumZ += block.Z
+		}
+	}
+
+	if sumX != 0 || sumZ != 0 {
+		t.Errorf("the blocks of a floor are not centered at the origin: %v, %v", sumX, sumZ)
+	}
+
+	if a := blocks["github.com/foo/bar/a"]; a.Height != buildingMaxHeight || a.Intensity != 0.5 {
+		t.Errorf("the largest package has a height of %v and an intensity of %v, want %v and 0.5", a.Height, a.Intensity, buildingMaxHeight)
+	}
+
+	if c := blocks["github.com/foo/bar/c"]; c.Intensity != 1 {
+		t.Errorf("the most imported package has an intensity of %v, want 1", c.Intensity)
+	}
+}
+
+func Test_prepareVisualizationData_floors(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar/cmd", Layer: "cmd"},
+			{Path: "github.com/foo/bar/app", Level: 1, Layer: "app"},
+			{Path: "github.com/foo/bar/domain", Level: 2, Layer: "domain"},
+		},
+		Levels: []report.Level{
+			{Level: 0, Layer: "cmd", Packages: []string{"github.com/foo/bar/cmd"}},
+			{Level: 1, Layer: "app", Packages: []string{"github.com/foo/bar/app"}},
+			{Level: 2, Layer: "domain", Packages: []string{"github.com/foo/bar/domain"}},
+		},
+	}
+
+	floors := []checker.Floor{{Label: "Ground floor", Layers: []string{"domain", "app"}}}
+
+	data := prepareVisualizationData(context.Background(), r, floors)
+
+	if len(data.Floors) != 2 || data.Floors[0].Label != "Ground floor" || data.Floors[1].Label != "Other packages" {
+		t.Fatalf("prepareVisualizationData() floors = %+v, want the mapped floor and the other packages", data.Floors)
+	}
+
+	want := map[string]int{"github.com/foo/bar/domain": 0, "github.com/foo/bar/app": 0, "github.com/foo/bar/cmd": 1}
+
+	for _, block := range data.Blocks {
+		if block.Floor != want[block.Package] {
+			t.Errorf("%v is on floor %v, want %v", block.Package, block.Floor, want[block.Package])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package visualizer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// GenerateD2Graph writes the package graph in the D2 language, with a container per level
+// and violations styled in red
+func GenerateD2Graph(ctx context.Context, w io.Writer, r report.Report) error {
+	out := &errWriter{ctx: ctx, w: w}
+
+	// D2 keys are generated, the import paths are used as labels. keys holds the full key of every
+	// package including its level container.
+	keys := make(map[string]string)
+
+	out.printf("direction: down\n\n")
+
+	for _, level := range r.Levels {
+		container := fmt.Sprintf("level_%v", level.Level)
+
+		out.printf("%v: %v {\n", container, strconv.Quote(levelLabel(level)))
+
+		for _, pkg := range level.Packages {
+			key := fmt.Sprintf("p%v", len(keys))
+			keys[pkg] = container + "." + key
+
+			out.printf("  %v: %v\n", key, strconv.Quote(nodeLabel(r.Module, pkg)))
+		}
+
+		out.printf("}\n\n")
+	}
+
+	for _, pkg := range r.Packages {
+		if _, ok := keys[pkg.Path]; ok {
+			continue
+		}
+
+		keys[pkg.Path] = fmt.Sprintf("p%v", len(keys))
+
+		out.printf("%v: %v\n", keys[pkg.Path], strconv.Quote(nodeLabel(r.Module, pkg.Path)))
+	}
+
+	for _, edge := range r.Edges {
+		from, to := keys[edge.From], keys[edge.To]
+
+		if from == "" || to == "" {
+			continue
+		}
+
+		if !edge.Violation {
+			out.printf("%v -> %v\n", from, to)
+			continue
+		}
+
+		out.printf("%v -> %v: violation {\n", from, to)
+		out.printf("  style.stroke: red\n")
+		out.printf("  style.stroke-width: 3\n")
+		out.printf("  style.font-color: red\n")
+		out.printf("}\n")
+	}
+
+	return out.err
+}
@@ -0,0 +1,145 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"html"
+	"html/template"
+	"io"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+var (
+	//go:embed assets/diff.html
+	diffReportSource string
+
+	diffReportTemplate = template.Must(template.New("diff").Parse(diffReportSource))
+)
+
+// GenerateDiffReport writes a self-contained HTML report of the changes between two reports: the packages and
+// imports added by the new report are drawn in green, those it removed in dashed grey and the imports with new
+// violations in red
+func GenerateDiffReport(ctx context.Context, w io.Writer, oldReport report.Report, newReport report.Report, c report.Comparison) error {
+	// the new report comes first so that its levels give the rows of the layout
+	layout := layoutGraph(ctx, report.Union([]report.Report{newReport, oldReport}, []string{c.New, c.Old}))
+
+	var graph bytes.Buffer
+
+	if err := drawDiff(ctx, &graph, layout, newReport, c); err != nil {
+		return err
+	}
+
+	return diffReportTemplate.Execute(w, struct {
+		Comparison report.Comparison
+		Module     string
+		Style      template.CSS
+		Graph      template.HTML
+	}{
+		Comparison: c,
+		Module:     newReport.Module,
+		Style:      template.CSS(htmlReportStyle),
+		Graph:      template.HTML(graph.String()),
+	})
+}
+
+// drawDiff draws the packages and imports of the new report and the removed ones at their place in the layout
+func drawDiff(ctx context.Context, w io.Writer, layout svgLayout, newReport report.Report, c report.Comparison) error {
+	out := &errWriter{ctx: ctx, w: w}
+
+	added := make(map[string]bool)
+	for _, pkg := range c.AddedPackages {
+		added[pkg] = true
+	}
+
+	removed := make(map[string]bool)
+	for _, pkg := range c.RemovedPackages {
+		removed[pkg] = true
+	}
+
+	addedEdges := make(map[[2]string]bool)
+	for _, edge := range c.AddedDependencies {
+		addedEdges[[2]string{edge.From, edge.To}] = true
+	}
+
+	violating := make(map[[2]string]bool)
+	for _, violation := range c.NewViolations {
+		if !violation.Suppressed {
+			violating[[2]string{violation.From, violation.To}] = true
+		}
+	}
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", layout.width, layout.height, layout.width, layout.height)
+	out.printf(`<defs>
+<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/></marker>
+<marker id="arrow-violation" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#d62728"/></marker>
+<marker id="arrow-added" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#31a354"/></marker>
+<marker id="arrow-removed" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#bbb"/></marker>
+</defs>
+`)
+
+	for i, label := range layout.rowLabels {
+		y := svgMarginTop + i*svgRowGap
+
+		out.printf(`<rect x="5" y="%v" width="%v" height="%v" fill="#f4f6f8" rx="6"/>`+"\n", y-12, layout.width-10, svgNodeHeight+24)
+		out.printf(`<text x="15" y="%v" fill="#666" font-weight="bold">%v</text>`+"\n", y+svgNodeHeight/2+4, html.EscapeString(label))
+	}
+
+	edge := func(e report.Edge, stroke string, marker string, dash string, title string) {
+		from, to := layout.nodes[e.From], layout.nodes[e.To]
+
+		if from == nil || to == nil {
+			return
+		}
+
+		out.printf(`<path d="%v" fill="none" stroke="%v" stroke-width="%v"%v marker-end="url(#%v)"><title>%v</title></path>`+"\n",
+			edgePath(from, to), stroke, edgeWidth(report.Edge{Weight: e.Weight}), dash, marker, html.EscapeString(title))
+	}
+
+	for _, e := range c.RemovedDependencies {
+		edge(e, "#bbb", "arrow-removed", ` stroke-dasharray="6,4"`, e.From+" no longer imports "+e.To)
+	}
+
+	// the changes are drawn last to stay on top
+	for _, changed := range []bool{false, true} {
+		for _, e := range newReport.Edges {
+			key := [2]string{e.From, e.To}
+
+			if (addedEdges[key] || violating[key]) != changed {
+				continue
+			}
+
+			switch {
+			case violating[key]:
+				edge(e, "#d62728", "arrow-violation", "", edgeTitle(e)+", new violation")
+			case addedEdges[key]:
+				edge(e, "#31a354", "arrow-added", "", edgeTitle(e)+", added")
+			default:
+				edge(e, "#999", "arrow", "", edgeTitle(e))
+			}
+		}
+	}
+
+	for _, row := range layout.rows {
+		for _, node := range row {
+			fill, stroke, dash, state := "#ffffff", "#4a6fa5", "", ""
+
+			switch {
+			case added[node.path]:
+				fill, stroke, state = "#e5f5e0", "#31a354", ", added"
+			case removed[node.path]:
+				fill, stroke, dash, state = "#f4f4f4", "#bbb", ` stroke-dasharray="5,3"`, ", removed"
+			}
+
+			out.printf(`<g><title>%v</title><rect x="%v" y="%v" width="%v" height="%v" rx="8" fill="%v" stroke="%v" stroke-width="1.5"%v/>`,
+				html.EscapeString(node.path+state), node.x, node.y, node.width, svgNodeHeight, fill, stroke, dash)
+			out.printf(`<text x="%v" y="%v" text-anchor="middle" fill="#222">%v</text></g>`+"\n",
+				node.x+node.width/2, node.y+svgNodeHeight/2+4, html.EscapeString(node.label))
+		}
+	}
+
+	out.printf("</svg>\n")
+
+	return out.err
+}
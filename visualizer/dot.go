@@ -0,0 +1,181 @@
+package visualizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// DotOptions are the options of the DOT graph
+type DotOptions struct {
+	// ClusterDirectories groups the packages of every level into a cluster per top-level directory of the module
+	ClusterDirectories bool
+}
+
+// GenerateDotGraph writes the package graph in Graphviz DOT, with a cluster per level and violations in red.
+// Edges imported by several files are labeled with the number of files and drawn thicker. The levels, packages
+// and edges are sorted and the node IDs are hashes of the import paths, so that the graph of an unchanged
+// architecture is the same on every run and committed graphs diff cleanly.
+func GenerateDotGraph(ctx context.Context, w io.Writer, r report.Report) error {
+	return GenerateDotGraphWithOptions(ctx, w, r, DotOptions{})
+}
+
+// GenerateDotGraphWithOptions writes the package graph in Graphviz DOT like GenerateDotGraph, with the given options
+func GenerateDotGraphWithOptions(ctx context.Context, w io.Writer, r report.Report, opts DotOptions) error {
+	out := &errWriter{ctx: ctx, w: w}
+
+	out.printf("digraph %v {\n", strconv.Quote(r.Module))
+	out.printf("  rankdir=TB;\n")
+	out.printf("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+
+	inLevel := make(map[string]bool)
+	vendored := vendoredPackages(r)
+
+	nodeAttributes := func(pkg string) string {
+		if vendored[pkg] {
+			return fmt.Sprintf("label=%v, tooltip=%v, style=\"rounded,dashed\"", strconv.Quote(nodeLabel(r.Module, pkg)+"\n(vendored)"), strconv.Quote(pkg))
+		}
+
+		return fmt.Sprintf("label=%v, tooltip=%v", strconv.Quote(nodeLabel(r.Module, pkg)), strconv.Quote(pkg))
+	}
+
+	// nodes writes the packages, in a cluster per top-level directory with ClusterDirectories
+	nodes := func(indent string, cluster string, packages []string) {
+		if !opts.ClusterDirectories {
+			for _, pkg := range packages {
+				out.printf("%v%v [%v];\n", indent, dotID("pkg_", pkg), nodeAttributes(pkg))
+			}
+
+			return
+		}
+
+		directories, byDirectory := groupByDirectory(r.Module, packages)
+
+		for _, dir := range directories {
+			if dir == "" {
+				for _, pkg := range byDirectory[dir] {
+					out.printf("%v%v [%v];\n", indent, dotID("pkg_", pkg), nodeAttributes(pkg))
+				}
+
+				continue
+			}
+
+			out.printf("%vsubgraph %v {\n", indent, dotID(cluster+"_dir_", dir))
+			out.printf("%v  label=%v;\n", indent, strconv.Quote(dir+"/"))
+			out.printf("%v  style=dotted;\n", indent)
+
+			for _, pkg := range byDirectory[dir] {
+				out.printf("%v  %v [%v];\n", indent, dotID("pkg_", pkg), nodeAttributes(pkg))
+			}
+
+			out.printf("%v}\n", indent)
+		}
+	}
+
+	levels := append([]report.Level(nil), r.Levels...)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Level < levels[j].Level })
+
+	for _, level := range levels {
+		cluster := fmt.Sprintf("cluster_level_%v", level.Level)
+
+		out.printf("  subgraph %v {\n", cluster)
+		out.printf("    label=%v;\n", strconv.Quote(levelLabel(level)))
+		out.printf("    style=dashed;\n")
+
+		for _, pkg := range level.Packages {
+			inLevel[pkg] = true
+		}
+
+		nodes("    ", cluster, sortedStrings(level.Packages))
+
+		out.printf("  }\n")
+	}
+
+	var unleveled []string
+
+	for _, pkg := range r.Packages {
+		if !inLevel[pkg.Path] {
+			unleveled = append(unleveled, pkg.Path)
+		}
+	}
+
+	nodes("  ", "cluster", sortedStrings(unleveled))
+
+	edges := append([]report.Edge(nil), r.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+
+		return edges[i].To < edges[j].To
+	})
+
+	for _, edge := range edges {
+		var attributes []string
+
+		if edge.Violation {
+			attributes = append(attributes, "color=red")
+		}
+
+		if width := edgeWidth(edge); width != 1 {
+			attributes = append(attributes, fmt.Sprintf("penwidth=%v", width))
+		}
+
+		if edge.Weight > 1 {
+			attributes = append(attributes, fmt.Sprintf("label=%v", edge.Weight))
+		}
+
+		if len(attributes) > 0 {
+			out.printf("  %v -> %v [%v];\n", dotID("pkg_", edge.From), dotID("pkg_", edge.To), strings.Join(attributes, ", "))
+		} else {
+			out.printf("  %v -> %v;\n", dotID("pkg_", edge.From), dotID("pkg_", edge.To))
+		}
+	}
+
+	out.printf("}\n")
+
+	return out.err
+}
+
+// dotID returns a DOT ID made of the prefix and a hash of the name, it does not depend on the other names of the
+// graph and stays the same between runs
+func dotID(prefix string, name string) string {
+	sum := sha256.Sum256([]byte(name))
+
+	return prefix + hex.EncodeToString(sum[:6])
+}
+
+// sortedStrings returns a sorted copy of s
+func sortedStrings(s []string) []string {
+	sorted := append([]string(nil), s...)
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// errWriter keeps the first write error so that generators can write without checking every call,
+// writing stops with the error of ctx when ctx is done
+type errWriter struct {
+	ctx context.Context
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		ew.err = ew.ctx.Err()
+	}
+
+	if ew.err != nil {
+		return
+	}
+
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
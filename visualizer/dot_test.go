@@ -0,0 +1,100 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func TestGenerateDotGraphWithOptions_clusterDirectories(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar"},
+			{Path: "github.com/foo/bar/internal/user", Level: 1},
+			{Path: "github.com/foo/bar/internal/billing", Level: 1},
+			{Path: "github.com/foo/bar/pkg/money", Level: 1},
+		},
+		Levels: []report.Level{
+			{Level: 0, Packages: []string{"github.com/foo/bar"}},
+			{Level: 1, Packages: []string{"github.com/foo/bar/internal/user", "github.com/foo/bar/internal/billing", "github.com/foo/bar/pkg/money"}},
+		},
+	}
+
+	var out bytes.Buffer
+
+	if err := GenerateDotGraphWithOptions(context.Background(), &out, r, DotOptions{ClusterDirectories: true}); err != nil {
+		t.Fatalf("GenerateDotGraphWithOptions() error = %v", err)
+	}
+
+	dot := out.String()
+
+	for _, want := range []string{
+		"subgraph " + dotID("cluster_level_1_dir_", "internal") + " {\n      label=\"internal/\";\n      style=dotted;\n      " + dotID("pkg_", "github.com/foo/bar/internal/billing"),
+		"subgraph " + dotID("cluster_level_1_dir_", "pkg") + " {\n      label=\"pkg/\";",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("GenerateDotGraphWithOptions() does not contain %q:\n%v", want, dot)
+		}
+	}
+
+	if strings.Contains(dot, "cluster_level_0_dir") {
+		t.Errorf("GenerateDotGraphWithOptions() clusters the module root:\n%v", dot)
+	}
+}
+
+func TestGenerateDotGraph_deterministic(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar"},
+			{Path: "github.com/foo/bar/user", Level: 1},
+			{Path: "github.com/foo/bar/billing", Level: 1},
+		},
+		Levels: []report.Level{
+			{Level: 1, Packages: []string{"github.com/foo/bar/user", "github.com/foo/bar/billing"}},
+			{Level: 0, Packages: []string{"github.com/foo/bar"}},
+		},
+		Edges: []report.Edge{
+			{From: "github.com/foo/bar/user", To: "github.com/foo/bar/billing"},
+			{From: "github.com/foo/bar", To: "github.com/foo/bar/user"},
+		},
+	}
+
+	shuffled := r
+	shuffled.Levels = []report.Level{
+		{Level: 0, Packages: []string{"github.com/foo/bar"}},
+		{Level: 1, Packages: []string{"github.com/foo/bar/billing", "github.com/foo/bar/user"}},
+	}
+	shuffled.Edges = []report.Edge{r.Edges[1], r.Edges[0]}
+
+	var out, shuffledOut bytes.Buffer
+
+	if err := GenerateDotGraph(context.Background(), &out, r); err != nil {
+		t.Fatalf("GenerateDotGraph() error = %v", err)
+	}
+
+	if err := GenerateDotGraph(context.Background(), &shuffledOut, shuffled); err != nil {
+		t.Fatalf("GenerateDotGraph() error = %v", err)
+	}
+
+	if out.String() != shuffledOut.String() {
+		t.Errorf("GenerateDotGraph() depends on the order of the report:\n%v\n%v", out.String(), shuffledOut.String())
+	}
+
+	if strings.Index(out.String(), "cluster_level_0") > strings.Index(out.String(), "cluster_level_1") {
+		t.Errorf("GenerateDotGraph() does not sort the levels:\n%v", out.String())
+	}
+
+	if r.Levels[0].Level != 1 || r.Edges[0].From != "github.com/foo/bar/user" {
+		t.Errorf("GenerateDotGraph() sorted the report in place")
+	}
+}
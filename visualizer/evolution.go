@@ -0,0 +1,215 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// evolutionFrameSeconds is how long every commit is shown by the animation
+const evolutionFrameSeconds = 2
+
+var (
+	//go:embed assets/evolution.html
+	evolutionSource string
+
+	evolutionTemplate = template.Must(template.New("evolution").Parse(evolutionSource))
+)
+
+// EvolutionFrame is the report of a commit of the history
+type EvolutionFrame struct {
+	Commit  string
+	Time    time.Time
+	Subject string
+	Report  report.Report
+}
+
+// evolutionFrame is a frame of the animation with its counts, added counts are relative to the previous frame
+type evolutionFrame struct {
+	EvolutionFrame
+	Graph         template.HTML
+	Delay         template.CSS
+	Packages      int
+	Imports       int
+	Violations    int
+	AddedPackages int
+	AddedImports  int
+}
+
+// GenerateEvolution writes a self-contained HTML animation of the package graph over the frames, oldest first.
+// Every package keeps its place of the latest frame in which it exists, the packages and imports added by a commit
+// are drawn in green. The animation is plain CSS, it pauses while hovered.
+func GenerateEvolution(ctx context.Context, w io.Writer, frames []EvolutionFrame) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no commits to animate")
+	}
+
+	// the latest frame comes first so that its levels give the rows of the layout
+	reports := make([]report.Report, 0, len(frames))
+	names := make([]string, 0, len(frames))
+
+	for i := len(frames) - 1; i >= 0; i-- {
+		reports = append(reports, frames[i].Report)
+		names = append(names, frames[i].Commit)
+	}
+
+	layout := layoutGraph(ctx, report.Union(reports, names))
+
+	var rendered []evolutionFrame
+	previous := report.Report{}
+
+	for i, frame := range frames {
+		var graph bytes.Buffer
+
+		packages, imports := drawEvolutionFrame(ctx, &graph, layout, frame.Report, previous, i > 0)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// everything is new in the first frame
+		if i == 0 {
+			packages, imports = 0, 0
+		}
+
+		violations := 0
+		for _, violation := range frame.Report.Violations {
+			if !violation.Suppressed {
+				violations++
+			}
+		}
+
+		frame.Subject = shortSubject(frame.Subject)
+
+		rendered = append(rendered, evolutionFrame{
+			EvolutionFrame: frame,
+			Graph:          template.HTML(graph.String()),
+			Delay:          template.CSS(fmt.Sprintf("animation-delay: %vs", i*evolutionFrameSeconds)),
+			Packages:       len(frame.Report.Packages),
+			Imports:        len(frame.Report.Edges),
+			Violations:     violations,
+			AddedPackages:  packages,
+			AddedImports:   imports,
+		})
+
+		previous = frame.Report
+	}
+
+	// every frame is visible for its share of the cycle, then hidden until its next turn
+	animation := fmt.Sprintf(`.evolution .frame { animation: evolution %vs step-end infinite; }
+@keyframes evolution { 0%% { opacity: 1; } %.4f%% { opacity: 0; } 100%% { opacity: 0; } }`,
+		len(frames)*evolutionFrameSeconds, 100/float64(len(frames)))
+
+	return evolutionTemplate.Execute(w, struct {
+		Module    string
+		Style     template.CSS
+		Animation template.CSS
+		Frames    []evolutionFrame
+	}{
+		Module:    frames[len(frames)-1].Report.Module,
+		Style:     template.CSS(htmlReportStyle),
+		Animation: template.CSS(animation),
+		Frames:    rendered,
+	})
+}
+
+// drawEvolutionFrame draws the packages and imports of r at their place in the layout, those missing in previous
+// are drawn in green when highlight is set. It returns the number of added packages and imports.
+func drawEvolutionFrame(ctx context.Context, w io.Writer, layout svgLayout, r report.Report, previous report.Report, highlight bool) (int, int) {
+	out := &errWriter{ctx: ctx, w: w}
+
+	oldPackages := make(map[string]bool)
+	for _, pkg := range previous.Packages {
+		oldPackages[pkg.Path] = true
+	}
+
+	oldEdges := make(map[[2]string]bool)
+	for _, edge := range previous.Edges {
+		oldEdges[[2]string{edge.From, edge.To}] = true
+	}
+
+	addedPackages, addedImports := 0, 0
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", layout.width, layout.height, layout.width, layout.height)
+	out.printf(`<defs>
+<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/></marker>
+<marker id="arrow-violation" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#d62728"/></marker>
+<marker id="arrow-added" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#31a354"/></marker>
+</defs>
+`)
+
+	for i, label := range layout.rowLabels {
+		y := svgMarginTop + i*svgRowGap
+
+		out.printf(`<rect x="5" y="%v" width="%v" height="%v" fill="#f4f6f8" rx="6"/>`+"\n", y-12, layout.width-10, svgNodeHeight+24)
+		out.printf(`<text x="15" y="%v" fill="#666" font-weight="bold">%v</text>`+"\n", y+svgNodeHeight/2+4, html.EscapeString(label))
+	}
+
+	for _, edge := range r.Edges {
+		from, to := layout.nodes[edge.From], layout.nodes[edge.To]
+
+		if from == nil || to == nil {
+			continue
+		}
+
+		added := !oldEdges[[2]string{edge.From, edge.To}]
+		if added {
+			addedImports++
+		}
+
+		stroke, marker := "#999", "arrow"
+
+		switch {
+		case edge.Violation:
+			stroke, marker = "#d62728", "arrow-violation"
+		case added && highlight:
+			stroke, marker = "#31a354", "arrow-added"
+		}
+
+		out.printf(`<path d="%v" fill="none" stroke="%v" stroke-width="%v" marker-end="url(#%v)"><title>%v</title></path>`+"\n",
+			edgePath(from, to), stroke, edgeWidth(edge), marker, html.EscapeString(edgeTitle(edge)))
+	}
+
+	for _, pkg := range r.Packages {
+		node := layout.nodes[pkg.Path]
+
+		if node == nil {
+			continue
+		}
+
+		fill, stroke := "#ffffff", "#4a6fa5"
+
+		if !oldPackages[pkg.Path] {
+			addedPackages++
+
+			if highlight {
+				fill, stroke = "#e5f5e0", "#31a354"
+			}
+		}
+
+		out.printf(`<g><title>%v</title><rect x="%v" y="%v" width="%v" height="%v" rx="8" fill="%v" stroke="%v" stroke-width="1.5"/>`,
+			html.EscapeString(pkg.Path), node.x, node.y, node.width, svgNodeHeight, fill, stroke)
+		out.printf(`<text x="%v" y="%v" text-anchor="middle" fill="#222">%v</text></g>`+"\n",
+			node.x+node.width/2, node.y+svgNodeHeight/2+4, html.EscapeString(node.label))
+	}
+
+	out.printf("</svg>\n")
+
+	return addedPackages, addedImports
+}
+
+// shortSubject cuts a commit subject for the captions
+func shortSubject(subject string) string {
+	if runes := []rune(subject); len(runes) > 72 {
+		return strings.TrimSpace(string(runes[:71])) + "…"
+	}
+
+	return subject
+}
@@ -0,0 +1,181 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// The report assets are embedded and inlined, the generated report does not load anything from the network
+// and renders in air-gapped environments.
+var (
+	//go:embed assets/report.html
+	htmlReportSource string
+
+	//go:embed assets/report.css
+	htmlReportStyle string
+
+	htmlReportTemplate = template.Must(template.New("report").Parse(htmlReportSource))
+)
+
+// Themes of the HTML report
+const (
+	HTMLThemeLight = "light"
+	HTMLThemeDark  = "dark"
+)
+
+// HTMLThemes are the themes of the HTML report
+var HTMLThemes = []string{HTMLThemeLight, HTMLThemeDark}
+
+// htmlColor matches the CSS hex colors accepted as brand color
+var htmlColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// HTMLOptions are the options of the HTML report
+type HTMLOptions struct {
+	// Theme is one of HTMLThemes, HTMLThemeLight when empty
+	Theme string
+	// Logo is the data URI of an image shown next to the title, it keeps the report self-contained
+	Logo string
+	// Color is the brand color of the titles and links, a CSS hex color
+	Color string
+	// SourceURL links the packages and the import lines to their source, {ref}, {path} and {line} are replaced
+	// by Ref, the slash separated path relative to the project root and the line
+	// (ex. https://github.com/org/repo/blob/{ref}/{path}#L{line}). Packages link to their directory, without the
+	// fragment holding {line}.
+	SourceURL string
+	Ref       string
+}
+
+// Validate checks the theme and the brand color
+func (opts HTMLOptions) Validate() error {
+	if opts.Theme != "" && !slices.Contains(HTMLThemes, opts.Theme) {
+		return fmt.Errorf("unknown HTML theme %q, use one of: %v", opts.Theme, strings.Join(HTMLThemes, ", "))
+	}
+
+	if opts.Logo != "" && !strings.HasPrefix(opts.Logo, "data:image/") {
+		return fmt.Errorf("the HTML logo is not the data URI of an image")
+	}
+
+	if opts.Color != "" && !htmlColor.MatchString(opts.Color) {
+		return fmt.Errorf("invalid HTML color %q, use a hex color such as #0b5fff", opts.Color)
+	}
+
+	return nil
+}
+
+// GenerateHTMLReport writes a self-contained HTML report with the package graph rendered as inline SVG,
+// so the report does not need any external resources to be viewed
+func GenerateHTMLReport(ctx context.Context, w io.Writer, r report.Report) error {
+	return GenerateHTMLReportWithOptions(ctx, w, r, HTMLOptions{})
+}
+
+// GenerateHTMLReportWithOptions writes the HTML report like GenerateHTMLReport, with the given options
+func GenerateHTMLReportWithOptions(ctx context.Context, w io.Writer, r report.Report, opts HTMLOptions) error {
+	var graph bytes.Buffer
+
+	if err := GenerateSVG(ctx, &graph, r); err != nil {
+		return err
+	}
+
+	data := struct {
+		Report     report.Report
+		Style      template.CSS
+		Theme      string
+		Logo       template.URL
+		Graph      template.HTML
+		Violations int
+		Suppressed int
+		Summary    report.Summary
+		// FileURL and PackageURL return the source links, empty without HTMLOptions.SourceURL
+		FileURL    func(file string, line int) string
+		PackageURL func(path string) string
+	}{
+		Report:     r,
+		Style:      template.CSS(htmlReportStyle),
+		Theme:      opts.Theme,
+		Graph:      template.HTML(graph.String()),
+		FileURL:    opts.fileURL,
+		PackageURL: func(path string) string { return opts.packageURL(r.Module, path) },
+	}
+
+	if data.Theme == "" {
+		data.Theme = HTMLThemeLight
+	}
+
+	// the logo and the color are only used when valid, they can not inject scripts or break out of the style sheet
+	if strings.HasPrefix(opts.Logo, "data:image/") {
+		data.Logo = template.URL(opts.Logo)
+	}
+
+	if htmlColor.MatchString(opts.Color) {
+		data.Style += template.CSS(fmt.Sprintf("\n:root, .theme-%v { --accent: %v; }\n", data.Theme, opts.Color))
+	}
+
+	// reports of other commands than check are not summarized
+	if r.Summary != nil {
+		data.Summary = *r.Summary
+	} else {
+		data.Summary = report.Summarize(r, report.DefaultTopOffenders)
+	}
+
+	for _, violation := range r.Violations {
+		if violation.Suppressed {
+			data.Suppressed++
+		} else {
+			data.Violations++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return htmlReportTemplate.Execute(w, data)
+}
+
+// fileURL returns the link to a line of a file
+func (opts HTMLOptions) fileURL(file string, line int) string {
+	if opts.SourceURL == "" {
+		return ""
+	}
+
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	link := opts.SourceURL
+
+	// directories have no lines
+	if line == 0 {
+		if i := strings.Index(link, "#"); i >= 0 && strings.Contains(link[i:], "{line}") {
+			link = link[:i]
+		}
+	}
+
+	return strings.NewReplacer("{ref}", opts.Ref, "{path}", strings.Join(segments, "/"), "{line}", strconv.Itoa(line)).Replace(link)
+}
+
+// packageURL returns the link to the directory of a package of the module, empty for other packages
+func (opts HTMLOptions) packageURL(module string, path string) string {
+	if path == module {
+		return opts.fileURL("", 0)
+	}
+
+	dir, ok := strings.CutPrefix(path, module+"/")
+	if !ok {
+		return ""
+	}
+
+	return opts.fileURL(dir, 0)
+}
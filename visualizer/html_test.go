@@ -0,0 +1,129 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func TestGenerateHTMLReport_selfContained(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar", Imports: []string{"github.com/foo/bar/domain"}},
+			{Path: "github.com/foo/bar/domain", Level: 1},
+		},
+		Levels: []report.Level{
+			{Level: 0, Packages: []string{"github.com/foo/bar"}},
+			{Level: 1, Packages: []string{"github.com/foo/bar/domain"}},
+		},
+		Edges: []report.Edge{
+			{From: "github.com/foo/bar", To: "github.com/foo/bar/domain", ToLevel: 1},
+		},
+	}
+
+	var out bytes.Buffer
+
+	if err := GenerateHTMLReport(context.Background(), &out, r); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+
+	html := out.String()
+
+	for _, external := range []string{`src="http`, `href="http`, `@import`, `url(http`} {
+		if strings.Contains(html, external) {
+			t.Errorf("GenerateHTMLReport() references an external resource: %v", external)
+		}
+	}
+
+	for _, inlined := range []string{"<style>", "<svg", "border-collapse"} {
+		if !strings.Contains(html, inlined) {
+			t.Errorf("GenerateHTMLReport() does not inline %v", inlined)
+		}
+	}
+}
+
+func TestGenerateHTMLReport_offenders(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Violations: []checker.Violation{
+			{Rule: checker.RuleSameLevel, From: "github.com/foo/bar/user", To: "github.com/foo/bar/billing"},
+			{Rule: checker.RuleSameLevel, From: "github.com/foo/bar/legacy", To: "github.com/foo/bar/billing", Suppressed: true},
+		},
+	}
+
+	var out bytes.Buffer
+
+	if err := GenerateHTMLReport(context.Background(), &out, r); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+
+	html := out.String()
+
+	for _, want := range []string{"Top offenders", "github.com/foo/bar/user", "github.com/foo/bar/billing"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("GenerateHTMLReport() does not list the offender %v", want)
+		}
+	}
+
+	if strings.Contains(html, "<td>github.com/foo/bar/legacy</td>") {
+		t.Errorf("GenerateHTMLReport() lists a package of a suppressed violation as an offender")
+	}
+}
+
+func TestHTMLOptions_sourceLinks(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	opts := HTMLOptions{SourceURL: "https://github.com/foo/bar/blob/{ref}/{path}#L{line}", Ref: "4f1c2aa"}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"file", opts.fileURL("internal/user/user.go", 12), "https://github.com/foo/bar/blob/4f1c2aa/internal/user/user.go#L12"},
+		{"package", opts.packageURL("github.com/foo/bar", "github.com/foo/bar/internal/user"), "https://github.com/foo/bar/blob/4f1c2aa/internal/user"},
+		{"module root", opts.packageURL("github.com/foo/bar", "github.com/foo/bar"), "https://github.com/foo/bar/blob/4f1c2aa/"},
+		{"external package", opts.packageURL("github.com/foo/bar", "github.com/other/lib"), ""},
+		{"no template", HTMLOptions{}.fileURL("user.go", 1), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("source link = %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    HTMLOptions
+		wantErr bool
+	}{
+		{"default", HTMLOptions{}, false},
+		{"dark with brand", HTMLOptions{Theme: HTMLThemeDark, Color: "#0b5fff", Logo: "data:image/png;base64,iVBORw0K"}, false},
+		{"short color", HTMLOptions{Color: "#fff"}, false},
+		{"unknown theme", HTMLOptions{Theme: "solarized"}, true},
+		{"color breaking out of the style sheet", HTMLOptions{Color: "red; } body { display: none"}, true},
+		{"logo not an image", HTMLOptions{Logo: "javascript:alert(1)"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
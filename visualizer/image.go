@@ -0,0 +1,115 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// Renderers of the svg and png images
+const (
+	// ImageRendererAuto renders with Graphviz dot when it is installed, with the built-in layout otherwise
+	ImageRendererAuto    = "auto"
+	ImageRendererDot     = "dot"
+	ImageRendererBuiltin = "builtin"
+)
+
+// ImageRenderers are the renderers of the svg and png images
+var ImageRenderers = []string{ImageRendererAuto, ImageRendererDot, ImageRendererBuiltin}
+
+// ImageOptions are the options of the svg and png images
+type ImageOptions struct {
+	// Renderer is one of ImageRenderers, ImageRendererAuto when empty
+	Renderer string
+	// Dot are the options of the DOT graph rendered by Graphviz dot
+	Dot DotOptions
+}
+
+// Validate checks the renderer, and that dot is installed when it is required
+func (opts ImageOptions) Validate() error {
+	switch opts.Renderer {
+	case "", ImageRendererAuto, ImageRendererBuiltin:
+		return nil
+	case ImageRendererDot:
+		if !dotInstalled() {
+			return fmt.Errorf("the dot renderer needs Graphviz dot in the PATH, install Graphviz or use the %v renderer", ImageRendererBuiltin)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown image renderer %q, use one of: %v", opts.Renderer, strings.Join(ImageRenderers, ", "))
+}
+
+// useDot tells if the image is rendered with Graphviz dot
+func (opts ImageOptions) useDot() bool {
+	switch opts.Renderer {
+	case ImageRendererDot:
+		return true
+	case ImageRendererBuiltin:
+		return false
+	}
+
+	return dotInstalled()
+}
+
+// GenerateSVGWithOptions renders the package graph as an SVG image with Graphviz dot, or with the built-in layout
+// of GenerateSVG, as selected by the options
+func GenerateSVGWithOptions(ctx context.Context, w io.Writer, r report.Report, opts ImageOptions) error {
+	if opts.useDot() {
+		return renderWithDot(ctx, w, r, "svg", opts.Dot)
+	}
+
+	return GenerateSVG(ctx, w, r)
+}
+
+// GeneratePNG renders the package graph as a PNG image with the built-in layout, without external tools
+func GeneratePNG(ctx context.Context, w io.Writer, r report.Report) error {
+	return GeneratePNGWithOptions(ctx, w, r, ImageOptions{Renderer: ImageRendererBuiltin})
+}
+
+// GeneratePNGWithOptions renders the package graph as a PNG image with Graphviz dot, or with the built-in layout,
+// as selected by the options
+func GeneratePNGWithOptions(ctx context.Context, w io.Writer, r report.Report, opts ImageOptions) error {
+	if opts.useDot() {
+		return renderWithDot(ctx, w, r, "png", opts.Dot)
+	}
+
+	return drawPNG(ctx, w, r)
+}
+
+// dotInstalled tells if Graphviz dot is in the PATH
+func dotInstalled() bool {
+	_, err := exec.LookPath("dot")
+
+	return err == nil
+}
+
+// renderWithDot pipes the DOT graph of the report through Graphviz dot to render it in the given output format
+func renderWithDot(ctx context.Context, w io.Writer, r report.Report, format string, opts DotOptions) error {
+	var graph, stderr bytes.Buffer
+
+	if err := GenerateDotGraphWithOptions(ctx, &graph, r, opts); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "dot", "-T"+format)
+	cmd.Stdin = &graph
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("dot: %v: %v", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package visualizer
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+const (
+	// pngScale is the number of pixels of a unit of the layout, the image is drawn at twice the size of the SVG
+	// to keep the bitmap font readable
+	pngScale = 2
+	// pngCurveSteps is the number of segments an edge curve is drawn with
+	pngCurveSteps = 24
+	// pngArrowSize is the length of the arrow heads, in units of the layout
+	pngArrowSize = 8
+)
+
+var (
+	pngBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	pngRow        = color.RGBA{0xf4, 0xf6, 0xf8, 0xff}
+	pngRowLabel   = color.RGBA{0x66, 0x66, 0x66, 0xff}
+	pngEdge       = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	pngArrow      = color.RGBA{0x55, 0x55, 0x55, 0xff}
+	pngViolation  = color.RGBA{0xd6, 0x27, 0x28, 0xff}
+	pngNodeStroke = color.RGBA{0x4a, 0x6f, 0xa5, 0xff}
+	pngNodeLabel  = color.RGBA{0x22, 0x22, 0x22, 0xff}
+)
+
+// pngCanvas draws with the units of the layout on an image scaled by pngScale
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+// drawPNG draws the layout of GenerateSVG on an image and encodes it as PNG
+func drawPNG(ctx context.Context, w io.Writer, r report.Report) error {
+	layout := layoutGraph(ctx, r)
+	c := pngCanvas{img: image.NewRGBA(image.Rect(0, 0, layout.width*pngScale, layout.height*pngScale))}
+
+	c.fillRect(0, 0, float64(layout.width), float64(layout.height), pngBackground)
+
+	for i, label := range layout.rowLabels {
+		y := svgMarginTop + i*svgRowGap
+
+		c.fillRect(5, float64(y-12), float64(layout.width-10), svgNodeHeight+24, pngRow)
+		c.text(15, float64(y+svgNodeHeight/2), label, pngRowLabel)
+	}
+
+	// violations are drawn last to stay on top
+	for _, violations := range []bool{false, true} {
+		for _, edge := range r.Edges {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if edge.Violation != violations {
+				continue
+			}
+
+			from, to := layout.nodes[edge.From], layout.nodes[edge.To]
+
+			if from == nil || to == nil {
+				continue
+			}
+
+			stroke, arrow := pngEdge, pngArrow
+
+			if edge.Violation {
+				stroke, arrow = pngViolation, pngViolation
+			}
+
+			c.curve(edgeCurve(from, to), edgeWidth(edge), stroke, arrow)
+		}
+	}
+
+	vendored := vendoredPackages(r)
+
+	for _, row := range layout.rows {
+		for _, node := range row {
+			x, y, width := float64(node.x), float64(node.y), float64(node.width)
+
+			c.fillRect(x, y, width, svgNodeHeight, pngBackground)
+			c.strokeRect(x, y, width, svgNodeHeight, 1.5, vendored[node.path], pngNodeStroke)
+			c.text(x+width/2-float64(textWidth(node.label))/2, y+svgNodeHeight/2, node.label, pngNodeLabel)
+		}
+	}
+
+	return png.Encode(w, c.img)
+}
+
+// textWidth returns the width of a text in the bitmap font, in units of the layout
+func textWidth(text string) int {
+	n := len([]rune(text))
+
+	if n == 0 {
+		return 0
+	}
+
+	return n*(fontWidth+1) - 1
+}
+
+// fillRect fills a rectangle
+func (c pngCanvas) fillRect(x, y, width, height float64, col color.RGBA) {
+	x0, y0 := int(x*pngScale), int(y*pngScale)
+	x1, y1 := int((x+width)*pngScale), int((y+height)*pngScale)
+
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			c.set(px, py, col)
+		}
+	}
+}
+
+// strokeRect draws the border of a rectangle, dashed borders are drawn in dashes of 5 and gaps of 3 units
+func (c pngCanvas) strokeRect(x, y, width, height, stroke float64, dashed bool, col color.RGBA) {
+	corners := []svgPoint{{int(x), int(y)}, {int(x + width), int(y)}, {int(x + width), int(y + height)}, {int(x), int(y + height)}}
+
+	for i, from := range corners {
+		to := corners[(i+1)%len(corners)]
+
+		if !dashed {
+			c.line(float64(from.x), float64(from.y), float64(to.x), float64(to.y), stroke, col)
+			continue
+		}
+
+		length := math.Hypot(float64(to.x-from.x), float64(to.y-from.y))
+
+		for start := 0.0; start < length; start += 8 {
+			end := math.Min(start+5, length)
+			dx, dy := float64(to.x-from.x)/length, float64(to.y-from.y)/length
+
+			c.line(float64(from.x)+dx*start, float64(from.y)+dy*start, float64(from.x)+dx*end, float64(from.y)+dy*end, stroke, col)
+		}
+	}
+}
+
+// curve draws a cubic curve ending with an arrow head
+func (c pngCanvas) curve(points [4]svgPoint, stroke float64, col color.RGBA, arrow color.RGBA) {
+	at := func(t float64) (float64, float64) {
+		u := 1 - t
+		x := u*u*u*float64(points[0].x) + 3*u*u*t*float64(points[1].x) + 3*u*t*t*float64(points[2].x) + t*t*t*float64(points[3].x)
+		y := u*u*u*float64(points[0].y) + 3*u*u*t*float64(points[1].y) + 3*u*t*t*float64(points[2].y) + t*t*t*float64(points[3].y)
+
+		return x, y
+	}
+
+	prevX, prevY := at(0)
+
+	for i := 1; i <= pngCurveSteps; i++ {
+		x, y := at(float64(i) / pngCurveSteps)
+		c.line(prevX, prevY, x, y, stroke, col)
+
+		if i < pngCurveSteps {
+			prevX, prevY = x, y
+		}
+	}
+
+	// the arrow head points along the last segment of the curve
+	endX, endY := float64(points[3].x), float64(points[3].y)
+	length := math.Hypot(endX-prevX, endY-prevY)
+
+	if length == 0 {
+		return
+	}
+
+	dx, dy := (endX-prevX)/length, (endY-prevY)/length
+	baseX, baseY := endX-dx*pngArrowSize, endY-dy*pngArrowSize
+	half := pngArrowSize * 0.4
+
+	c.fillTriangle(endX, endY, baseX-dy*half, baseY+dx*half, baseX+dy*half, baseY-dx*half, arrow)
+}
+
+// line draws a line of the given stroke width
+func (c pngCanvas) line(x0, y0, x1, y1, stroke float64, col color.RGBA) {
+	radius := math.Max(stroke*pngScale/2, 0.5)
+	steps := int(math.Hypot(x1-x0, y1-y0)*pngScale) + 1
+
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		cx, cy := (x0+(x1-x0)*t)*pngScale, (y0+(y1-y0)*t)*pngScale
+
+		for py := int(math.Floor(cy - radius)); py <= int(math.Ceil(cy+radius)); py++ {
+			for px := int(math.Floor(cx - radius)); px <= int(math.Ceil(cx+radius)); px++ {
+				if math.Hypot(float64(px)+0.5-cx, float64(py)+0.5-cy) <= radius {
+					c.set(px, py, col)
+				}
+			}
+		}
+	}
+}
+
+// fillTriangle fills the triangle of three points
+func (c pngCanvas) fillTriangle(x0, y0, x1, y1, x2, y2 float64, col color.RGBA) {
+	side := func(ax, ay, bx, by, px, py float64) float64 {
+		return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+	}
+
+	minX, maxX := math.Min(x0, math.Min(x1, x2)), math.Max(x0, math.Max(x1, x2))
+	minY, maxY := math.Min(y0, math.Min(y1, y2)), math.Max(y0, math.Max(y1, y2))
+
+	for py := int(minY * pngScale); py <= int(maxY*pngScale); py++ {
+		for px := int(minX * pngScale); px <= int(maxX*pngScale); px++ {
+			x, y := (float64(px)+0.5)/pngScale, (float64(py)+0.5)/pngScale
+			a, b, d := side(x0, y0, x1, y1, x, y), side(x1, y1, x2, y2, x, y), side(x2, y2, x0, y0, x, y)
+
+			if (a >= 0 && b >= 0 && d >= 0) || (a <= 0 && b <= 0 && d <= 0) {
+				c.set(px, py, col)
+			}
+		}
+	}
+}
+
+// text draws a line of text in the bitmap font, a dot of the font is a unit of the layout. The text starts at
+// x and is vertically centered on y.
+func (c pngCanvas) text(x, y float64, text string, col color.RGBA) {
+	top := y - fontHeight/2.0
+
+	for i, char := range []rune(text) {
+		columns := glyph(char)
+		left := x + float64(i*(fontWidth+1))
+
+		for column, bits := range columns {
+			for row := 0; row < fontHeight; row++ {
+				if bits&(1<<row) != 0 {
+					c.fillRect(left+float64(column), top+float64(row), 1, 1, col)
+				}
+			}
+		}
+	}
+}
+
+// set colors a pixel, pixels outside of the image are left out
+func (c pngCanvas) set(px, py int, col color.RGBA) {
+	if image.Pt(px, py).In(c.img.Rect) {
+		c.img.SetRGBA(px, py, col)
+	}
+}
@@ -0,0 +1,76 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/report"
+)
+
+func TestGeneratePNG(t *testing.T) {
+	checker.ModPath = "github.com/foo/bar"
+
+	r := report.Report{
+		Module: checker.ModPath,
+		Packages: []report.Package{
+			{Path: "github.com/foo/bar"},
+			{Path: "github.com/foo/bar/user", Level: 1},
+			{Path: "github.com/foo/bar/billing", Level: 1},
+		},
+		Levels: []report.Level{
+			{Level: 0, Packages: []string{"github.com/foo/bar"}},
+			{Level: 1, Packages: []string{"github.com/foo/bar/user", "github.com/foo/bar/billing"}},
+		},
+		Edges: []report.Edge{
+			{From: "github.com/foo/bar", To: "github.com/foo/bar/user"},
+			{From: "github.com/foo/bar/user", To: "github.com/foo/bar/billing", Violation: true},
+		},
+	}
+
+	var out bytes.Buffer
+
+	if err := GeneratePNG(context.Background(), &out, r); err != nil {
+		t.Fatalf("GeneratePNG() error = %v", err)
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatalf("GeneratePNG() is not a PNG image: %v", err)
+	}
+
+	layout := layoutGraph(context.Background(), r)
+
+	if size := img.Bounds().Size(); size.X != layout.width*pngScale || size.Y != layout.height*pngScale {
+		t.Errorf("GeneratePNG() size = %v, want %vx%v", size, layout.width*pngScale, layout.height*pngScale)
+	}
+
+	violation := false
+
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y && !violation; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if img.At(x, y) == pngViolation {
+				violation = true
+				break
+			}
+		}
+	}
+
+	if !violation {
+		t.Errorf("GeneratePNG() does not draw the violating import in red")
+	}
+}
+
+func TestImageOptions_Validate(t *testing.T) {
+	for _, renderer := range []string{"", ImageRendererAuto, ImageRendererBuiltin} {
+		if err := (ImageOptions{Renderer: renderer}).Validate(); err != nil {
+			t.Errorf("Validate() of renderer %q error = %v", renderer, err)
+		}
+	}
+
+	if err := (ImageOptions{Renderer: "cairo"}).Validate(); err == nil {
+		t.Errorf("Validate() accepts an unknown renderer")
+	}
+}
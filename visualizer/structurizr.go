@@ -0,0 +1,86 @@
+package visualizer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// GenerateStructurizrDSL writes the package graph as a Structurizr DSL workspace for the C4 model: the module is
+// a software system with a container per level (or layer) and a component per package, violations are tagged
+func GenerateStructurizrDSL(ctx context.Context, w io.Writer, r report.Report) error {
+	out := &errWriter{ctx: ctx, w: w}
+
+	// identifiers are generated, the import paths are used as names
+	ids := make(map[string]string)
+	var containers []string
+
+	out.printf("workspace %v {\n\n", strconv.Quote(r.Module))
+	out.printf("  model {\n")
+	out.printf("    system = softwareSystem %v {\n", strconv.Quote(r.Module))
+
+	addContainer := func(id string, name string, packages []string) {
+		containers = append(containers, id)
+
+		out.printf("      %v = container %v {\n", id, strconv.Quote(name))
+
+		for _, pkg := range packages {
+			ids[pkg] = fmt.Sprintf("p%v", len(ids))
+
+			out.printf("        %v = component %v %v\n", ids[pkg], strconv.Quote(nodeLabel(r.Module, pkg)), strconv.Quote(pkg))
+		}
+
+		out.printf("      }\n")
+	}
+
+	for _, level := range r.Levels {
+		addContainer(fmt.Sprintf("level%v", level.Level), levelLabel(level), level.Packages)
+	}
+
+	var unassigned []string
+
+	for _, pkg := range r.Packages {
+		if _, ok := ids[pkg.Path]; !ok {
+			unassigned = append(unassigned, pkg.Path)
+		}
+	}
+
+	if len(unassigned) > 0 {
+		addContainer("unassigned", "No level", unassigned)
+	}
+
+	out.printf("    }\n\n")
+
+	for _, edge := range r.Edges {
+		from, to := ids[edge.From], ids[edge.To]
+
+		if from == "" || to == "" {
+			continue
+		}
+
+		if edge.Violation {
+			out.printf("    %v -> %v \"imports\" \"\" \"Violation\"\n", from, to)
+		} else {
+			out.printf("    %v -> %v \"imports\"\n", from, to)
+		}
+	}
+
+	out.printf("  }\n\n")
+	out.printf("  views {\n")
+	out.printf("    container system {\n      include *\n      autoLayout tb\n    }\n\n")
+
+	for _, container := range containers {
+		out.printf("    component %v {\n      include *\n      autoLayout tb\n    }\n\n", container)
+	}
+
+	out.printf("    styles {\n")
+	out.printf("      relationship \"Violation\" {\n        color #d62728\n        thickness 3\n      }\n")
+	out.printf("    }\n")
+	out.printf("  }\n")
+	out.printf("}\n")
+
+	return out.err
+}
@@ -0,0 +1,213 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+const (
+	sunburstSize   = 800
+	sunburstCenter = 60
+)
+
+var (
+	//go:embed assets/sunburst.html
+	sunburstSource string
+
+	sunburstTemplate = template.Must(template.New("sunburst").Parse(sunburstSource))
+)
+
+// sunburstNode is a directory of the module, a package when pkg is set
+type sunburstNode struct {
+	name     string
+	path     string
+	pkg      *report.Package
+	children []*sunburstNode
+}
+
+// weight returns the number of packages of the node and its subdirectories
+func (node *sunburstNode) weight() int {
+	weight := 0
+	if node.pkg != nil {
+		weight++
+	}
+
+	for _, child := range node.children {
+		weight += child.weight()
+	}
+
+	return weight
+}
+
+// depth returns the number of rings of the subdirectories of the node
+func (node *sunburstNode) depth() int {
+	depth := 0
+
+	for _, child := range node.children {
+		depth = max(depth, child.depth()+1)
+	}
+
+	return depth
+}
+
+// child returns the subdirectory of the node, added when missing
+func (node *sunburstNode) child(name string) *sunburstNode {
+	for _, child := range node.children {
+		if child.name == name {
+			return child
+		}
+	}
+
+	child := &sunburstNode{name: name, path: node.path + "/" + name}
+	node.children = append(node.children, child)
+
+	return child
+}
+
+// GenerateSunburst writes a self-contained HTML sunburst of the directory hierarchy of the module packages, colored
+// by level, so that packages whose level does not match their place in the directory structure stand out
+func GenerateSunburst(ctx context.Context, w io.Writer, r report.Report) error {
+	root := &sunburstNode{name: r.Module, path: r.Module}
+
+	for i := range r.Packages {
+		pkg := &r.Packages[i]
+
+		if pkg.Path == r.Module {
+			root.pkg = pkg
+			continue
+		}
+
+		rel, ok := strings.CutPrefix(pkg.Path, r.Module+"/")
+		if pkg.External || !ok {
+			continue
+		}
+
+		node := root
+		for _, name := range strings.Split(rel, "/") {
+			node = node.child(name)
+		}
+
+		node.pkg = pkg
+	}
+
+	var svg bytes.Buffer
+	out := &errWriter{ctx: ctx, w: &svg}
+
+	center := float64(sunburstSize) / 2
+	ringWidth := 0.0
+	if depth := root.depth(); depth > 0 {
+		ringWidth = (center - 10 - sunburstCenter) / float64(depth)
+	}
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="Helvetica, Arial, sans-serif" font-size="11">`+"\n", sunburstSize, sunburstSize, sunburstSize, sunburstSize)
+	out.printf(`<g><title>%v</title><circle cx="%v" cy="%v" r="%v" fill="%v" stroke="#ffffff" stroke-width="1.5"/></g>`+"\n",
+		html.EscapeString(sunburstTitle(root)), center, center, sunburstCenter, sunburstColor(root))
+
+	var ring func(node *sunburstNode, from float64, to float64, depth int)
+	ring = func(node *sunburstNode, from float64, to float64, depth int) {
+		weight := node.weight()
+		inner := sunburstCenter + float64(depth)*ringWidth
+		start := from
+
+		for _, child := range node.children {
+			end := start + (to-from)*float64(child.weight())/float64(weight)
+
+			out.printf(`<g><title>%v</title><path d="%v" fill="%v" stroke="#ffffff" stroke-width="1.5"/>`,
+				html.EscapeString(sunburstTitle(child)), arcPath(center, inner, inner+ringWidth, start, end), sunburstColor(child))
+
+			// labels are written horizontally in the middle of the segments long enough to hold them
+			mid, radius := (start+end)/2, inner+ringWidth/2
+			if label := fitLabel(child.name, (end-start)*radius); label != "" && ringWidth >= 16 {
+				out.printf(`<text x="%.1f" y="%.1f" text-anchor="middle" fill="#222">%v</text>`,
+					center+radius*math.Sin(mid), center-radius*math.Cos(mid)+4, html.EscapeString(label))
+			}
+
+			out.printf("</g>\n")
+
+			ring(child, start, end, depth+1)
+			start = end
+		}
+	}
+
+	// the share of the root package stays empty in the first ring
+	ring(root, 0, 2*math.Pi, 0)
+
+	out.printf("</svg>\n")
+
+	if out.err != nil {
+		return out.err
+	}
+
+	type legend struct {
+		Label string
+		Color template.CSS
+	}
+
+	var levels []legend
+
+	for _, level := range r.Levels {
+		levels = append(levels, legend{Label: levelLabel(level), Color: template.CSS(levelColor(level.Level))})
+	}
+
+	return sunburstTemplate.Execute(w, struct {
+		Report   report.Report
+		Style    template.CSS
+		Sunburst template.HTML
+		Levels   []legend
+	}{
+		Report:   r,
+		Style:    template.CSS(htmlReportStyle),
+		Sunburst: template.HTML(svg.String()),
+		Levels:   levels,
+	})
+}
+
+// sunburstColor returns the color of the level of a package, directories without a package are grey
+func sunburstColor(node *sunburstNode) string {
+	if node.pkg == nil {
+		return "#eeeeee"
+	}
+
+	return levelColor(node.pkg.Level)
+}
+
+// sunburstTitle describes a node of the sunburst
+func sunburstTitle(node *sunburstNode) string {
+	if node.pkg == nil {
+		return node.path + "\nno package"
+	}
+
+	if node.pkg.Layer != "" {
+		return fmt.Sprintf("%v\nlevel %v (%v)", node.path, node.pkg.Level, node.pkg.Layer)
+	}
+
+	return fmt.Sprintf("%v\nlevel %v", node.path, node.pkg.Level)
+}
+
+// arcPath returns the SVG path of a ring segment between two radii and two angles, clockwise from the top
+func arcPath(center, inner, outer, from, to float64) string {
+	// a full ring can not be drawn with a single arc
+	to = min(to, from+2*math.Pi-0.0001)
+
+	largeArc := 0
+	if to-from > math.Pi {
+		largeArc = 1
+	}
+
+	point := func(radius, angle float64) string {
+		return fmt.Sprintf("%.2f %.2f", center+radius*math.Sin(angle), center-radius*math.Cos(angle))
+	}
+
+	return fmt.Sprintf("M %v A %.2f %.2f 0 %v 1 %v L %v A %.2f %.2f 0 %v 0 %v Z",
+		point(outer, from), outer, outer, largeArc, point(outer, to),
+		point(inner, to), inner, inner, largeArc, point(inner, from))
+}
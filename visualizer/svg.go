@@ -0,0 +1,268 @@
+package visualizer
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+const (
+	svgMarginLeft  = 150
+	svgMarginTop   = 30
+	svgNodeHeight  = 36
+	svgNodeGap     = 30
+	svgRowGap      = 110
+	svgCharWidth   = 7
+	svgMinNodeSize = 80
+)
+
+// svgNode is a positioned package box
+type svgNode struct {
+	path  string
+	label string
+	x, y  int
+	width int
+}
+
+// svgLayout is a layered layout of the package graph, one row per level
+type svgLayout struct {
+	rows      [][]*svgNode
+	rowLabels []string
+	nodes     map[string]*svgNode
+	width     int
+	height    int
+}
+
+// layoutGraph places the packages of every level on a row and orders the rows with the barycenter
+// heuristic, to reduce edge crossings. Packages without a level are placed on an additional last row.
+func layoutGraph(ctx context.Context, r report.Report) svgLayout {
+	layout := svgLayout{nodes: make(map[string]*svgNode)}
+
+	addRow := func(label string, paths []string) {
+		var row []*svgNode
+
+		for _, path := range paths {
+			if _, ok := layout.nodes[path]; ok {
+				continue
+			}
+
+			label := nodeLabel(r.Module, path)
+			width := len(label)*svgCharWidth + 20
+
+			if width < svgMinNodeSize {
+				width = svgMinNodeSize
+			}
+
+			node := &svgNode{path: path, label: label, width: width}
+			layout.nodes[path] = node
+			row = append(row, node)
+		}
+
+		layout.rows = append(layout.rows, row)
+		layout.rowLabels = append(layout.rowLabels, label)
+	}
+
+	for _, level := range r.Levels {
+		addRow(levelLabel(level), level.Packages)
+	}
+
+	var unleveled []string
+
+	for _, pkg := range r.Packages {
+		if _, ok := layout.nodes[pkg.Path]; !ok {
+			unleveled = append(unleveled, pkg.Path)
+		}
+	}
+
+	if len(unleveled) > 0 {
+		addRow("No level", unleveled)
+	}
+
+	neighbors := make(map[string][]string)
+
+	for _, edge := range r.Edges {
+		neighbors[edge.From] = append(neighbors[edge.From], edge.To)
+		neighbors[edge.To] = append(neighbors[edge.To], edge.From)
+	}
+
+	// the sweeps are the slow part of large graphs, the layout is left unordered when ctx is done
+	for sweep := 0; sweep < 4 && ctx.Err() == nil; sweep++ {
+		for i := 1; i < len(layout.rows); i++ {
+			orderByBarycenter(layout.rows[i], layout.rows[i-1], neighbors)
+		}
+
+		for i := len(layout.rows) - 2; i >= 0; i-- {
+			orderByBarycenter(layout.rows[i], layout.rows[i+1], neighbors)
+		}
+	}
+
+	rowWidths := make([]int, len(layout.rows))
+	maxWidth := 0
+
+	for i, row := range layout.rows {
+		for j, node := range row {
+			if j > 0 {
+				rowWidths[i] += svgNodeGap
+			}
+
+			rowWidths[i] += node.width
+		}
+
+		if rowWidths[i] > maxWidth {
+			maxWidth = rowWidths[i]
+		}
+	}
+
+	for i, row := range layout.rows {
+		x := svgMarginLeft + (maxWidth-rowWidths[i])/2
+
+		for _, node := range row {
+			node.x = x
+			node.y = svgMarginTop + i*svgRowGap
+			x += node.width + svgNodeGap
+		}
+	}
+
+	layout.width = svgMarginLeft + maxWidth + 2*svgNodeGap
+	layout.height = svgMarginTop*2 + len(layout.rows)*svgRowGap
+
+	return layout
+}
+
+// orderByBarycenter sorts the nodes of row by the average position of their neighbors in the fixed row
+func orderByBarycenter(row []*svgNode, fixed []*svgNode, neighbors map[string][]string) {
+	position := make(map[string]int)
+
+	for i, node := range fixed {
+		position[node.path] = i
+	}
+
+	barycenter := make(map[string]float64)
+
+	for i, node := range row {
+		sum, count := 0, 0
+
+		for _, neighbor := range neighbors[node.path] {
+			if p, ok := position[neighbor]; ok {
+				sum += p
+				count++
+			}
+		}
+
+		// nodes without neighbors in the fixed row keep their position
+		barycenter[node.path] = float64(i)
+
+		if count > 0 {
+			barycenter[node.path] = float64(sum) / float64(count)
+		}
+	}
+
+	sort.SliceStable(row, func(i, j int) bool {
+		return barycenter[row[i].path] < barycenter[row[j].path]
+	})
+}
+
+// GenerateSVG renders the package graph as an SVG image using a layered layout, without external tools
+func GenerateSVG(ctx context.Context, w io.Writer, r report.Report) error {
+	out := &errWriter{ctx: ctx, w: w}
+	layout := layoutGraph(ctx, r)
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", layout.width, layout.height, layout.width, layout.height)
+	out.printf(`<defs>
+<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/></marker>
+<marker id="arrow-violation" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#d62728"/></marker>
+</defs>
+`)
+
+	for i, label := range layout.rowLabels {
+		y := svgMarginTop + i*svgRowGap
+
+		out.printf(`<rect x="5" y="%v" width="%v" height="%v" fill="#f4f6f8" rx="6"/>`+"\n", y-12, layout.width-10, svgNodeHeight+24)
+		out.printf(`<text x="15" y="%v" fill="#666" font-weight="bold">%v</text>`+"\n", y+svgNodeHeight/2+4, html.EscapeString(label))
+	}
+
+	// violations are drawn last to stay on top
+	for _, violations := range []bool{false, true} {
+		for _, edge := range r.Edges {
+			if edge.Violation != violations {
+				continue
+			}
+
+			from, to := layout.nodes[edge.From], layout.nodes[edge.To]
+
+			if from == nil || to == nil {
+				continue
+			}
+
+			stroke, marker := "#999", "arrow"
+
+			if edge.Violation {
+				stroke, marker = "#d62728", "arrow-violation"
+			}
+
+			out.printf(`<path d="%v" fill="none" stroke="%v" stroke-width="%v" marker-end="url(#%v)"><title>%v</title></path>`+"\n",
+				edgePath(from, to), stroke, edgeWidth(edge), marker, html.EscapeString(edgeTitle(edge)))
+		}
+	}
+
+	vendored := vendoredPackages(r)
+
+	for _, row := range layout.rows {
+		for _, node := range row {
+			dash := ""
+
+			if vendored[node.path] {
+				dash = ` stroke-dasharray="5,3"`
+			}
+
+			out.printf(`<g><title>%v</title><rect x="%v" y="%v" width="%v" height="%v" rx="8" fill="#ffffff" stroke="#4a6fa5" stroke-width="1.5"%v/>`,
+				html.EscapeString(node.path), node.x, node.y, node.width, svgNodeHeight, dash)
+			out.printf(`<text x="%v" y="%v" text-anchor="middle" fill="#222">%v</text></g>`+"\n",
+				node.x+node.width/2, node.y+svgNodeHeight/2+4, html.EscapeString(node.label))
+		}
+	}
+
+	out.printf("</svg>\n")
+
+	return out.err
+}
+
+// edgePath returns the SVG path of an import edge, the cubic curve of edgeCurve
+func edgePath(from, to *svgNode) string {
+	c := edgeCurve(from, to)
+
+	return fmt.Sprintf("M %v %v C %v %v, %v %v, %v %v", c[0].x, c[0].y, c[1].x, c[1].y, c[2].x, c[2].y, c[3].x, c[3].y)
+}
+
+// svgPoint is a point of the layout
+type svgPoint struct {
+	x, y int
+}
+
+// edgeCurve returns the start, control and end points of the cubic curve of an import edge. Edges go from the
+// bottom of the importing package to the top of the imported one, edges within a row are drawn as arcs above
+// the row.
+func edgeCurve(from, to *svgNode) [4]svgPoint {
+	fromX, toX := from.x+from.width/2, to.x+to.width/2
+
+	switch {
+	case from.y == to.y:
+		top := from.y - 40
+
+		return [4]svgPoint{{fromX, from.y}, {fromX, top}, {toX, top}, {toX, to.y}}
+	case from.y < to.y:
+		fromY, toY := from.y+svgNodeHeight, to.y
+		mid := (fromY + toY) / 2
+
+		return [4]svgPoint{{fromX, fromY}, {fromX, mid}, {toX, mid}, {toX, toY}}
+	default:
+		fromY, toY := from.y, to.y+svgNodeHeight
+		mid := (fromY + toY) / 2
+
+		return [4]svgPoint{{fromX, fromY}, {fromX, mid}, {toX, mid}, {toX, toY}}
+	}
+}
@@ -0,0 +1,222 @@
+package visualizer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// Colorings of the treemap
+const (
+	TreemapColorViolations = "violations"
+	TreemapColorLevel      = "level"
+)
+
+// TreemapColors are the colorings of the treemap
+var TreemapColors = []string{TreemapColorViolations, TreemapColorLevel}
+
+const (
+	treemapWidth  = 1200
+	treemapHeight = 700
+)
+
+var (
+	//go:embed assets/treemap.html
+	treemapSource string
+
+	treemapTemplate = template.Must(template.New("treemap").Parse(treemapSource))
+)
+
+// TreemapOptions are the options of the treemap
+type TreemapOptions struct {
+	// ColorBy is one of TreemapColors, TreemapColorViolations when empty
+	ColorBy string
+}
+
+// treemapTile is a package of the treemap with its rectangle
+type treemapTile struct {
+	pkg        report.Package
+	size       float64
+	violations int
+	x, y, w, h float64
+}
+
+// GenerateTreemap writes a self-contained HTML treemap of the packages sized by their lines of code and colored by
+// the number of violations of their imports
+func GenerateTreemap(ctx context.Context, w io.Writer, r report.Report) error {
+	return GenerateTreemapWithOptions(ctx, w, r, TreemapOptions{})
+}
+
+// GenerateTreemapWithOptions writes the treemap like GenerateTreemap, with the given options
+func GenerateTreemapWithOptions(ctx context.Context, w io.Writer, r report.Report, opts TreemapOptions) error {
+	violations := make(map[string]int)
+	maxViolations := 0
+
+	for _, violation := range r.Violations {
+		if !violation.Suppressed {
+			violations[violation.From]++
+			maxViolations = max(maxViolations, violations[violation.From])
+		}
+	}
+
+	var tiles []*treemapTile
+
+	// third-party modules have no files of the project
+	for _, pkg := range r.Packages {
+		if pkg.External || len(pkg.Files) == 0 {
+			continue
+		}
+
+		size := pkg.Lines
+		if size == 0 {
+			size = len(pkg.Files)
+		}
+
+		tiles = append(tiles, &treemapTile{pkg: pkg, size: float64(size), violations: violations[pkg.Path]})
+	}
+
+	sort.SliceStable(tiles, func(i, j int) bool { return tiles[i].size > tiles[j].size })
+
+	squarify(tiles, 0, 0, treemapWidth, treemapHeight)
+
+	var svg bytes.Buffer
+	out := &errWriter{ctx: ctx, w: &svg}
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="Helvetica, Arial, sans-serif" font-size="11">`+"\n", treemapWidth, treemapHeight, treemapWidth, treemapHeight)
+
+	for _, tile := range tiles {
+		fill := violationColor(tile.violations, maxViolations)
+		if opts.ColorBy == TreemapColorLevel {
+			fill = levelColor(tile.pkg.Level)
+		}
+
+		title := fmt.Sprintf("%v\n%v lines, %v files, level %v, fan-in %v, %v violations", tile.pkg.Path, tile.pkg.Lines, len(tile.pkg.Files), tile.pkg.Level, tile.pkg.FanIn, tile.violations)
+
+		out.printf(`<g><title>%v</title><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%v" stroke="#ffffff" stroke-width="1.5"/>`,
+			html.EscapeString(title), tile.x, tile.y, tile.w, tile.h, fill)
+
+		if label := fitLabel(nodeLabel(r.Module, tile.pkg.Path), tile.w); label != "" && tile.h >= 16 {
+			out.printf(`<text x="%.1f" y="%.1f" fill="#222">%v</text>`, tile.x+4, tile.y+13, html.EscapeString(label))
+		}
+
+		out.printf("</g>\n")
+	}
+
+	out.printf("</svg>\n")
+
+	if out.err != nil {
+		return out.err
+	}
+
+	colorBy := opts.ColorBy
+	if colorBy == "" {
+		colorBy = TreemapColorViolations
+	}
+
+	return treemapTemplate.Execute(w, struct {
+		Report  report.Report
+		Style   template.CSS
+		Treemap template.HTML
+		ColorBy string
+	}{
+		Report:  r,
+		Style:   template.CSS(htmlReportStyle),
+		Treemap: template.HTML(svg.String()),
+		ColorBy: colorBy,
+	})
+}
+
+// squarify lays the tiles, sorted by size with the largest first, out in the rectangle with the squarified
+// treemap algorithm: tiles are added to a row along the shorter side as long as that improves the worst aspect ratio
+func squarify(tiles []*treemapTile, x, y, w, h float64) {
+	total := 0.0
+
+	for _, tile := range tiles {
+		total += tile.size
+	}
+
+	if total == 0 {
+		return
+	}
+
+	// areas of the tiles, in the unit of the rectangle
+	scale := w * h / total
+
+	for len(tiles) > 0 {
+		side := min(w, h)
+		n := 1
+
+		for n < len(tiles) && worstRatio(tiles[:n+1], side, scale) <= worstRatio(tiles[:n], side, scale) {
+			n++
+		}
+
+		row := tiles[:n]
+		tiles = tiles[n:]
+
+		area := 0.0
+		for _, tile := range row {
+			area += tile.size * scale
+		}
+
+		if w >= h {
+			// a column at the left
+			width := area / h
+			offset := y
+
+			for _, tile := range row {
+				tile.x, tile.y, tile.w, tile.h = x, offset, width, tile.size*scale/width
+				offset += tile.h
+			}
+
+			x, w = x+width, w-width
+		} else {
+			// a row at the top
+			height := area / w
+			offset := x
+
+			for _, tile := range row {
+				tile.x, tile.y, tile.w, tile.h = offset, y, tile.size*scale/height, height
+				offset += tile.w
+			}
+
+			y, h = y+height, h-height
+		}
+	}
+}
+
+// worstRatio returns the largest aspect ratio of the tiles of a row along side
+func worstRatio(row []*treemapTile, side float64, scale float64) float64 {
+	sum, largest, smallest := 0.0, 0.0, row[0].size*scale
+
+	for _, tile := range row {
+		area := tile.size * scale
+		sum += area
+		largest = max(largest, area)
+		smallest = min(smallest, area)
+	}
+
+	return max(side*side*largest/(sum*sum), sum*sum/(side*side*smallest))
+}
+
+// fitLabel returns the label cut to the width of a tile, empty when not even a few characters fit
+func fitLabel(label string, width float64) string {
+	// about 6.5 pixels per character at the font size of the treemap
+	fit := int((width - 8) / 6.5)
+
+	if fit < 3 {
+		return ""
+	}
+
+	if runes := []rune(label); len(runes) > fit {
+		return string(runes[:fit-1]) + "…"
+	}
+
+	return label
+}
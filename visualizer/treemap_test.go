@@ -0,0 +1,36 @@
+package visualizer
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_squarify(t *testing.T) {
+	var tiles []*treemapTile
+
+	for _, size := range []float64{6, 6, 4, 3, 2, 2, 1} {
+		tiles = append(tiles, &treemapTile{size: size})
+	}
+
+	squarify(tiles, 0, 0, 600, 400)
+
+	for _, tile := range tiles {
+		area := tile.size * 600 * 400 / 24
+
+		if math.Abs(tile.w*tile.h-area) > 0.01 {
+			t.Errorf("tile of size %v has an area of %v, want %v", tile.size, tile.w*tile.h, area)
+		}
+
+		if tile.x < 0 || tile.y < 0 || tile.x+tile.w > 600.01 || tile.y+tile.h > 400.01 {
+			t.Errorf("tile of size %v at %v,%v %vx%v is outside of the treemap", tile.size, tile.x, tile.y, tile.w, tile.h)
+		}
+	}
+
+	for i, a := range tiles {
+		for _, b := range tiles[i+1:] {
+			if a.x+a.w > b.x+0.01 && b.x+b.w > a.x+0.01 && a.y+a.h > b.y+0.01 && b.y+b.h > a.y+0.01 {
+				t.Errorf("tiles of size %v and %v overlap", a.size, b.size)
+			}
+		}
+	}
+}
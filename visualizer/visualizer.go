@@ -0,0 +1,114 @@
+// Package visualizer renders the package graph of a report as diagrams. The generators take a context,
+// they stop with its error when it is done.
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/report"
+)
+
+// levelLabel returns the title of a level, with its layer name if layers are declared
+func levelLabel(level report.Level) string {
+	if level.Layer != "" {
+		return fmt.Sprintf("Level %v (%v)", level.Level, level.Layer)
+	}
+
+	return fmt.Sprintf("Level %v", level.Level)
+}
+
+// nodeLabel shortens package paths of the module for display
+func nodeLabel(module string, path string) string {
+	if path == module {
+		return path
+	}
+
+	return strings.TrimPrefix(path, module+"/")
+}
+
+// vendoredPackages returns the set of vendored packages of the report, drawn dashed
+func vendoredPackages(r report.Report) map[string]bool {
+	vendored := make(map[string]bool)
+
+	for _, pkg := range r.Packages {
+		if pkg.Vendored {
+			vendored[pkg.Path] = true
+		}
+	}
+
+	return vendored
+}
+
+// edgeWidth returns the line width of an edge, growing with the number of importing files so that heavily used
+// dependencies stand out, violations are drawn one wider
+func edgeWidth(edge report.Edge) float64 {
+	width := 1 + math.Min(math.Log2(float64(max(edge.Weight, 1))), 4)
+
+	if edge.Violation {
+		width++
+	}
+
+	return math.Round(width*10) / 10
+}
+
+// edgeTitle describes an edge with the number of importing files
+func edgeTitle(edge report.Edge) string {
+	if edge.Weight > 1 {
+		return fmt.Sprintf("%v imports %v (%v files)", edge.From, edge.To, edge.Weight)
+	}
+
+	return edge.From + " imports " + edge.To
+}
+
+// levelColors are the fill colors of the levels, repeated for deeper graphs
+var levelColors = []string{"#aec7e8", "#ffbb78", "#98df8a", "#c5b0d5", "#c49c94", "#f7b6d2", "#dbdb8d", "#9edae5", "#c7c7c7", "#ff9896"}
+
+// levelColor returns the fill color of a level
+func levelColor(level int) string {
+	return levelColors[level%len(levelColors)]
+}
+
+// violationColor returns a fill color from green without violations to red for the most violations
+func violationColor(violations int, most int) string {
+	if violations == 0 || most == 0 {
+		return "#c7e9c0"
+	}
+
+	// from light red for a single violation to the red of the violating edges
+	t := float64(violations) / float64(most)
+
+	return fmt.Sprintf("#%02x%02x%02x", 252-int(t*38), 187-int(t*148), 161-int(t*121))
+}
+
+// topDirectory returns the top-level directory of a package of the module, empty for the module root and
+// packages outside of the module
+func topDirectory(module string, path string) string {
+	if !strings.HasPrefix(path, module+"/") {
+		return ""
+	}
+
+	dir, _, _ := strings.Cut(strings.TrimPrefix(path, module+"/"), "/")
+
+	return dir
+}
+
+// groupByDirectory groups packages by their top-level directory, the directories are returned in the order of
+// their first package
+func groupByDirectory(module string, packages []string) ([]string, map[string][]string) {
+	var directories []string
+	byDirectory := make(map[string][]string)
+
+	for _, pkg := range packages {
+		dir := topDirectory(module, pkg)
+
+		if _, seen := byDirectory[dir]; !seen {
+			directories = append(directories, dir)
+		}
+
+		byDirectory[dir] = append(byDirectory[dir], pkg)
+	}
+
+	return directories, byDirectory
+}
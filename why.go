@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/audi70r/uncle-bob/checker"
+	"github.com/audi70r/uncle-bob/utilities/clog"
+)
+
+// runWhy prints the import chains through which a package of the project depends on another package
+func runWhy(args []string) {
+	flagSet := flag.NewFlagSet("uncle-bob why", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), "Usage: uncle-bob why [flags] <package> <dependency>")
+		flagSet.PrintDefaults()
+	}
+
+	var af analysisFlags
+	af.register(flagSet)
+
+	all := flagSet.Bool("all", false, "print every import chain instead of the shortest one")
+	limit := flagSet.Int("limit", 100, "maximum number of chains printed with -all, 0 for no limit")
+
+	parseFlags(flagSet, args)
+
+	if err := af.validate(); err != nil {
+		clog.Error(err.Error())
+		exit(exitConfigError)
+	}
+
+	if flagSet.NArg() != 2 {
+		flagSet.Usage()
+		exit(exitConfigError)
+	}
+
+	PrintAA()
+
+	handleInterrupts()
+
+	ctx, cancel := af.withTimeout(context.Background())
+	defer cancel()
+
+	workDir := locateProject(&af)
+	cfg := loadConfig(workDir, af.configPath)
+
+	af.setupCache()
+
+	// third-party modules are always mapped, they are the usual unwanted dependencies
+	opts := af.mapOptions(cfg)
+	opts.External = true
+
+	clog.SetOutput(io.Discard)
+	packageMap, err := mapModule(ctx, workDir, opts)
+	clog.SetOutput(console)
+
+	if err != nil {
+		clog.Error(err.Error())
+		exit(exitAnalysisError)
+	}
+
+	if checker.Interrupted() {
+		clog.Warning("Analysis was interrupted, the results are partial")
+		exit(exitInterrupted)
+	}
+
+	var pkgs [2]string
+
+	for i, name := range flagSet.Args() {
+		pkg, ok := checker.ResolvePackage(packageMap, name)
+
+		if !ok {
+			clog.Error(fmt.Sprintf("package %v is not part of the import graph", name))
+			exit(exitConfigError)
+		}
+
+		pkgs[i] = pkg
+	}
+
+	var chains [][]string
+
+	if *all {
+		chains = checker.ImportChains(packageMap, pkgs[0], pkgs[1], *limit)
+	} else if chain := checker.ShortestImportChain(packageMap, pkgs[0], pkgs[1]); chain != nil {
+		chains = [][]string{chain}
+	}
+
+	if len(chains) == 0 {
+		fmt.Fprintf(console, "%v does not depend on %v\n", shortPath(pkgs[0]), shortPath(pkgs[1]))
+		return
+	}
+
+	for _, chain := range chains {
+		shortPaths := make([]string, 0, len(chain))
+
+		for _, pkg := range chain {
+			shortPaths = append(shortPaths, shortPath(pkg))
+		}
+
+		fmt.Fprintln(console, strings.Join(shortPaths, " --> "))
+	}
+
+	if *all && *limit > 0 && len(chains) == *limit {
+		clog.Warning(fmt.Sprintf("Only the first %v chains are printed, use -limit to print more", *limit))
+	}
+}
+
+// shortPath trims the module path from a package import path for display
+func shortPath(importPath string) string {
+	if importPath == checker.ModPath {
+		return "/"
+	}
+
+	return strings.TrimPrefix(importPath, checker.ModPath)
+}